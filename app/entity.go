@@ -4,6 +4,8 @@ import (
 	"context"
 	"crudly/errs"
 	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
 	"crudly/util/result"
 	"fmt"
 
@@ -17,16 +19,24 @@ type entityFetcher interface {
 		tableName model.TableName,
 		tableSchema model.TableSchema,
 		id model.EntityId,
+		fieldProjection optional.O[model.FieldProjection],
 	) result.R[model.Entity]
 
 	FetchEntities(
 		projectId model.ProjectId,
 		table model.TableName,
 		tableSchema model.TableSchema,
-		entityFilter model.EntityFilter,
+		filterExpression model.EntityFilterExpression,
 		entityOrders model.EntityOrders,
 		paginationParams model.PaginationParams,
+		fieldProjection optional.O[model.FieldProjection],
 	) result.R[model.Entities]
+
+	ExpandEntities(
+		projectId model.ProjectId,
+		entities model.Entities,
+		fieldExpansions model.FieldExpansions,
+	) error
 }
 
 type entityCreator interface {
@@ -43,6 +53,24 @@ type entityCreator interface {
 		ids []model.EntityId,
 		entities model.Entities,
 	) error
+
+	UpsertEntity(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		tableSchema model.TableSchema,
+		upsertKey model.UpsertKey,
+		id model.EntityId,
+		entity model.Entity,
+	) result.R[model.UpsertedEntity]
+
+	UpsertEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		tableSchema model.TableSchema,
+		upsertKey model.UpsertKey,
+		ids []model.EntityId,
+		entities model.Entities,
+	) result.R[[]model.UpsertedEntity]
 }
 
 type entityUpdater interface {
@@ -71,7 +99,13 @@ type entityCountFetcher interface {
 	FetchTotalEntityCount(
 		projectId model.ProjectId,
 		tableName model.TableName,
-		entityFilter model.EntityFilter,
+		filterExpression model.EntityFilterExpression,
+	) result.R[uint]
+
+	FetchEstimatedEntityCount(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		filterExpression model.EntityFilterExpression,
 	) result.R[uint]
 }
 
@@ -84,8 +118,8 @@ type partialEntityValidator interface {
 }
 
 type entityFilterValidator interface {
-	ValidateEntityFilter(
-		entityFilter model.EntityFilter,
+	ValidateEntityFilterExpression(
+		filterExpression *model.EntityFilterExpression,
 		tableSchema model.TableSchema,
 	) error
 }
@@ -97,17 +131,122 @@ type entityOrderValidator interface {
 	) error
 }
 
+type entityCursorValidator interface {
+	ValidateEntityCursor(
+		entityCursor model.EntityCursor,
+		entityOrders model.EntityOrders,
+		tableSchema model.TableSchema,
+	) error
+}
+
+type entityAggregationFetcher interface {
+	FetchEntityAggregation(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		tableSchema model.TableSchema,
+		filterExpression model.EntityFilterExpression,
+		groupBy []model.FieldName,
+		entityAggregations model.EntityAggregations,
+		entityHistogram optional.O[model.EntityHistogram],
+	) result.R[model.EntityAggregationResults]
+
+	FetchFieldValueCounts(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		fieldName model.FieldName,
+		fieldDefinition model.FieldDefinition,
+		filterExpression model.EntityFilterExpression,
+		order model.FieldValuesOrderType,
+		paginationParams model.PaginationParams,
+	) result.R[model.FieldValueCounts]
+}
+
+type entityAggregationValidator interface {
+	ValidateEntityAggregation(
+		filterExpression model.EntityFilterExpression,
+		groupBy []model.FieldName,
+		entityAggregations model.EntityAggregations,
+		entityHistogram optional.O[model.EntityHistogram],
+		tableSchema model.TableSchema,
+	) error
+
+	ValidateFieldValues(
+		fieldName model.FieldName,
+		tableSchema model.TableSchema,
+	) error
+}
+
+type fieldProjectionValidator interface {
+	ValidateFieldProjection(
+		fieldProjection model.FieldProjection,
+		tableSchema model.TableSchema,
+	) error
+}
+
+type upsertKeyValidator interface {
+	ValidateUpsertKey(
+		upsertKey model.UpsertKey,
+		tableSchema model.TableSchema,
+	) error
+
+	ValidateUpsertedEntity(
+		upsertKey model.UpsertKey,
+		entity model.Entity,
+	) error
+}
+
+type entityExpansionValidator interface {
+	ValidateEntityExpansion(entityExpansion model.EntityExpansion) error
+
+	ValidateExpandedField(
+		fieldName model.FieldName,
+		tableSchema model.TableSchema,
+		fieldProjection optional.O[model.FieldProjection],
+	) error
+}
+
+type entityLinker interface {
+	CreateLink(
+		projectId model.ProjectId,
+		linkTableName model.TableName,
+		linkFields model.LinkFields,
+		id model.EntityId,
+		ownId model.EntityId,
+		otherId model.EntityId,
+	) result.R[bool]
+
+	DeleteLink(
+		projectId model.ProjectId,
+		linkTableName model.TableName,
+		linkFields model.LinkFields,
+		ownId model.EntityId,
+		otherId model.EntityId,
+	) error
+}
+
+type tableIndexGetter interface {
+	GetTableIndexes(projectId model.ProjectId, tableName model.TableName) result.R[model.TableIndexes]
+}
+
 type entityManager struct {
-	entityFetcher          entityFetcher
-	entityCreator          entityCreator
-	entityUpdater          entityUpdater
-	entityDeleter          entityDeleter
-	entityCountFetcher     entityCountFetcher
-	tableSchemaGetter      tableSchemaGetter
-	entityValidator        entityValidator
-	partialEntityValidator partialEntityValidator
-	entityFilterValidator  entityFilterValidator
-	entityOrderValidator   entityOrderValidator
+	entityFetcher              entityFetcher
+	entityCreator              entityCreator
+	entityUpdater              entityUpdater
+	entityDeleter              entityDeleter
+	entityCountFetcher         entityCountFetcher
+	tableSchemaGetter          tableSchemaGetter
+	entityValidator            entityValidator
+	partialEntityValidator     partialEntityValidator
+	entityFilterValidator      entityFilterValidator
+	entityOrderValidator       entityOrderValidator
+	entityCursorValidator      entityCursorValidator
+	fieldProjectionValidator   fieldProjectionValidator
+	entityAggregationFetcher   entityAggregationFetcher
+	entityAggregationValidator entityAggregationValidator
+	upsertKeyValidator         upsertKeyValidator
+	entityExpansionValidator   entityExpansionValidator
+	entityLinker               entityLinker
+	tableIndexGetter           tableIndexGetter
 }
 
 func NewEntityManager(
@@ -121,6 +260,14 @@ func NewEntityManager(
 	partialEntityValidator partialEntityValidator,
 	entityFilterValidator entityFilterValidator,
 	entityOrderValidator entityOrderValidator,
+	entityCursorValidator entityCursorValidator,
+	fieldProjectionValidator fieldProjectionValidator,
+	entityAggregationFetcher entityAggregationFetcher,
+	entityAggregationValidator entityAggregationValidator,
+	upsertKeyValidator upsertKeyValidator,
+	entityExpansionValidator entityExpansionValidator,
+	entityLinker entityLinker,
+	tableIndexGetter tableIndexGetter,
 ) entityManager {
 	return entityManager{
 		entityFetcher,
@@ -133,6 +280,14 @@ func NewEntityManager(
 		partialEntityValidator,
 		entityFilterValidator,
 		entityOrderValidator,
+		entityCursorValidator,
+		fieldProjectionValidator,
+		entityAggregationFetcher,
+		entityAggregationValidator,
+		upsertKeyValidator,
+		entityExpansionValidator,
+		entityLinker,
+		tableIndexGetter,
 	}
 }
 
@@ -140,25 +295,52 @@ func (e *entityManager) GetEntity(
 	projectId model.ProjectId,
 	tableName model.TableName,
 	id model.EntityId,
+	fieldProjection optional.O[model.FieldProjection],
+	entityExpansion model.EntityExpansion,
 ) result.R[model.Entity] {
+	err := e.entityExpansionValidator.ValidateEntityExpansion(entityExpansion)
+
+	if err != nil {
+		return result.Err[model.Entity](errs.NewInvalidEntityExpansionError(err))
+	}
+
 	tableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, tableName)
 
 	if tableSchemaResult.IsErr() {
 		return result.Err[model.Entity](fmt.Errorf("error getting table schema: %w", tableSchemaResult.UnwrapErr()))
 	}
 
+	fieldExpansionResult := e.getFieldExpansion(
+		projectId,
+		tableName,
+		tableSchemaResult.Unwrap(),
+		entityExpansion,
+		fieldProjection,
+	)
+
+	if fieldExpansionResult.IsErr() {
+		return result.Err[model.Entity](fieldExpansionResult.UnwrapErr())
+	}
+
+	fieldExpansion := fieldExpansionResult.Unwrap()
+
 	entityResult := e.entityFetcher.FetchEntity(
 		projectId,
 		tableName,
 		tableSchemaResult.Unwrap(),
 		id,
+		fieldExpansion.FieldProjection,
 	)
 
 	if entityResult.IsErr() {
-		err := entityResult.UnwrapErr()
+		return entityResult
+	}
 
-		if _, ok := err.(errs.EntityNotFoundError); ok {
-			return entityResult
+	if len(fieldExpansion.Expansions) > 0 {
+		err := e.entityFetcher.ExpandEntities(projectId, model.Entities{entityResult.Unwrap()}, fieldExpansion.Expansions)
+
+		if err != nil {
+			return result.Errf[model.Entity]("error expanding entity: %w", err)
 		}
 	}
 
@@ -171,6 +353,61 @@ func (e *entityManager) GetEntities(
 	entityFilter model.EntityFilter,
 	entityOrders model.EntityOrders,
 	paginationParams model.PaginationParams,
+	countType model.EntityCountType,
+	fieldProjection optional.O[model.FieldProjection],
+	entityExpansion model.EntityExpansion,
+) result.R[model.GetEntitiesResponse] {
+	return e.QueryEntities(
+		projectId,
+		tableName,
+		entityFilter.ToExpression(),
+		entityOrders,
+		paginationParams,
+		countType,
+		fieldProjection,
+		entityExpansion,
+	)
+}
+
+func (e *entityManager) QueryEntities(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	filterExpression model.EntityFilterExpression,
+	entityOrders model.EntityOrders,
+	paginationParams model.PaginationParams,
+	countType model.EntityCountType,
+	fieldProjection optional.O[model.FieldProjection],
+	entityExpansion model.EntityExpansion,
+) result.R[model.GetEntitiesResponse] {
+	err := e.entityExpansionValidator.ValidateEntityExpansion(entityExpansion)
+
+	if err != nil {
+		return result.Err[model.GetEntitiesResponse](errs.NewInvalidEntityExpansionError(err))
+	}
+
+	return e.queryEntities(
+		projectId,
+		tableName,
+		filterExpression,
+		entityOrders,
+		paginationParams,
+		countType,
+		fieldProjection,
+		entityExpansion,
+	)
+}
+
+// Queries entities without validating the expansion paths, so that callers
+// expanding fields of their own can validate only the paths they were given
+func (e *entityManager) queryEntities(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	filterExpression model.EntityFilterExpression,
+	entityOrders model.EntityOrders,
+	paginationParams model.PaginationParams,
+	countType model.EntityCountType,
+	fieldProjection optional.O[model.FieldProjection],
+	entityExpansion model.EntityExpansion,
 ) result.R[model.GetEntitiesResponse] {
 	tableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, tableName)
 
@@ -180,7 +417,7 @@ func (e *entityManager) GetEntities(
 
 	tableSchema := tableSchemaResult.Unwrap()
 
-	err := e.entityFilterValidator.ValidateEntityFilter(entityFilter, tableSchema)
+	err := e.entityFilterValidator.ValidateEntityFilterExpression(&filterExpression, tableSchema)
 
 	if err != nil {
 		return result.Err[model.GetEntitiesResponse](errs.NewInvalidEntityFilterError(err))
@@ -208,17 +445,74 @@ func (e *entityManager) GetEntities(
 		})
 	}
 
+	if paginationParams.Cursor.IsSome() {
+		if paginationParams.Offset != 0 {
+			return result.Err[model.GetEntitiesResponse](
+				errs.NewInvalidEntityCursorError(fmt.Errorf("offset can't be used with a cursor")),
+			)
+		}
+
+		err = e.entityCursorValidator.ValidateEntityCursor(paginationParams.Cursor.Unwrap(), entityOrders, tableSchema)
+
+		if err != nil {
+			return result.Err[model.GetEntitiesResponse](errs.NewInvalidEntityCursorError(err))
+		}
+	}
+
+	fieldExpansionResult := e.getFieldExpansion(
+		projectId,
+		tableName,
+		tableSchema,
+		entityExpansion,
+		fieldProjection,
+	)
+
+	if fieldExpansionResult.IsErr() {
+		return result.Err[model.GetEntitiesResponse](fieldExpansionResult.UnwrapErr())
+	}
+
+	fieldExpansion := fieldExpansionResult.Unwrap()
+	fieldProjection = fieldExpansion.FieldProjection
+
+	// Order fields are always fetched so that the next cursor can be built,
+	// they're removed again afterwards if they weren't asked for
+	fetchFieldProjection := fieldProjection
+
+	if fieldProjection.IsSome() {
+		fetchFieldProjection = optional.Some(getOrderedFieldProjection(fieldProjection.Unwrap(), entityOrders))
+	}
+
+	// One more entity than requested is fetched to find out whether there is
+	// a next page to give a cursor for
+	fetchPaginationParams := paginationParams
+	fetchPaginationParams.Limit = paginationParams.Limit + 1
+
 	entityCount := uint(0)
 	entities := model.Entities{}
 
 	g, _ := errgroup.WithContext(context.Background())
 
 	g.Go(func() error {
-		entityCountResult := e.entityCountFetcher.FetchTotalEntityCount(
-			projectId,
-			tableName,
-			entityFilter,
-		)
+		var entityCountResult result.R[uint]
+
+		switch countType {
+		case model.EntityCountTypeExact:
+			entityCountResult = e.entityCountFetcher.FetchTotalEntityCount(
+				projectId,
+				tableName,
+				filterExpression,
+			)
+		case model.EntityCountTypeEstimated:
+			entityCountResult = e.entityCountFetcher.FetchEstimatedEntityCount(
+				projectId,
+				tableName,
+				filterExpression,
+			)
+		case model.EntityCountTypeNone:
+			return nil
+		default:
+			panic(fmt.Sprintf("invalid entity count type has entered the system: %v", countType))
+		}
 
 		if entityCountResult.IsErr() {
 			return entityCountResult.UnwrapErr()
@@ -234,9 +528,10 @@ func (e *entityManager) GetEntities(
 			projectId,
 			tableName,
 			tableSchema,
-			entityFilter,
+			filterExpression,
 			entityOrders,
-			paginationParams,
+			fetchPaginationParams,
+			fetchFieldProjection,
 		)
 
 		if entitiesResult.IsErr() {
@@ -252,14 +547,86 @@ func (e *entityManager) GetEntities(
 		return result.Err[model.GetEntitiesResponse](err)
 	}
 
+	entities, nextCursor := getEntitiesPage(entities, paginationParams.Limit, entityOrders)
+
+	if fieldProjection.IsSome() {
+		for _, entity := range entities {
+			for fieldName := range entity {
+				if fieldName != "id" && !util.Contains(fieldProjection.Unwrap(), fieldName) {
+					delete(entity, fieldName)
+				}
+			}
+		}
+	}
+
+	if len(fieldExpansion.Expansions) > 0 {
+		err = e.entityFetcher.ExpandEntities(projectId, entities, fieldExpansion.Expansions)
+
+		if err != nil {
+			return result.Errf[model.GetEntitiesResponse]("error expanding entities: %w", err)
+		}
+	}
+
 	return result.Ok(model.GetEntitiesResponse{
 		Entities:   entities,
+		CountType:  countType,
 		TotalCount: entityCount,
 		Limit:      uint(paginationParams.Limit),
 		Offset:     uint(paginationParams.Offset),
+		Cursor:     paginationParams.Cursor,
+		NextCursor: nextCursor,
 	})
 }
 
+func getOrderedFieldProjection(
+	fieldProjection model.FieldProjection,
+	entityOrders model.EntityOrders,
+) model.FieldProjection {
+	orderedFieldProjection := append(model.FieldProjection{}, fieldProjection...)
+
+	for _, entityOrder := range entityOrders {
+		if !util.Contains(orderedFieldProjection, entityOrder.FieldName) {
+			orderedFieldProjection = append(orderedFieldProjection, entityOrder.FieldName)
+		}
+	}
+
+	return orderedFieldProjection
+}
+
+// Cuts entities fetched with a limit one higher than requested back down to
+// the requested limit, giving a cursor to the next page if that dropped any
+func getEntitiesPage(
+	entities model.Entities,
+	limit model.PaginationLimit,
+	entityOrders model.EntityOrders,
+) (model.Entities, optional.O[model.EntityCursor]) {
+	if len(entities) <= int(limit) {
+		return entities, optional.None[model.EntityCursor]()
+	}
+
+	entities = entities[:limit]
+
+	if len(entities) == 0 {
+		return entities, optional.None[model.EntityCursor]()
+	}
+
+	return entities, optional.Some(getEntityCursor(entities[len(entities)-1], entityOrders))
+}
+
+func getEntityCursor(entity model.Entity, entityOrders model.EntityOrders) model.EntityCursor {
+	entityCursor := model.EntityCursor{}
+
+	for _, entityOrder := range entityOrders {
+		entityCursor = append(entityCursor, model.EntityCursorKey{
+			FieldName: entityOrder.FieldName,
+			Type:      entityOrder.Type,
+			Value:     entity[entityOrder.FieldName],
+		})
+	}
+
+	return entityCursor
+}
+
 func (e *entityManager) CreateEntityWithId(
 	projectId model.ProjectId,
 	tableName model.TableName,
@@ -292,6 +659,14 @@ func (e *entityManager) CreateEntityWithId(
 			return err
 		}
 
+		if _, ok := err.(errs.UniqueViolationError); ok {
+			return err
+		}
+
+		if _, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			return err
+		}
+
 		return fmt.Errorf("error creating entity: %w", err)
 	}
 
@@ -351,12 +726,151 @@ func (e *entityManager) CreateEntities(
 	err := e.entityCreator.CreateEntities(projectId, tableName, entityIds, entities)
 
 	if err != nil {
+		if _, ok := err.(errs.UniqueViolationError); ok {
+			return err
+		}
+
+		if _, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			return err
+		}
+
 		return fmt.Errorf("error creating entities: %w", err)
 	}
 
 	return nil
 }
 
+func (e *entityManager) UpsertEntity(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	upsertKey model.UpsertKey,
+	entity model.Entity,
+) result.R[model.UpsertedEntity] {
+	tableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		return result.Errf[model.UpsertedEntity]("error getting table schema: %w", tableSchemaResult.UnwrapErr())
+	}
+
+	tableSchema := tableSchemaResult.Unwrap()
+
+	err := e.upsertKeyValidator.ValidateUpsertKey(upsertKey, tableSchema)
+
+	if err != nil {
+		return result.Err[model.UpsertedEntity](errs.NewInvalidUpsertKeyError(err))
+	}
+
+	err = e.entityValidator.ValidateEntity(entity, tableSchema)
+
+	if err == nil {
+		err = e.upsertKeyValidator.ValidateUpsertedEntity(upsertKey, entity)
+	}
+
+	if err != nil {
+		return result.Err[model.UpsertedEntity](errs.NewInvalidEntityError(err))
+	}
+
+	upsertedEntityResult := e.entityCreator.UpsertEntity(
+		projectId,
+		tableName,
+		tableSchema,
+		upsertKey,
+		model.EntityId(uuid.New()),
+		entity,
+	)
+
+	if upsertedEntityResult.IsErr() {
+		err := upsertedEntityResult.UnwrapErr()
+
+		if _, ok := err.(errs.UniqueViolationError); ok {
+			return upsertedEntityResult
+		}
+
+		if _, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			return upsertedEntityResult
+		}
+
+		if _, ok := err.(errs.InvalidUpsertKeyError); ok {
+			return upsertedEntityResult
+		}
+
+		return result.Errf[model.UpsertedEntity]("error upserting entity: %w", err)
+	}
+
+	return upsertedEntityResult
+}
+
+func (e *entityManager) UpsertEntities(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	upsertKey model.UpsertKey,
+	entities model.Entities,
+) result.R[[]model.UpsertedEntity] {
+	tableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		return result.Errf[[]model.UpsertedEntity]("error getting table schema: %w", tableSchemaResult.UnwrapErr())
+	}
+
+	tableSchema := tableSchemaResult.Unwrap()
+
+	err := e.upsertKeyValidator.ValidateUpsertKey(upsertKey, tableSchema)
+
+	if err != nil {
+		return result.Err[[]model.UpsertedEntity](errs.NewInvalidUpsertKeyError(err))
+	}
+
+	for index, entity := range entities {
+		err := e.entityValidator.ValidateEntity(entity, tableSchema)
+
+		if err == nil {
+			err = e.upsertKeyValidator.ValidateUpsertedEntity(upsertKey, entity)
+		}
+
+		if err != nil {
+			return result.Err[[]model.UpsertedEntity](errs.NewInvalidEntityError(
+				fmt.Errorf("error with entity at index %d: %w", index, err),
+			))
+		}
+	}
+
+	// Only used for the entities that end up being inserted
+	entityIds := make([]model.EntityId, len(entities))
+
+	for index := range entities {
+		entityIds[index] = model.EntityId(uuid.New())
+	}
+
+	upsertedEntitiesResult := e.entityCreator.UpsertEntities(
+		projectId,
+		tableName,
+		tableSchema,
+		upsertKey,
+		entityIds,
+		entities,
+	)
+
+	if upsertedEntitiesResult.IsErr() {
+		err := upsertedEntitiesResult.UnwrapErr()
+
+		if _, ok := err.(errs.UniqueViolationError); ok {
+			return upsertedEntitiesResult
+		}
+
+		if _, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			return upsertedEntitiesResult
+		}
+
+		if _, ok := err.(errs.InvalidUpsertKeyError); ok {
+			return upsertedEntitiesResult
+		}
+
+		return result.Errf[[]model.UpsertedEntity]("error upserting entities: %w", err)
+	}
+
+	return upsertedEntitiesResult
+}
+
 func (e *entityManager) UpdateEntity(
 	projectId model.ProjectId,
 	tableName model.TableName,
@@ -402,6 +916,10 @@ func (e *entityManager) DeleteEntity(
 			return err
 		}
 
+		if _, ok := err.(errs.EntityReferencedError); ok {
+			return err
+		}
+
 		return fmt.Errorf("error deleting entity: %w", err)
 	}
 
@@ -419,7 +937,9 @@ func (e *entityManager) GetTotalEntityCount(
 		return result.Errf[uint]("error getting table schema: %w", tableSchemaResult.UnwrapErr())
 	}
 
-	err := e.entityFilterValidator.ValidateEntityFilter(entityFilter, tableSchemaResult.Unwrap())
+	filterExpression := entityFilter.ToExpression()
+
+	err := e.entityFilterValidator.ValidateEntityFilterExpression(&filterExpression, tableSchemaResult.Unwrap())
 
 	if err != nil {
 		return result.Err[uint](errs.NewInvalidEntityFilterError(err))
@@ -428,6 +948,116 @@ func (e *entityManager) GetTotalEntityCount(
 	return e.entityCountFetcher.FetchTotalEntityCount(
 		projectId,
 		tableName,
-		entityFilter,
+		filterExpression,
+	)
+}
+
+func (e *entityManager) AggregateEntities(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	entityAggregationQuery model.EntityAggregationQuery,
+) result.R[model.EntityAggregationResponse] {
+	tableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		return result.Errf[model.EntityAggregationResponse]("error getting table schema: %w", tableSchemaResult.UnwrapErr())
+	}
+
+	tableSchema := tableSchemaResult.Unwrap()
+
+	filterExpression := entityAggregationQuery.Filter.ToExpression()
+
+	err := e.entityFilterValidator.ValidateEntityFilterExpression(&filterExpression, tableSchema)
+
+	if err != nil {
+		return result.Err[model.EntityAggregationResponse](errs.NewInvalidEntityFilterError(err))
+	}
+
+	err = e.entityAggregationValidator.ValidateEntityAggregation(
+		filterExpression,
+		entityAggregationQuery.GroupBy,
+		entityAggregationQuery.Aggregations,
+		entityAggregationQuery.Histogram,
+		tableSchema,
+	)
+
+	if err != nil {
+		return result.Err[model.EntityAggregationResponse](errs.NewInvalidEntityAggregationError(err))
+	}
+
+	entityAggregationResultsResult := e.entityAggregationFetcher.FetchEntityAggregation(
+		projectId,
+		tableName,
+		tableSchema,
+		filterExpression,
+		entityAggregationQuery.GroupBy,
+		entityAggregationQuery.Aggregations,
+		entityAggregationQuery.Histogram,
+	)
+
+	if entityAggregationResultsResult.IsErr() {
+		err := entityAggregationResultsResult.UnwrapErr()
+
+		if _, ok := err.(errs.InvalidEntityAggregationError); ok {
+			return result.Err[model.EntityAggregationResponse](err)
+		}
+
+		return result.Errf[model.EntityAggregationResponse]("error fetching entity aggregation: %w", err)
+	}
+
+	return result.Ok(model.EntityAggregationResponse{
+		GroupBy:      entityAggregationQuery.GroupBy,
+		Aggregations: entityAggregationQuery.Aggregations,
+		Histogram:    entityAggregationQuery.Histogram,
+		Results:      entityAggregationResultsResult.Unwrap(),
+	})
+}
+
+func (e *entityManager) GetFieldValues(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	fieldName model.FieldName,
+	fieldValuesQuery model.FieldValuesQuery,
+) result.R[model.GetFieldValuesResponse] {
+	tableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		return result.Errf[model.GetFieldValuesResponse]("error getting table schema: %w", tableSchemaResult.UnwrapErr())
+	}
+
+	tableSchema := tableSchemaResult.Unwrap()
+
+	filterExpression := fieldValuesQuery.Filter.ToExpression()
+
+	err := e.entityFilterValidator.ValidateEntityFilterExpression(&filterExpression, tableSchema)
+
+	if err != nil {
+		return result.Err[model.GetFieldValuesResponse](errs.NewInvalidEntityFilterError(err))
+	}
+
+	err = e.entityAggregationValidator.ValidateFieldValues(fieldName, tableSchema)
+
+	if err != nil {
+		return result.Err[model.GetFieldValuesResponse](errs.NewInvalidEntityAggregationError(err))
+	}
+
+	fieldValueCountsResult := e.entityAggregationFetcher.FetchFieldValueCounts(
+		projectId,
+		tableName,
+		fieldName,
+		tableSchema[fieldName],
+		filterExpression,
+		fieldValuesQuery.Order,
+		fieldValuesQuery.PaginationParams,
 	)
+
+	if fieldValueCountsResult.IsErr() {
+		return result.Errf[model.GetFieldValuesResponse]("error fetching field values: %w", fieldValueCountsResult.UnwrapErr())
+	}
+
+	return result.Ok(model.GetFieldValuesResponse{
+		Values: fieldValueCountsResult.Unwrap(),
+		Limit:  uint(fieldValuesQuery.PaginationParams.Limit),
+		Offset: uint(fieldValuesQuery.PaginationParams.Offset),
+	})
 }
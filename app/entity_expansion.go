@@ -0,0 +1,161 @@
+package app
+
+import (
+	"crudly/errs"
+	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"fmt"
+	"strings"
+)
+
+// Resolves the expansion paths against the schemas of the referenced tables,
+// giving a tree of the fields to expand. Field projections can include fields
+// of expanded entities as paths too, e.g. customer.name, which are split off
+// into the projections of the expansions, leaving the table's own projection
+// in the returned root
+func (e *entityManager) getFieldExpansion(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	tableSchema model.TableSchema,
+	entityExpansion model.EntityExpansion,
+	fieldProjection optional.O[model.FieldProjection],
+) result.R[model.FieldExpansion] {
+	fieldExpansion := model.FieldExpansion{
+		TableName:   tableName,
+		TableSchema: tableSchema,
+	}
+
+	err := e.resolveFieldExpansion(projectId, &fieldExpansion, entityExpansion, fieldProjection)
+
+	if err != nil {
+		return result.Err[model.FieldExpansion](err)
+	}
+
+	return result.Ok(fieldExpansion)
+}
+
+func (e *entityManager) resolveFieldExpansion(
+	projectId model.ProjectId,
+	fieldExpansion *model.FieldExpansion,
+	entityExpansion model.EntityExpansion,
+	fieldProjection optional.O[model.FieldProjection],
+) error {
+	ownFieldProjection, nestedFieldProjections := splitFieldProjection(fieldProjection, fieldExpansion.TableSchema)
+
+	if ownFieldProjection.IsSome() {
+		err := e.fieldProjectionValidator.ValidateFieldProjection(ownFieldProjection.Unwrap(), fieldExpansion.TableSchema)
+
+		if err != nil {
+			return errs.NewInvalidFieldProjectionError(err)
+		}
+	}
+
+	fieldExpansion.FieldProjection = ownFieldProjection
+
+	// Paths are grouped by their first field so that each field is expanded
+	// once, however many paths go through it
+	expandedFieldNames := []model.FieldName{}
+	nestedExpansions := map[model.FieldName]model.EntityExpansion{}
+
+	for _, expansionPath := range entityExpansion {
+		fieldName := expansionPath[0]
+
+		if _, ok := nestedExpansions[fieldName]; !ok {
+			expandedFieldNames = append(expandedFieldNames, fieldName)
+			nestedExpansions[fieldName] = model.EntityExpansion{}
+		}
+
+		if len(expansionPath) > 1 {
+			nestedExpansions[fieldName] = append(nestedExpansions[fieldName], expansionPath[1:])
+		}
+	}
+
+	for fieldName := range nestedFieldProjections {
+		if _, ok := nestedExpansions[fieldName]; !ok {
+			return errs.NewInvalidFieldProjectionError(
+				fmt.Errorf("fields of: \"%s\" can only be projected when it's expanded", fieldName),
+			)
+		}
+	}
+
+	for _, fieldName := range expandedFieldNames {
+		err := e.entityExpansionValidator.ValidateExpandedField(fieldName, fieldExpansion.TableSchema, ownFieldProjection)
+
+		if err != nil {
+			return errs.NewInvalidEntityExpansionError(err)
+		}
+
+		referencedTableName := fieldExpansion.TableSchema[fieldName].References.Unwrap()
+		referencedTableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, referencedTableName)
+
+		if referencedTableSchemaResult.IsErr() {
+			return fmt.Errorf("error getting referenced table schema: %w", referencedTableSchemaResult.UnwrapErr())
+		}
+
+		nestedFieldExpansion := model.FieldExpansion{
+			FieldName:   fieldName,
+			TableName:   referencedTableName,
+			TableSchema: referencedTableSchemaResult.Unwrap(),
+		}
+
+		nestedFieldProjection := optional.None[model.FieldProjection]()
+
+		if nestedProjection, ok := nestedFieldProjections[fieldName]; ok {
+			nestedFieldProjection = optional.Some(nestedProjection)
+		}
+
+		err = e.resolveFieldExpansion(
+			projectId,
+			&nestedFieldExpansion,
+			nestedExpansions[fieldName],
+			nestedFieldProjection,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		fieldExpansion.Expansions = append(fieldExpansion.Expansions, nestedFieldExpansion)
+	}
+
+	return nil
+}
+
+// Splits paths such as customer.name off from the fields of the table itself,
+// keyed by their first field, which is kept in the table's own projection
+func splitFieldProjection(
+	fieldProjection optional.O[model.FieldProjection],
+	tableSchema model.TableSchema,
+) (optional.O[model.FieldProjection], map[model.FieldName]model.FieldProjection) {
+	nestedFieldProjections := map[model.FieldName]model.FieldProjection{}
+
+	if fieldProjection.IsNone() {
+		return fieldProjection, nestedFieldProjections
+	}
+
+	ownFieldProjection := model.FieldProjection{}
+
+	addOwnField := func(fieldName model.FieldName) {
+		if !util.Contains(ownFieldProjection, fieldName) {
+			ownFieldProjection = append(ownFieldProjection, fieldName)
+		}
+	}
+
+	for _, fieldName := range fieldProjection.Unwrap() {
+		// Field names can contain dots themselves
+		if _, ok := tableSchema[fieldName]; ok || !strings.Contains(fieldName.String(), ".") {
+			addOwnField(fieldName)
+			continue
+		}
+
+		segments := strings.SplitN(fieldName.String(), ".", 2)
+		firstFieldName := model.FieldName(segments[0])
+
+		addOwnField(firstFieldName)
+		nestedFieldProjections[firstFieldName] = append(nestedFieldProjections[firstFieldName], model.FieldName(segments[1]))
+	}
+
+	return optional.Some(ownFieldProjection), nestedFieldProjections
+}
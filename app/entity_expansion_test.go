@@ -0,0 +1,256 @@
+package app
+
+import (
+	"crudly/app/validation"
+	"crudly/errs"
+	"crudly/model"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"reflect"
+	"testing"
+)
+
+// Orders reference customers, who reference addresses. Orders also have a
+// field whose name contains a dot
+var testExpansionSchemas = model.TableSchemas{
+	"orders": {
+		"customer": {Type: model.FieldTypeReference, References: optional.Some[model.TableName]("customers")},
+		"seller":   {Type: model.FieldTypeReference, References: optional.Some[model.TableName]("customers")},
+		"total":    {Type: model.FieldTypeInteger},
+		"ship.to":  {Type: model.FieldTypeString},
+	},
+	"customers": {
+		"name":    {Type: model.FieldTypeString},
+		"address": {Type: model.FieldTypeReference, References: optional.Some[model.TableName]("addresses")},
+	},
+	"addresses": {
+		"city": {Type: model.FieldTypeString},
+	},
+}
+
+type testExpansionSchemaGetter struct{}
+
+func (t *testExpansionSchemaGetter) GetTableSchema(
+	projectId model.ProjectId,
+	name model.TableName,
+) result.R[model.TableSchema] {
+	tableSchema, ok := testExpansionSchemas[name]
+
+	if !ok {
+		return result.Err[model.TableSchema](errs.TableNotFoundError{})
+	}
+
+	return result.Ok(tableSchema)
+}
+
+func TestSplitFieldProjection(t *testing.T) {
+	tests := []struct {
+		name                           string
+		fieldProjection                optional.O[model.FieldProjection]
+		expectedOwnFieldProjection     optional.O[model.FieldProjection]
+		expectedNestedFieldProjections map[model.FieldName]model.FieldProjection
+	}{
+		{
+			name:                           "no projection",
+			fieldProjection:                optional.None[model.FieldProjection](),
+			expectedOwnFieldProjection:     optional.None[model.FieldProjection](),
+			expectedNestedFieldProjections: map[model.FieldName]model.FieldProjection{},
+		},
+		{
+			name:                           "own fields",
+			fieldProjection:                optional.Some(model.FieldProjection{"total", "customer"}),
+			expectedOwnFieldProjection:     optional.Some(model.FieldProjection{"total", "customer"}),
+			expectedNestedFieldProjections: map[model.FieldName]model.FieldProjection{},
+		},
+		{
+			name:                       "nested fields keep their first field in the own projection",
+			fieldProjection:            optional.Some(model.FieldProjection{"total", "customer.name", "customer.address.city"}),
+			expectedOwnFieldProjection: optional.Some(model.FieldProjection{"total", "customer"}),
+			expectedNestedFieldProjections: map[model.FieldName]model.FieldProjection{
+				"customer": {"name", "address.city"},
+			},
+		},
+		{
+			name:                           "field names containing dots",
+			fieldProjection:                optional.Some(model.FieldProjection{"ship.to"}),
+			expectedOwnFieldProjection:     optional.Some(model.FieldProjection{"ship.to"}),
+			expectedNestedFieldProjections: map[model.FieldName]model.FieldProjection{},
+		},
+		{
+			name:                       "first fields aren't repeated",
+			fieldProjection:            optional.Some(model.FieldProjection{"customer", "customer.name", "seller.name"}),
+			expectedOwnFieldProjection: optional.Some(model.FieldProjection{"customer", "seller"}),
+			expectedNestedFieldProjections: map[model.FieldName]model.FieldProjection{
+				"customer": {"name"},
+				"seller":   {"name"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ownFieldProjection, nestedFieldProjections := splitFieldProjection(
+				test.fieldProjection,
+				testExpansionSchemas["orders"],
+			)
+
+			if !reflect.DeepEqual(ownFieldProjection, test.expectedOwnFieldProjection) {
+				t.Errorf("unexpected own projection:\n got: %+v\nwant: %+v", ownFieldProjection, test.expectedOwnFieldProjection)
+			}
+
+			if !reflect.DeepEqual(nestedFieldProjections, test.expectedNestedFieldProjections) {
+				t.Errorf(
+					"unexpected nested projections:\n got: %+v\nwant: %+v",
+					nestedFieldProjections,
+					test.expectedNestedFieldProjections,
+				)
+			}
+		})
+	}
+}
+
+func TestGetFieldExpansion(t *testing.T) {
+	addresses := model.FieldExpansion{
+		FieldName:       "address",
+		TableName:       "addresses",
+		TableSchema:     testExpansionSchemas["addresses"],
+		FieldProjection: optional.None[model.FieldProjection](),
+	}
+
+	tests := []struct {
+		name                   string
+		entityExpansion        model.EntityExpansion
+		fieldProjection        optional.O[model.FieldProjection]
+		expectedFieldExpansion model.FieldExpansion
+		expectErr              bool
+	}{
+		{
+			name:            "nothing expanded",
+			entityExpansion: model.EntityExpansion{},
+			fieldProjection: optional.None[model.FieldProjection](),
+			expectedFieldExpansion: model.FieldExpansion{
+				TableName:       "orders",
+				TableSchema:     testExpansionSchemas["orders"],
+				FieldProjection: optional.None[model.FieldProjection](),
+			},
+		},
+		{
+			name: "paths through the same field are grouped",
+			entityExpansion: model.EntityExpansion{
+				{"customer"},
+				{"seller"},
+				{"customer", "address"},
+			},
+			fieldProjection: optional.None[model.FieldProjection](),
+			expectedFieldExpansion: model.FieldExpansion{
+				TableName:       "orders",
+				TableSchema:     testExpansionSchemas["orders"],
+				FieldProjection: optional.None[model.FieldProjection](),
+				Expansions: model.FieldExpansions{
+					{
+						FieldName:       "customer",
+						TableName:       "customers",
+						TableSchema:     testExpansionSchemas["customers"],
+						FieldProjection: optional.None[model.FieldProjection](),
+						Expansions:      model.FieldExpansions{addresses},
+					},
+					{
+						FieldName:       "seller",
+						TableName:       "customers",
+						TableSchema:     testExpansionSchemas["customers"],
+						FieldProjection: optional.None[model.FieldProjection](),
+					},
+				},
+			},
+		},
+		{
+			name:            "nested projections go to their expansions",
+			entityExpansion: model.EntityExpansion{{"customer", "address"}},
+			fieldProjection: optional.Some(model.FieldProjection{"total", "customer.name", "customer.address"}),
+			expectedFieldExpansion: model.FieldExpansion{
+				TableName:       "orders",
+				TableSchema:     testExpansionSchemas["orders"],
+				FieldProjection: optional.Some(model.FieldProjection{"total", "customer"}),
+				Expansions: model.FieldExpansions{
+					{
+						FieldName:       "customer",
+						TableName:       "customers",
+						TableSchema:     testExpansionSchemas["customers"],
+						FieldProjection: optional.Some(model.FieldProjection{"name", "address"}),
+						Expansions:      model.FieldExpansions{addresses},
+					},
+				},
+			},
+		},
+		{
+			name:            "expanded field missing from the projection",
+			entityExpansion: model.EntityExpansion{{"customer"}},
+			fieldProjection: optional.Some(model.FieldProjection{"total"}),
+			expectErr:       true,
+		},
+		{
+			name:            "nested field expanded but missing from the nested projection",
+			entityExpansion: model.EntityExpansion{{"customer", "address"}},
+			fieldProjection: optional.Some(model.FieldProjection{"customer.name"}),
+			expectErr:       true,
+		},
+		{
+			name:            "nested fields projected without being expanded",
+			entityExpansion: model.EntityExpansion{},
+			fieldProjection: optional.Some(model.FieldProjection{"customer.name"}),
+			expectErr:       true,
+		},
+		{
+			name:            "nested field that doesn't exist",
+			entityExpansion: model.EntityExpansion{{"customer"}},
+			fieldProjection: optional.Some(model.FieldProjection{"customer.age"}),
+			expectErr:       true,
+		},
+		{
+			name:            "expanding a field that isn't a reference",
+			entityExpansion: model.EntityExpansion{{"total"}},
+			fieldProjection: optional.None[model.FieldProjection](),
+			expectErr:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fieldProjectionValidator := validation.NewFieldProjectionValidator()
+			entityExpansionValidator := validation.NewEntityExpansionValidator()
+
+			entityManager := entityManager{
+				tableSchemaGetter:        &testExpansionSchemaGetter{},
+				fieldProjectionValidator: &fieldProjectionValidator,
+				entityExpansionValidator: &entityExpansionValidator,
+			}
+
+			fieldExpansionResult := entityManager.getFieldExpansion(
+				model.ProjectId{},
+				"orders",
+				testExpansionSchemas["orders"],
+				test.entityExpansion,
+				test.fieldProjection,
+			)
+
+			if test.expectErr {
+				if fieldExpansionResult.IsOk() {
+					t.Fatalf("expected an error, got: %+v", fieldExpansionResult.Unwrap())
+				}
+				return
+			}
+
+			if fieldExpansionResult.IsErr() {
+				t.Fatalf("unexpected error: %s", fieldExpansionResult.UnwrapErr())
+			}
+
+			if !reflect.DeepEqual(fieldExpansionResult.Unwrap(), test.expectedFieldExpansion) {
+				t.Errorf(
+					"unexpected expansion:\n got: %+v\nwant: %+v",
+					fieldExpansionResult.Unwrap(),
+					test.expectedFieldExpansion,
+				)
+			}
+		})
+	}
+}
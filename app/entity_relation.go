@@ -0,0 +1,332 @@
+package app
+
+import (
+	"crudly/errs"
+	"crudly/model"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Gets the entities of another table that reference the given entity. When
+// the other table references this one through more than one field, via picks
+// which of them to follow
+func (e *entityManager) GetRelatedEntities(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	id model.EntityId,
+	relatedTableName model.TableName,
+	via optional.O[model.FieldName],
+	entityFilter model.EntityFilter,
+	entityOrders model.EntityOrders,
+	paginationParams model.PaginationParams,
+	countType model.EntityCountType,
+	fieldProjection optional.O[model.FieldProjection],
+	entityExpansion model.EntityExpansion,
+) result.R[model.GetEntitiesResponse] {
+	relatedTableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, relatedTableName)
+
+	if relatedTableSchemaResult.IsErr() {
+		return result.Err[model.GetEntitiesResponse](relatedTableSchemaResult.UnwrapErr())
+	}
+
+	referenceFieldNameResult := getReferenceFieldName(relatedTableSchemaResult.Unwrap(), relatedTableName, tableName, via)
+
+	if referenceFieldNameResult.IsErr() {
+		return result.Err[model.GetEntitiesResponse](referenceFieldNameResult.UnwrapErr())
+	}
+
+	err := e.checkEntityExists(projectId, tableName, id)
+
+	if err != nil {
+		return result.Err[model.GetEntitiesResponse](err)
+	}
+
+	return e.QueryEntities(
+		projectId,
+		relatedTableName,
+		getReferenceFilterExpression(entityFilter.ToExpression(), referenceFieldNameResult.Unwrap(), id),
+		entityOrders,
+		paginationParams,
+		countType,
+		fieldProjection,
+		entityExpansion,
+	)
+}
+
+// Gets the entities linked to the given entity through a link table. The
+// rows of the link table are paged through with their other side expanded,
+// so the projection and expansions given apply to the linked entities
+func (e *entityManager) GetLinkedEntities(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	id model.EntityId,
+	linkTableName model.TableName,
+	paginationParams model.PaginationParams,
+	countType model.EntityCountType,
+	fieldProjection optional.O[model.FieldProjection],
+	entityExpansion model.EntityExpansion,
+) result.R[model.GetEntitiesResponse] {
+	linkFieldsResult := e.getLinkFields(projectId, tableName, linkTableName)
+
+	if linkFieldsResult.IsErr() {
+		return result.Err[model.GetEntitiesResponse](linkFieldsResult.UnwrapErr())
+	}
+
+	linkFields := linkFieldsResult.Unwrap()
+
+	err := e.checkEntityExists(projectId, tableName, id)
+
+	if err != nil {
+		return result.Err[model.GetEntitiesResponse](err)
+	}
+
+	linkFieldProjection := optional.None[model.FieldProjection]()
+
+	if fieldProjection.IsSome() {
+		linkFieldProjection = optional.Some(model.FieldProjection{linkFields.Other})
+
+		for _, fieldName := range fieldProjection.Unwrap() {
+			linkFieldProjection = optional.Some(append(
+				linkFieldProjection.Unwrap(),
+				model.FieldName(linkFields.Other.String()+"."+fieldName.String()),
+			))
+		}
+	}
+
+	// The linked entities are expanded from the link table, one level deeper
+	// than the paths given, which are validated before that level is added
+	err = e.entityExpansionValidator.ValidateEntityExpansion(entityExpansion)
+
+	if err != nil {
+		return result.Err[model.GetEntitiesResponse](errs.NewInvalidEntityExpansionError(err))
+	}
+
+	linkEntityExpansion := model.EntityExpansion{{linkFields.Other}}
+
+	for _, expansionPath := range entityExpansion {
+		linkEntityExpansion = append(linkEntityExpansion, append(model.ExpansionPath{linkFields.Other}, expansionPath...))
+	}
+
+	linkEntitiesResult := e.queryEntities(
+		projectId,
+		linkTableName,
+		getReferenceFilterExpression(model.EmptyEntityFilterExpression(), linkFields.Own, id),
+		model.EntityOrders{},
+		paginationParams,
+		countType,
+		linkFieldProjection,
+		linkEntityExpansion,
+	)
+
+	if linkEntitiesResult.IsErr() {
+		return linkEntitiesResult
+	}
+
+	linkedEntitiesResponse := linkEntitiesResult.Unwrap()
+	linkedEntities := model.Entities{}
+
+	for _, linkEntity := range linkedEntitiesResponse.Entities {
+		if linkedEntity, ok := linkEntity[linkFields.Other].(model.Entity); ok {
+			linkedEntities = append(linkedEntities, linkedEntity)
+		}
+	}
+
+	linkedEntitiesResponse.Entities = linkedEntities
+
+	return result.Ok(linkedEntitiesResponse)
+}
+
+// Returns whether the entities weren't already linked
+func (e *entityManager) CreateLink(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	id model.EntityId,
+	linkTableName model.TableName,
+	otherId model.EntityId,
+) result.R[bool] {
+	linkFieldsResult := e.getLinkFields(projectId, tableName, linkTableName)
+
+	if linkFieldsResult.IsErr() {
+		return result.Err[bool](linkFieldsResult.UnwrapErr())
+	}
+
+	createdResult := e.entityLinker.CreateLink(
+		projectId,
+		linkTableName,
+		linkFieldsResult.Unwrap(),
+		model.EntityId(uuid.New()),
+		id,
+		otherId,
+	)
+
+	if createdResult.IsErr() {
+		err := createdResult.UnwrapErr()
+
+		if _, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			return createdResult
+		}
+
+		return result.Errf[bool]("error creating link: %w", err)
+	}
+
+	return createdResult
+}
+
+func (e *entityManager) DeleteLink(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	id model.EntityId,
+	linkTableName model.TableName,
+	otherId model.EntityId,
+) error {
+	linkFieldsResult := e.getLinkFields(projectId, tableName, linkTableName)
+
+	if linkFieldsResult.IsErr() {
+		return linkFieldsResult.UnwrapErr()
+	}
+
+	err := e.entityLinker.DeleteLink(
+		projectId,
+		linkTableName,
+		linkFieldsResult.Unwrap(),
+		id,
+		otherId,
+	)
+
+	if err != nil {
+		if _, ok := err.(errs.LinkNotFoundError); ok {
+			return err
+		}
+
+		return fmt.Errorf("error deleting link: %w", err)
+	}
+
+	return nil
+}
+
+func (e *entityManager) getLinkFields(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	linkTableName model.TableName,
+) result.R[model.LinkFields] {
+	linkTableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, linkTableName)
+
+	if linkTableSchemaResult.IsErr() {
+		return result.Err[model.LinkFields](linkTableSchemaResult.UnwrapErr())
+	}
+
+	linkTableIndexesResult := e.tableIndexGetter.GetTableIndexes(projectId, linkTableName)
+
+	if linkTableIndexesResult.IsErr() {
+		return result.Err[model.LinkFields](linkTableIndexesResult.UnwrapErr())
+	}
+
+	linkFields := linkTableIndexesResult.Unwrap().GetLinkFields(linkTableSchemaResult.Unwrap(), tableName)
+
+	if linkFields.IsNone() {
+		return result.Err[model.LinkFields](errs.NewInvalidRelationError(
+			fmt.Errorf("table: \"%s\" is not a link table for \"%s\"", linkTableName, tableName),
+		))
+	}
+
+	return result.Ok(linkFields.Unwrap())
+}
+
+func (e *entityManager) checkEntityExists(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	id model.EntityId,
+) error {
+	tableSchemaResult := e.tableSchemaGetter.GetTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		return fmt.Errorf("error getting table schema: %w", tableSchemaResult.UnwrapErr())
+	}
+
+	entityResult := e.entityFetcher.FetchEntity(
+		projectId,
+		tableName,
+		tableSchemaResult.Unwrap(),
+		id,
+		optional.Some(model.FieldProjection{}),
+	)
+
+	if entityResult.IsErr() {
+		err := entityResult.UnwrapErr()
+
+		if _, ok := err.(errs.EntityNotFoundError); ok {
+			return err
+		}
+
+		return fmt.Errorf("error fetching entity: %w", err)
+	}
+
+	return nil
+}
+
+func getReferenceFieldName(
+	relatedTableSchema model.TableSchema,
+	relatedTableName model.TableName,
+	tableName model.TableName,
+	via optional.O[model.FieldName],
+) result.R[model.FieldName] {
+	if via.IsSome() {
+		fieldDefinition, ok := relatedTableSchema[via.Unwrap()]
+
+		if !ok || fieldDefinition.References != optional.Some(tableName) {
+			return result.Err[model.FieldName](errs.NewInvalidRelationError(
+				fmt.Errorf("field: \"%s\" of \"%s\" doesn't reference \"%s\"", via.Unwrap(), relatedTableName, tableName),
+			))
+		}
+
+		return result.Ok(via.Unwrap())
+	}
+
+	referenceFieldNames := []model.FieldName{}
+
+	for fieldName, fieldDefinition := range relatedTableSchema {
+		if fieldDefinition.References == optional.Some(tableName) {
+			referenceFieldNames = append(referenceFieldNames, fieldName)
+		}
+	}
+
+	if len(referenceFieldNames) == 0 {
+		return result.Err[model.FieldName](errs.NewInvalidRelationError(
+			fmt.Errorf("table: \"%s\" has no references to \"%s\"", relatedTableName, tableName),
+		))
+	}
+
+	if len(referenceFieldNames) > 1 {
+		return result.Err[model.FieldName](errs.NewInvalidRelationError(
+			fmt.Errorf("table: \"%s\" references \"%s\" through more than one field, one has to be given as via", relatedTableName, tableName),
+		))
+	}
+
+	return result.Ok(referenceFieldNames[0])
+}
+
+// Comparators are given as strings, the same as filters from a request, as
+// they're parsed into the field's type when the filter is validated
+func getReferenceFilterExpression(
+	filterExpression model.EntityFilterExpression,
+	fieldName model.FieldName,
+	id model.EntityId,
+) model.EntityFilterExpression {
+	return model.EntityFilterExpression{
+		Type: model.EntityFilterExpressionTypeAnd,
+		Operands: []model.EntityFilterExpression{
+			filterExpression,
+			{
+				Type:      model.EntityFilterExpressionTypeField,
+				FieldName: fieldName,
+				FieldFilter: model.FieldFilter{
+					Type:       model.FieldFilterTypeEquals,
+					Comparator: id.String(),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,181 @@
+package app
+
+import (
+	"crudly/app/validation"
+	"crudly/errs"
+	"crudly/model"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Posts are linked to tags, which each have a parent tag
+var testLinkSchemas = model.TableSchemas{
+	"posts": {},
+	"tags": {
+		"parent": {Type: model.FieldTypeReference, References: optional.Some[model.TableName]("tags"), IsOptional: true},
+	},
+	"postTags": {
+		"post": {Type: model.FieldTypeReference, References: optional.Some[model.TableName]("posts")},
+		"tag":  {Type: model.FieldTypeReference, References: optional.Some[model.TableName]("tags")},
+	},
+}
+
+type testTableGetter struct{}
+
+func (t *testTableGetter) GetTableSchema(projectId model.ProjectId, name model.TableName) result.R[model.TableSchema] {
+	tableSchema, ok := testLinkSchemas[name]
+
+	if !ok {
+		return result.Err[model.TableSchema](errs.TableNotFoundError{})
+	}
+
+	return result.Ok(tableSchema)
+}
+
+func (t *testTableGetter) GetTableIndexes(projectId model.ProjectId, name model.TableName) result.R[model.TableIndexes] {
+	if name != "postTags" {
+		return result.Ok(model.TableIndexes{})
+	}
+
+	return result.Ok(model.TableIndexes{
+		model.LinkIndexName: {Fields: []model.FieldName{"post", "tag"}, Unique: true, Link: true},
+	})
+}
+
+// Fetches one link to a tag, recording what it was asked to expand
+type testLinkEntityFetcher struct {
+	fieldExpansions model.FieldExpansions
+}
+
+func (t *testLinkEntityFetcher) FetchEntity(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	tableSchema model.TableSchema,
+	id model.EntityId,
+	fieldProjection optional.O[model.FieldProjection],
+) result.R[model.Entity] {
+	return result.Ok(model.Entity{"id": uuid.UUID(id)})
+}
+
+func (t *testLinkEntityFetcher) FetchEntities(
+	projectId model.ProjectId,
+	table model.TableName,
+	tableSchema model.TableSchema,
+	filterExpression model.EntityFilterExpression,
+	entityOrders model.EntityOrders,
+	paginationParams model.PaginationParams,
+	fieldProjection optional.O[model.FieldProjection],
+) result.R[model.Entities] {
+	return result.Ok(model.Entities{{"id": uuid.New(), "post": uuid.New(), "tag": uuid.New()}})
+}
+
+func (t *testLinkEntityFetcher) ExpandEntities(
+	projectId model.ProjectId,
+	entities model.Entities,
+	fieldExpansions model.FieldExpansions,
+) error {
+	t.fieldExpansions = fieldExpansions
+
+	for _, entity := range entities {
+		entity["tag"] = model.Entity{"id": entity["tag"]}
+	}
+
+	return nil
+}
+
+func TestGetLinkedEntitiesExpansionDepth(t *testing.T) {
+	deepestExpansion := model.ExpansionPath{"parent", "parent", "parent"}
+
+	tests := []struct {
+		name                  string
+		entityExpansion       model.EntityExpansion
+		expectedExpandedDepth int
+		expectErr             bool
+	}{
+		{
+			name:                  "no expansion",
+			entityExpansion:       model.EntityExpansion{},
+			expectedExpandedDepth: 1,
+		},
+		{
+			name:                  "maximum depth",
+			entityExpansion:       model.EntityExpansion{deepestExpansion},
+			expectedExpandedDepth: model.MaxExpansionDepth + 1,
+		},
+		{
+			name:            "deeper than the maximum",
+			entityExpansion: model.EntityExpansion{append(model.ExpansionPath{"parent"}, deepestExpansion...)},
+			expectErr:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tableGetter := testTableGetter{}
+			entityFetcher := testLinkEntityFetcher{}
+			entityFilterValidator := validation.NewEntityFilterValidator()
+			entityOrderValidator := validation.NewEntityOrderValidator()
+			fieldProjectionValidator := validation.NewFieldProjectionValidator()
+			entityExpansionValidator := validation.NewEntityExpansionValidator()
+
+			entityManager := entityManager{
+				entityFetcher:            &entityFetcher,
+				tableSchemaGetter:        &tableGetter,
+				tableIndexGetter:         &tableGetter,
+				entityFilterValidator:    &entityFilterValidator,
+				entityOrderValidator:     &entityOrderValidator,
+				fieldProjectionValidator: &fieldProjectionValidator,
+				entityExpansionValidator: &entityExpansionValidator,
+			}
+
+			linkedEntitiesResult := entityManager.GetLinkedEntities(
+				model.ProjectId(uuid.New()),
+				"posts",
+				model.EntityId(uuid.New()),
+				"postTags",
+				model.PaginationParams{Limit: 10},
+				model.EntityCountTypeNone,
+				optional.None[model.FieldProjection](),
+				test.entityExpansion,
+			)
+
+			if test.expectErr {
+				if linkedEntitiesResult.IsOk() {
+					t.Fatalf("expected an error")
+				}
+
+				if _, ok := linkedEntitiesResult.UnwrapErr().(errs.InvalidEntityExpansionError); !ok {
+					t.Fatalf("unexpected error: %s", linkedEntitiesResult.UnwrapErr())
+				}
+
+				// The link field is never named, as it isn't part of the request
+				if strings.Contains(linkedEntitiesResult.UnwrapErr().Error(), "tag") {
+					t.Errorf("error names the link field: %s", linkedEntitiesResult.UnwrapErr())
+				}
+				return
+			}
+
+			if linkedEntitiesResult.IsErr() {
+				t.Fatalf("unexpected error: %s", linkedEntitiesResult.UnwrapErr())
+			}
+
+			if len(linkedEntitiesResult.Unwrap().Entities) != 1 {
+				t.Fatalf("got %d linked entities, want 1", len(linkedEntitiesResult.Unwrap().Entities))
+			}
+
+			expandedDepth := 0
+
+			for fieldExpansions := entityFetcher.fieldExpansions; len(fieldExpansions) > 0; fieldExpansions = fieldExpansions[0].Expansions {
+				expandedDepth++
+			}
+
+			if expandedDepth != test.expectedExpandedDepth {
+				t.Errorf("got expansions %d deep, want %d", expandedDepth, test.expectedExpandedDepth)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"reflect"
+	"testing"
+)
+
+func TestGetEntitiesPage(t *testing.T) {
+	entityOrders := model.EntityOrders{
+		{Type: model.FieldOrderTypeDescending, FieldName: "age"},
+		{Type: model.FieldOrderTypeAscending, FieldName: "id"},
+	}
+
+	entities := model.Entities{
+		{"id": "a", "age": 40},
+		{"id": "b", "age": nil},
+		{"id": "c", "age": 20},
+	}
+
+	tests := []struct {
+		name               string
+		entities           model.Entities
+		limit              model.PaginationLimit
+		expectedEntities   model.Entities
+		expectedNextCursor optional.O[model.EntityCursor]
+	}{
+		{
+			name:               "fewer than the limit",
+			entities:           entities,
+			limit:              5,
+			expectedEntities:   entities,
+			expectedNextCursor: optional.None[model.EntityCursor](),
+		},
+		{
+			name:               "exactly the limit",
+			entities:           entities,
+			limit:              3,
+			expectedEntities:   entities,
+			expectedNextCursor: optional.None[model.EntityCursor](),
+		},
+		{
+			name:             "one more than the limit",
+			entities:         entities,
+			limit:            2,
+			expectedEntities: entities[:2],
+			expectedNextCursor: optional.Some(model.EntityCursor{
+				{FieldName: "age", Type: model.FieldOrderTypeDescending, Value: nil},
+				{FieldName: "id", Type: model.FieldOrderTypeAscending, Value: "b"},
+			}),
+		},
+		{
+			name:               "zero limit",
+			entities:           entities[:1],
+			limit:              0,
+			expectedEntities:   model.Entities{},
+			expectedNextCursor: optional.None[model.EntityCursor](),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, nextCursor := getEntitiesPage(test.entities, test.limit, entityOrders)
+
+			if !reflect.DeepEqual(page, test.expectedEntities) {
+				t.Errorf("unexpected entities:\n got: %+v\nwant: %+v", page, test.expectedEntities)
+			}
+
+			if !reflect.DeepEqual(nextCursor, test.expectedNextCursor) {
+				t.Errorf("unexpected next cursor:\n got: %+v\nwant: %+v", nextCursor, test.expectedNextCursor)
+			}
+		})
+	}
+}
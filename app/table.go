@@ -3,9 +3,11 @@ package app
 import (
 	"crudly/errs"
 	"crudly/model"
+	"crudly/util"
 	"crudly/util/optional"
 	"crudly/util/result"
 	"fmt"
+	"sort"
 )
 
 type tableCreator interface {
@@ -13,6 +15,7 @@ type tableCreator interface {
 		projectId model.ProjectId,
 		name model.TableName,
 		schema model.TableSchema,
+		indexes model.TableIndexes,
 	) error
 }
 
@@ -51,20 +54,121 @@ type tableFieldDeleter interface {
 		tableName model.TableName,
 		existingSchema model.TableSchema,
 		name model.FieldName,
+		indexNames []model.IndexName,
 	) error
 }
 
+type tableFieldRenamer interface {
+	RenameField(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		existingSchema model.TableSchema,
+		existingIndexes model.TableIndexes,
+		name model.FieldName,
+		newName model.FieldName,
+	) error
+}
+
+type tableRenamer interface {
+	RenameTable(
+		projectId model.ProjectId,
+		name model.TableName,
+		newName model.TableName,
+		existingIndexes model.TableIndexes,
+		referencingSchemas model.TableSchemas,
+	) error
+}
+
+type tableFieldAlterer interface {
+	AlterField(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		existingSchema model.TableSchema,
+		name model.FieldName,
+		newDefinition model.FieldDefinition,
+		backfillValue optional.O[any],
+		dryRun bool,
+		checkStoredFieldValue func(storedFieldValue model.StoredFieldValue) error,
+	) result.R[model.FieldAlteration]
+}
+
+type fieldAlterationValidator interface {
+	ValidateFieldAlteration(
+		fieldName model.FieldName,
+		fieldDefinition model.FieldDefinition,
+		newFieldDefinition model.FieldDefinition,
+		tableIndexes model.TableIndexes,
+	) error
+
+	ConvertStoredField(
+		storedFieldValue model.StoredFieldValue,
+		fieldName model.FieldName,
+		fieldDefinition model.FieldDefinition,
+		newFieldDefinition model.FieldDefinition,
+	) result.R[any]
+}
+
 type tableSchemaValidator interface {
 	ValidateTableSchema(schema model.TableSchema) error
 }
 
+type tableTimeFieldMigrator interface {
+	MigrateTimeFields(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		tableSchema model.TableSchema,
+	) result.R[[]model.FieldName]
+}
+
+type tableIndexFetcher interface {
+	FetchTableIndexes(
+		projectId model.ProjectId,
+		tableName model.TableName,
+	) result.R[model.TableIndexes]
+}
+
+type tableIndexCreator interface {
+	CreateIndex(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		name model.IndexName,
+		index model.TableIndex,
+	) error
+}
+
+type tableIndexDeleter interface {
+	DeleteIndex(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		name model.IndexName,
+	) error
+}
+
+type tableIndexValidator interface {
+	ValidateTableIndex(
+		name model.IndexName,
+		index *model.TableIndex,
+		tableSchema model.TableSchema,
+	) error
+}
+
 type tableManager struct {
-	tableSchemaFetcher   tableSchemaFetcher
-	tableCreator         tableCreator
-	tableDeleter         tableDeleter
-	tableFieldAdder      tableFieldAdder
-	tableFieldDeleter    tableFieldDeleter
-	tableSchemaValidator tableSchemaValidator
+	tableSchemaFetcher       tableSchemaFetcher
+	tableCreator             tableCreator
+	tableDeleter             tableDeleter
+	tableFieldAdder          tableFieldAdder
+	tableFieldDeleter        tableFieldDeleter
+	tableSchemaValidator     tableSchemaValidator
+	tableTimeFieldMigrator   tableTimeFieldMigrator
+	entityValidator          entityValidator
+	tableIndexFetcher        tableIndexFetcher
+	tableIndexCreator        tableIndexCreator
+	tableIndexDeleter        tableIndexDeleter
+	tableIndexValidator      tableIndexValidator
+	tableFieldRenamer        tableFieldRenamer
+	tableRenamer             tableRenamer
+	tableFieldAlterer        tableFieldAlterer
+	fieldAlterationValidator fieldAlterationValidator
 }
 
 func NewTableManager(
@@ -74,6 +178,16 @@ func NewTableManager(
 	tableFieldAdder tableFieldAdder,
 	tableFieldDeleter tableFieldDeleter,
 	tableSchemaValidator tableSchemaValidator,
+	tableTimeFieldMigrator tableTimeFieldMigrator,
+	entityValidator entityValidator,
+	tableIndexFetcher tableIndexFetcher,
+	tableIndexCreator tableIndexCreator,
+	tableIndexDeleter tableIndexDeleter,
+	tableIndexValidator tableIndexValidator,
+	tableFieldRenamer tableFieldRenamer,
+	tableRenamer tableRenamer,
+	tableFieldAlterer tableFieldAlterer,
+	fieldAlterationValidator fieldAlterationValidator,
 ) tableManager {
 	return tableManager{
 		tableSchemaFetcher,
@@ -82,6 +196,16 @@ func NewTableManager(
 		tableFieldAdder,
 		tableFieldDeleter,
 		tableSchemaValidator,
+		tableTimeFieldMigrator,
+		entityValidator,
+		tableIndexFetcher,
+		tableIndexCreator,
+		tableIndexDeleter,
+		tableIndexValidator,
+		tableFieldRenamer,
+		tableRenamer,
+		tableFieldAlterer,
+		fieldAlterationValidator,
 	}
 }
 
@@ -113,24 +237,128 @@ func (t *tableManager) GetTableSchemas(projectId model.ProjectId) result.R[model
 	return result.Ok(tableSchemasResult.Unwrap())
 }
 
-func (t *tableManager) CreateTable(projectId model.ProjectId, name model.TableName, schema model.TableSchema) error {
+func (t *tableManager) CreateTable(
+	projectId model.ProjectId,
+	name model.TableName,
+	schema model.TableSchema,
+	indexes model.TableIndexes,
+	link bool,
+) error {
 	err := t.tableSchemaValidator.ValidateTableSchema(schema)
 
 	if err != nil {
 		return errs.NewInvalidTableError(err)
 	}
 
+	err = t.validateReferencedTables(projectId, name, schema)
+
+	if err != nil {
+		return err
+	}
+
+	if indexes == nil {
+		indexes = model.TableIndexes{}
+	}
+
+	if link {
+		linkIndexResult := getLinkIndex(schema)
+
+		if linkIndexResult.IsErr() {
+			return errs.NewInvalidTableError(linkIndexResult.UnwrapErr())
+		}
+
+		if _, ok := indexes[model.LinkIndexName]; ok {
+			return errs.NewInvalidTableIndexError(
+				fmt.Errorf("index: \"%s\" is reserved for the link index of link tables", model.LinkIndexName),
+			)
+		}
+
+		indexes[model.LinkIndexName] = linkIndexResult.Unwrap()
+	}
+
+	for indexName, index := range indexes {
+		err := t.tableIndexValidator.ValidateTableIndex(indexName, &index, schema)
+
+		if err != nil {
+			return errs.NewInvalidTableIndexError(err)
+		}
+
+		indexes[indexName] = index
+	}
+
 	return t.tableCreator.CreateTable(
 		projectId,
 		name,
 		schema,
+		indexes,
 	)
 }
 
+// Tables can reference themselves, but can't be deleted while another table
+// references them
 func (t *tableManager) DeleteTable(projectId model.ProjectId, name model.TableName) error {
+	tableSchemasResult := t.tableSchemaFetcher.FetchTableSchemas(projectId)
+
+	if tableSchemasResult.IsErr() {
+		return fmt.Errorf("error fetching table schemas: %w", tableSchemasResult.UnwrapErr())
+	}
+
+	referencingFields := []string{}
+
+	for tableName, tableSchema := range tableSchemasResult.Unwrap() {
+		if tableName == name {
+			continue
+		}
+
+		for fieldName, fieldDefinition := range tableSchema {
+			if fieldDefinition.References == optional.Some(name) {
+				referencingFields = append(referencingFields, tableName.String()+"."+fieldName.String())
+			}
+		}
+	}
+
+	if len(referencingFields) > 0 {
+		sort.Strings(referencingFields)
+
+		return errs.NewTableReferencedError(referencingFields)
+	}
+
 	return t.tableDeleter.DeleteTable(projectId, name)
 }
 
+// Checks that the tables referenced by a schema's reference fields exist. A
+// table being created can reference itself
+func (t *tableManager) validateReferencedTables(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	schema model.TableSchema,
+) error {
+	for fieldName, fieldDefinition := range schema {
+		if fieldDefinition.References.IsNone() || fieldDefinition.References.Unwrap() == tableName {
+			continue
+		}
+
+		referencedTableName := fieldDefinition.References.Unwrap()
+		tableSchemaResult := t.tableSchemaFetcher.FetchTableSchema(projectId, referencedTableName)
+
+		if tableSchemaResult.IsErr() {
+			err := tableSchemaResult.UnwrapErr()
+
+			if _, ok := err.(errs.TableNotFoundError); ok {
+				return errs.NewInvalidTableError(fmt.Errorf(
+					"field \"%s\" references table \"%s\" which does not exist",
+					fieldName,
+					referencedTableName,
+				))
+			}
+
+			return fmt.Errorf("error fetching referenced table schema: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (t *tableManager) AddField(
 	projectId model.ProjectId,
 	tableName model.TableName,
@@ -156,7 +384,36 @@ func (t *tableManager) AddField(
 		return fmt.Errorf("error fetching table schema: %w", tableSchemaResult.UnwrapErr())
 	}
 
-	err := t.tableFieldAdder.AddTableField(
+	newSchema := util.CopyMap(tableSchemaResult.Unwrap())
+	newSchema[name] = definition
+
+	err := t.tableSchemaValidator.ValidateTableSchema(newSchema)
+
+	if err != nil {
+		return errs.NewInvalidTableError(err)
+	}
+
+	err = t.validateReferencedTables(projectId, tableName, model.TableSchema{name: definition})
+
+	if err != nil {
+		return err
+	}
+
+	if !definition.IsOptional {
+		// Validating the default as a single field entity checks it against the
+		// definition's constraints and converts it into the field's model type
+		defaultEntity := model.Entity{name: defaultValue.Unwrap()}
+
+		err = t.entityValidator.ValidateEntity(defaultEntity, model.TableSchema{name: definition})
+
+		if err != nil {
+			return errs.NewInvalidDefaultValueError(err)
+		}
+
+		defaultValue = optional.Some[any](defaultEntity[name])
+	}
+
+	err = t.tableFieldAdder.AddTableField(
 		projectId,
 		tableName,
 		name,
@@ -191,10 +448,406 @@ func (t *tableManager) DeleteField(
 		return errs.FieldNotFoundError{}
 	}
 
+	tableIndexesResult := t.tableIndexFetcher.FetchTableIndexes(projectId, tableName)
+
+	if tableIndexesResult.IsErr() {
+		return fmt.Errorf("error fetching table indexes: %w", tableIndexesResult.UnwrapErr())
+	}
+
+	// Postgres drops indexes on a column along with it, but indexes that only
+	// filter on it would otherwise be left behind, and so would the rows
+	// recording either kind
+	indexNames := []model.IndexName{}
+
+	for indexName, index := range tableIndexesResult.Unwrap() {
+		if index.ReferencesField(name) {
+			indexNames = append(indexNames, indexName)
+		}
+	}
+
 	return t.tableFieldDeleter.DeleteField(
 		projectId,
 		tableName,
 		tableSchemaResult.Unwrap(),
 		name,
+		indexNames,
+	)
+}
+
+func (t *tableManager) RenameField(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+	newName model.FieldName,
+) error {
+	tableSchemaResult := t.tableSchemaFetcher.FetchTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		err := tableSchemaResult.UnwrapErr()
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			return err
+		}
+
+		return fmt.Errorf("error fetching table schema: %w", err)
+	}
+
+	tableSchema := tableSchemaResult.Unwrap()
+
+	if _, ok := tableSchema[name]; !ok {
+		return errs.FieldNotFoundError{}
+	}
+
+	if _, ok := tableSchema[newName]; ok {
+		return errs.FieldAlreadyExistsError{}
+	}
+
+	newSchema := util.CopyMap(tableSchema)
+	newSchema[newName] = newSchema[name]
+	delete(newSchema, name)
+
+	err := t.tableSchemaValidator.ValidateTableSchema(newSchema)
+
+	if err != nil {
+		return errs.NewInvalidTableError(err)
+	}
+
+	tableIndexesResult := t.tableIndexFetcher.FetchTableIndexes(projectId, tableName)
+
+	if tableIndexesResult.IsErr() {
+		return fmt.Errorf("error fetching table indexes: %w", tableIndexesResult.UnwrapErr())
+	}
+
+	return t.tableFieldRenamer.RenameField(
+		projectId,
+		tableName,
+		tableSchema,
+		tableIndexesResult.Unwrap(),
+		name,
+		newName,
 	)
 }
+
+// References to the table from other tables, and from itself, are moved onto
+// the new name along with it
+func (t *tableManager) RenameTable(
+	projectId model.ProjectId,
+	name model.TableName,
+	newName model.TableName,
+) error {
+	tableSchemasResult := t.tableSchemaFetcher.FetchTableSchemas(projectId)
+
+	if tableSchemasResult.IsErr() {
+		return fmt.Errorf("error fetching table schemas: %w", tableSchemasResult.UnwrapErr())
+	}
+
+	tableSchemas := tableSchemasResult.Unwrap()
+
+	if _, ok := tableSchemas[name]; !ok {
+		return errs.TableNotFoundError{}
+	}
+
+	if _, ok := tableSchemas[newName]; ok {
+		return errs.TableAlreadyExistsError{}
+	}
+
+	referencingSchemas := model.TableSchemas{}
+
+	for tableName, tableSchema := range tableSchemas {
+		for fieldName, fieldDefinition := range tableSchema {
+			if fieldDefinition.References != optional.Some(name) {
+				continue
+			}
+
+			if _, ok := referencingSchemas[tableName]; !ok {
+				referencingSchemas[tableName] = util.CopyMap(tableSchema)
+			}
+
+			fieldDefinition.References = optional.Some(newName)
+			referencingSchemas[tableName][fieldName] = fieldDefinition
+		}
+	}
+
+	tableIndexesResult := t.tableIndexFetcher.FetchTableIndexes(projectId, name)
+
+	if tableIndexesResult.IsErr() {
+		return fmt.Errorf("error fetching table indexes: %w", tableIndexesResult.UnwrapErr())
+	}
+
+	return t.tableRenamer.RenameTable(
+		projectId,
+		name,
+		newName,
+		tableIndexesResult.Unwrap(),
+		referencingSchemas,
+	)
+}
+
+// Every entity's value is checked against the new definition before the
+// field is altered, and the field is only altered if they can all be
+// converted. Dry runs stop after the check
+func (t *tableManager) AlterField(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	fieldAlterationRequest model.FieldAlterationRequest,
+) result.R[model.FieldAlteration] {
+	name := fieldAlterationRequest.Name
+	newDefinition := fieldAlterationRequest.Definition
+	backfillValue := fieldAlterationRequest.BackfillValue
+
+	tableSchemaResult := t.tableSchemaFetcher.FetchTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		err := tableSchemaResult.UnwrapErr()
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			return result.Err[model.FieldAlteration](err)
+		}
+
+		return result.Errf[model.FieldAlteration]("error fetching table schema: %w", err)
+	}
+
+	tableSchema := tableSchemaResult.Unwrap()
+
+	definition, ok := tableSchema[name]
+
+	if !ok {
+		return result.Err[model.FieldAlteration](errs.FieldNotFoundError{})
+	}
+
+	newSchema := util.CopyMap(tableSchema)
+	newSchema[name] = newDefinition
+
+	err := t.tableSchemaValidator.ValidateTableSchema(newSchema)
+
+	if err != nil {
+		return result.Err[model.FieldAlteration](errs.NewInvalidTableError(err))
+	}
+
+	tableIndexesResult := t.tableIndexFetcher.FetchTableIndexes(projectId, tableName)
+
+	if tableIndexesResult.IsErr() {
+		return result.Errf[model.FieldAlteration]("error fetching table indexes: %w", tableIndexesResult.UnwrapErr())
+	}
+
+	err = t.fieldAlterationValidator.ValidateFieldAlteration(name, definition, newDefinition, tableIndexesResult.Unwrap())
+
+	if err != nil {
+		return result.Err[model.FieldAlteration](errs.NewInvalidFieldAlterationError(err))
+	}
+
+	if backfillValue.IsSome() {
+		// As with defaults, validating the backfill as a single field entity
+		// converts it into the field's model type
+		backfillEntity := model.Entity{name: backfillValue.Unwrap()}
+
+		err = t.entityValidator.ValidateEntity(backfillEntity, model.TableSchema{name: newDefinition})
+
+		if err != nil {
+			return result.Err[model.FieldAlteration](errs.NewInvalidBackfillValueError(err))
+		}
+
+		backfillValue = optional.Some[any](backfillEntity[name])
+	}
+
+	// Values are checked by the alterer as it reads them, so that it can do
+	// so in the same transaction as it alters the field
+	checkStoredFieldValue := func(storedFieldValue model.StoredFieldValue) error {
+		if storedFieldValue.Value == nil {
+			if backfillValue.IsNone() && !newDefinition.IsOptional {
+				return fmt.Errorf("value is null and no backfill value was given")
+			}
+
+			return nil
+		}
+
+		convertedFieldResult := t.fieldAlterationValidator.ConvertStoredField(
+			storedFieldValue,
+			name,
+			definition,
+			newDefinition,
+		)
+
+		if convertedFieldResult.IsErr() {
+			return convertedFieldResult.UnwrapErr()
+		}
+
+		return nil
+	}
+
+	fieldAlterationResult := t.tableFieldAlterer.AlterField(
+		projectId,
+		tableName,
+		tableSchema,
+		name,
+		newDefinition,
+		backfillValue,
+		fieldAlterationRequest.DryRun,
+		checkStoredFieldValue,
+	)
+
+	if fieldAlterationResult.IsErr() {
+		err := fieldAlterationResult.UnwrapErr()
+
+		if _, ok := err.(errs.FieldConversionError); ok {
+			return fieldAlterationResult
+		}
+
+		// The field may have been changed or deleted since it was checked
+		if _, ok := err.(errs.InvalidFieldAlterationError); ok {
+			return fieldAlterationResult
+		}
+
+		if _, ok := err.(errs.FieldNotFoundError); ok {
+			return fieldAlterationResult
+		}
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			return fieldAlterationResult
+		}
+
+		return result.Errf[model.FieldAlteration]("error altering field: %w", err)
+	}
+
+	return fieldAlterationResult
+}
+
+// Converts any time fields still stored without a timezone, returning the
+// fields that were converted
+func (t *tableManager) MigrateTimeFields(
+	projectId model.ProjectId,
+	tableName model.TableName,
+) result.R[[]model.FieldName] {
+	tableSchemaResult := t.tableSchemaFetcher.FetchTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		err := tableSchemaResult.UnwrapErr()
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			return result.Err[[]model.FieldName](err)
+		}
+
+		return result.Errf[[]model.FieldName]("error fetching table schema: %w", err)
+	}
+
+	return t.tableTimeFieldMigrator.MigrateTimeFields(projectId, tableName, tableSchemaResult.Unwrap())
+}
+
+func (t *tableManager) GetTableIndexes(
+	projectId model.ProjectId,
+	tableName model.TableName,
+) result.R[model.TableIndexes] {
+	tableSchemaResult := t.tableSchemaFetcher.FetchTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		err := tableSchemaResult.UnwrapErr()
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			return result.Err[model.TableIndexes](err)
+		}
+
+		return result.Errf[model.TableIndexes]("error fetching table schema: %w", err)
+	}
+
+	tableIndexesResult := t.tableIndexFetcher.FetchTableIndexes(projectId, tableName)
+
+	if tableIndexesResult.IsErr() {
+		return result.Errf[model.TableIndexes]("error fetching table indexes: %w", tableIndexesResult.UnwrapErr())
+	}
+
+	return tableIndexesResult
+}
+
+func (t *tableManager) AddIndex(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.IndexName,
+	index model.TableIndex,
+) error {
+	tableSchemaResult := t.tableSchemaFetcher.FetchTableSchema(projectId, tableName)
+
+	if tableSchemaResult.IsErr() {
+		err := tableSchemaResult.UnwrapErr()
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			return err
+		}
+
+		return fmt.Errorf("error fetching table schema: %w", err)
+	}
+
+	err := t.tableIndexValidator.ValidateTableIndex(name, &index, tableSchemaResult.Unwrap())
+
+	if err != nil {
+		return errs.NewInvalidTableIndexError(err)
+	}
+
+	tableIndexesResult := t.tableIndexFetcher.FetchTableIndexes(projectId, tableName)
+
+	if tableIndexesResult.IsErr() {
+		return fmt.Errorf("error fetching table indexes: %w", tableIndexesResult.UnwrapErr())
+	}
+
+	if _, ok := tableIndexesResult.Unwrap()[name]; ok {
+		return errs.IndexAlreadyExistsError{}
+	}
+
+	return t.tableIndexCreator.CreateIndex(projectId, tableName, name, index)
+}
+
+func (t *tableManager) DeleteIndex(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.IndexName,
+) error {
+	tableIndexesResult := t.GetTableIndexes(projectId, tableName)
+
+	if tableIndexesResult.IsErr() {
+		return tableIndexesResult.UnwrapErr()
+	}
+
+	index, ok := tableIndexesResult.Unwrap()[name]
+
+	if !ok {
+		return errs.IndexNotFoundError{}
+	}
+
+	// The link index is what marks a link table, so it's kept for as long as
+	// the table is
+	if index.Link {
+		return errs.NewInvalidTableIndexError(fmt.Errorf("the link index of a link table can't be deleted"))
+	}
+
+	return t.tableIndexDeleter.DeleteIndex(projectId, tableName, name)
+}
+
+// Link tables get a unique index over both their references, so that each
+// pair of entities is only linked once. It also marks them as link tables
+func getLinkIndex(schema model.TableSchema) result.R[model.TableIndex] {
+	fieldNames := util.GetSortedMapKeys(schema)
+
+	if len(fieldNames) != 2 {
+		return result.Errf[model.TableIndex]("link tables must have exactly two fields")
+	}
+
+	for _, fieldName := range fieldNames {
+		fieldDefinition := schema[fieldName]
+
+		if fieldDefinition.Type != model.FieldTypeReference || fieldDefinition.IsOptional {
+			return result.Errf[model.TableIndex]("link table field: \"%s\" must be a required reference", fieldName)
+		}
+	}
+
+	// Links are looked up from one side at a time, which for a table linked
+	// to itself would make each link only visible from the entity it was
+	// created from
+	if schema[fieldNames[0]].References == schema[fieldNames[1]].References {
+		return result.Errf[model.TableIndex]("link table fields must reference different tables")
+	}
+
+	return result.Ok(model.TableIndex{
+		Fields: fieldNames,
+		Unique: true,
+		Link:   true,
+	})
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"reflect"
+	"testing"
+)
+
+func TestGetLinkIndex(t *testing.T) {
+	reference := func(tableName model.TableName) model.FieldDefinition {
+		return model.FieldDefinition{Type: model.FieldTypeReference, References: optional.Some(tableName)}
+	}
+
+	tests := []struct {
+		name          string
+		schema        model.TableSchema
+		expectedIndex model.TableIndex
+		expectErr     bool
+	}{
+		{
+			name:   "two required references",
+			schema: model.TableSchema{"tag": reference("tags"), "post": reference("posts")},
+			expectedIndex: model.TableIndex{
+				Fields: []model.FieldName{"post", "tag"},
+				Unique: true,
+				Link:   true,
+			},
+		},
+		{
+			name:      "one reference",
+			schema:    model.TableSchema{"post": reference("posts")},
+			expectErr: true,
+		},
+		{
+			name: "a third field",
+			schema: model.TableSchema{
+				"tag":   reference("tags"),
+				"post":  reference("posts"),
+				"count": {Type: model.FieldTypeInteger},
+			},
+			expectErr: true,
+		},
+		{
+			name: "an optional reference",
+			schema: model.TableSchema{
+				"tag":  reference("tags"),
+				"post": {Type: model.FieldTypeReference, References: optional.Some[model.TableName]("posts"), IsOptional: true},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "two references to the same table",
+			schema:    model.TableSchema{"follower": reference("users"), "followed": reference("users")},
+			expectErr: true,
+		},
+		{
+			name:      "a field that isn't a reference",
+			schema:    model.TableSchema{"tag": reference("tags"), "post": {Type: model.FieldTypeId}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			linkIndexResult := getLinkIndex(test.schema)
+
+			if test.expectErr {
+				if linkIndexResult.IsOk() {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+
+			if linkIndexResult.IsErr() {
+				t.Fatalf("unexpected error: %s", linkIndexResult.UnwrapErr())
+			}
+
+			if !reflect.DeepEqual(linkIndexResult.Unwrap(), test.expectedIndex) {
+				t.Errorf("unexpected index:\n got: %+v\nwant: %+v", linkIndexResult.Unwrap(), test.expectedIndex)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"fmt"
+)
+
+var arrayElementTypes = []model.FieldType{
+	model.FieldTypeString,
+	model.FieldTypeInteger,
+	model.FieldTypeEnum,
+}
+
+func validateArrayDefinition(fieldName model.FieldName, fieldDefinition model.FieldDefinition) error {
+	if fieldDefinition.Type != model.FieldTypeArray {
+		if fieldDefinition.ElementType.IsSome() {
+			return fmt.Errorf("non array type definition \"%s\" has an element type", fieldName)
+		}
+
+		return nil
+	}
+
+	if fieldDefinition.ElementType.IsNone() {
+		return fmt.Errorf("array type definition \"%s\" must include an element type", fieldName)
+	}
+
+	elementType := fieldDefinition.ElementType.Unwrap()
+
+	if !util.Contains(arrayElementTypes, elementType) {
+		return fmt.Errorf(
+			"array type definition \"%s\" has unsupported element type \"%s\"",
+			fieldName,
+			elementType.String(),
+		)
+	}
+
+	return nil
+}
+
+func isEnumDefinition(fieldDefinition model.FieldDefinition) bool {
+	return fieldDefinition.Type == model.FieldTypeEnum ||
+		fieldDefinition.ElementType == optional.Some(model.FieldTypeEnum)
+}
+
+func getArrayElementDefinition(fieldDefinition model.FieldDefinition) model.FieldDefinition {
+	return model.FieldDefinition{
+		Type:   fieldDefinition.ElementType.Unwrap(),
+		Values: fieldDefinition.Values,
+	}
+}
+
+// Converts validated elements into a slice of the element type, which is
+// how array fields are held once validated
+func getArrayValue(elements []any, elementType model.FieldType) any {
+	if elementType == model.FieldTypeInteger {
+		integers := []int{}
+
+		for _, element := range elements {
+			integers = append(integers, element.(int))
+		}
+
+		return integers
+	}
+
+	strs := []string{}
+
+	for _, element := range elements {
+		strs = append(strs, element.(string))
+	}
+
+	return strs
+}
+
+func parseIncomingArray(field any, fieldDefinition model.FieldDefinition) result.R[any] {
+	elements, ok := field.([]any)
+
+	if !ok {
+		return result.Errf[any]("value is not an array")
+	}
+
+	elementDefinition := getArrayElementDefinition(fieldDefinition)
+	parsedElements := []any{}
+
+	for i, element := range elements {
+		switch elementDefinition.Type {
+		case model.FieldTypeInteger:
+			integer, ok := parseIncomingInteger(element)
+
+			if !ok {
+				return result.Errf[any]("element %d is not a valid integer", i)
+			}
+
+			if !isIntegerInRange(integer) {
+				return result.Errf[any]("element %d is not a valid integer, %w", i, errIntegerRange)
+			}
+
+			parsedElements = append(parsedElements, integer)
+		case model.FieldTypeString:
+			str, ok := element.(string)
+
+			if !ok {
+				return result.Errf[any]("element %d is not a valid string", i)
+			}
+
+			parsedElements = append(parsedElements, str)
+		case model.FieldTypeEnum:
+			str, ok := element.(string)
+
+			if !ok || !util.Contains(elementDefinition.Values.Unwrap(), str) {
+				return result.Errf[any](
+					"element %d is not a supported value. supported values: %v",
+					i,
+					elementDefinition.Values.Unwrap(),
+				)
+			}
+
+			parsedElements = append(parsedElements, str)
+		default:
+			panic(fmt.Sprintf("invalid array element type has entered the system: %+v", elementDefinition.Type))
+		}
+	}
+
+	return result.Ok(getArrayValue(parsedElements, elementDefinition.Type))
+}
+
+// PATCH requests can replace an array outright, or change it in place with
+// an object of the form {"append": [...]} or {"remove": [...]}
+func parseIncomingArrayUpdate(field any, fieldDefinition model.FieldDefinition) result.R[any] {
+	update, ok := field.(map[string]any)
+
+	if !ok {
+		return parseIncomingArray(field, fieldDefinition)
+	}
+
+	if len(update) != 1 {
+		return result.Errf[any]("array update must have exactly one of: append, remove")
+	}
+
+	for k, v := range update {
+		var arrayUpdateType model.ArrayUpdateType
+
+		switch k {
+		case "append":
+			arrayUpdateType = model.ArrayUpdateTypeAppend
+		case "remove":
+			arrayUpdateType = model.ArrayUpdateTypeRemove
+		default:
+			return result.Errf[any]("unrecognised array update: %s", k)
+		}
+
+		elementsResult := parseIncomingArray(v, fieldDefinition)
+
+		if elementsResult.IsErr() {
+			return result.Errf[any]("error parsing %s: %w", k, elementsResult.UnwrapErr())
+		}
+
+		return result.Ok[any](model.ArrayUpdate{
+			Type:     arrayUpdateType,
+			Elements: elementsResult.Unwrap(),
+		})
+	}
+
+	panic("array update had no entries.")
+}
+
+// Array fields can be filtered on their number of elements, using the path
+// "length" (e.g. tags.length>=2)
+var arrayLengthFilterTypes = []model.FieldFilterType{
+	model.FieldFilterTypeEquals,
+	model.FieldFilterTypeNotEquals,
+	model.FieldFilterTypeGreaterThan,
+	model.FieldFilterTypeGreaterThanEq,
+	model.FieldFilterTypeLessThan,
+	model.FieldFilterTypeLessThanEq,
+	model.FieldFilterTypeIn,
+}
+
+func validateArrayLengthFilter(parsedFieldFilter *model.FieldFilter) error {
+	if len(parsedFieldFilter.Path) != 1 || parsedFieldFilter.Path[0] != "length" {
+		return fmt.Errorf("array fields can only be filtered on their length")
+	}
+
+	if !util.Contains(arrayLengthFilterTypes, parsedFieldFilter.Type) {
+		return fmt.Errorf(
+			"filter: \"%s\" is not valid for an array length",
+			parsedFieldFilter.Type.String(),
+		)
+	}
+
+	parsedFieldFilter.Path = nil
+	parsedFieldFilter.Length = true
+
+	if parsedFieldFilter.Type == model.FieldFilterTypeIn {
+		comparators, ok := parsedFieldFilter.Comparator.([]string)
+
+		if !ok {
+			panic("in comparator was not a string slice.")
+		}
+
+		parsedComparators := []any{}
+
+		for _, comparator := range comparators {
+			length, ok := parseIntegerComparator(comparator)
+
+			if !ok {
+				return fmt.Errorf("filter comparator is not a valid integer: %s", comparator)
+			}
+
+			if !isIntegerInRange(length) {
+				return fmt.Errorf("filter comparator is not a valid integer: %s, %w", comparator, errIntegerRange)
+			}
+
+			parsedComparators = append(parsedComparators, length)
+		}
+
+		parsedFieldFilter.Comparator = parsedComparators
+
+		return nil
+	}
+
+	comparator, ok := parsedFieldFilter.Comparator.(string)
+
+	if !ok {
+		panic("comparator was not a string.")
+	}
+
+	length, ok := parseIntegerComparator(comparator)
+
+	if !ok {
+		return fmt.Errorf("filter comparator is not a valid integer: %s", comparator)
+	}
+
+	if !isIntegerInRange(length) {
+		return fmt.Errorf("filter comparator is not a valid integer: %s, %w", comparator, errIntegerRange)
+	}
+
+	parsedFieldFilter.Comparator = length
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+var boundedNumberTypes = []model.FieldType{
+	model.FieldTypeInteger,
+	model.FieldTypeBigInt,
+	model.FieldTypeFloat,
+	model.FieldTypeDecimal,
+}
+
+func validateBoundsDefinition(fieldName model.FieldName, fieldDefinition model.FieldDefinition) error {
+	hasNumberBounds := fieldDefinition.Min.IsSome() || fieldDefinition.Max.IsSome()
+	hasTimeBounds := fieldDefinition.NotBefore.IsSome() || fieldDefinition.NotAfter.IsSome()
+
+	if hasNumberBounds && !util.Contains(boundedNumberTypes, fieldDefinition.Type) {
+		return fmt.Errorf("non numeric type definition \"%s\" has a min or max", fieldName)
+	}
+
+	if hasTimeBounds && fieldDefinition.Type != model.FieldTypeTime {
+		return fmt.Errorf("non time type definition \"%s\" has a notBefore or notAfter", fieldName)
+	}
+
+	bounds := map[string]optional.O[model.Decimal]{
+		"min": fieldDefinition.Min,
+		"max": fieldDefinition.Max,
+	}
+
+	for boundName, bound := range bounds {
+		if bound.IsNone() {
+			continue
+		}
+
+		boundRat, ok := getBoundRat(bound.Unwrap())
+
+		if !ok {
+			return fmt.Errorf("type definition \"%s\" has a %s that is not a number", fieldName, boundName)
+		}
+
+		isIntegerType := fieldDefinition.Type == model.FieldTypeInteger || fieldDefinition.Type == model.FieldTypeBigInt
+
+		if isIntegerType && !boundRat.IsInt() {
+			return fmt.Errorf("integer type definition \"%s\" has a %s that is not an integer", fieldName, boundName)
+		}
+	}
+
+	if fieldDefinition.Min.IsSome() && fieldDefinition.Max.IsSome() {
+		min, _ := getBoundRat(fieldDefinition.Min.Unwrap())
+		max, _ := getBoundRat(fieldDefinition.Max.Unwrap())
+
+		if min.Cmp(max) > 0 {
+			return fmt.Errorf("type definition \"%s\" has a min greater than its max", fieldName)
+		}
+	}
+
+	if fieldDefinition.NotBefore.IsSome() && fieldDefinition.NotAfter.IsSome() {
+		if fieldDefinition.NotBefore.Unwrap().After(fieldDefinition.NotAfter.Unwrap()) {
+			return fmt.Errorf("time type definition \"%s\" has a notBefore after its notAfter", fieldName)
+		}
+	}
+
+	return nil
+}
+
+func getBoundRat(bound model.Decimal) (*big.Rat, bool) {
+	decimalResult := parseDecimal(bound.String(), optional.None[uint](), optional.None[uint]())
+
+	if decimalResult.IsErr() {
+		return nil, false
+	}
+
+	return new(big.Rat).SetString(decimalResult.Unwrap().String())
+}
+
+// Checks a validated number field against the min and max of its definition
+func validateNumberBounds(field any, fieldDefinition model.FieldDefinition) error {
+	if fieldDefinition.Min.IsNone() && fieldDefinition.Max.IsNone() {
+		return nil
+	}
+
+	var value *big.Rat
+
+	switch v := field.(type) {
+	case int:
+		value = new(big.Rat).SetInt64(int64(v))
+	case int64:
+		value = new(big.Rat).SetInt64(v)
+	case float64:
+		value = new(big.Rat).SetFloat64(v)
+	case model.Decimal:
+		value, _ = new(big.Rat).SetString(v.String())
+	}
+
+	if value == nil {
+		panic(fmt.Sprintf("bounded field has unsupported type: %T", field))
+	}
+
+	if fieldDefinition.Min.IsSome() {
+		min, _ := getBoundRat(fieldDefinition.Min.Unwrap())
+
+		if value.Cmp(min) < 0 {
+			return fmt.Errorf("value %s is less than the minimum of %s", formatBoundValue(field), fieldDefinition.Min.Unwrap())
+		}
+	}
+
+	if fieldDefinition.Max.IsSome() {
+		max, _ := getBoundRat(fieldDefinition.Max.Unwrap())
+
+		if value.Cmp(max) > 0 {
+			return fmt.Errorf("value %s is greater than the maximum of %s", formatBoundValue(field), fieldDefinition.Max.Unwrap())
+		}
+	}
+
+	return nil
+}
+
+func formatBoundValue(field any) string {
+	if float, ok := field.(float64); ok {
+		return strconv.FormatFloat(float, 'f', -1, 64)
+	}
+
+	return fmt.Sprintf("%v", field)
+}
+
+// Checks a validated time field against the notBefore and notAfter of its
+// definition
+func validateTimeBounds(value time.Time, fieldDefinition model.FieldDefinition) error {
+	if fieldDefinition.NotBefore.IsSome() && value.Before(fieldDefinition.NotBefore.Unwrap()) {
+		return fmt.Errorf(
+			"value %s is before %s",
+			value.Format(time.RFC3339Nano),
+			fieldDefinition.NotBefore.Unwrap().Format(time.RFC3339Nano),
+		)
+	}
+
+	if fieldDefinition.NotAfter.IsSome() && value.After(fieldDefinition.NotAfter.Unwrap()) {
+		return fmt.Errorf(
+			"value %s is after %s",
+			value.Format(time.RFC3339Nano),
+			fieldDefinition.NotAfter.Unwrap().Format(time.RFC3339Nano),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"crudly/model"
+	"regexp"
+	"time"
+)
+
+const IncomingDateFormat = "2006-01-02"
+
+func parseIncomingDate(field any) (model.Date, bool) {
+	str, ok := field.(string)
+
+	if !ok {
+		return "", false
+	}
+
+	date, err := time.Parse(IncomingDateFormat, str)
+
+	if err != nil {
+		return "", false
+	}
+
+	return model.Date(date.Format(IncomingDateFormat)), true
+}
+
+// ISO 8601 durations with designators, e.g. P1Y2M3DT4H5M6.5S or P2W, as
+// accepted by postgres. Each component may be signed and fractional, which
+// postgres also uses when writing them out. Components are limited to 9
+// digits to stay clear of postgres' interval range
+var durationPattern = regexp.MustCompile(
+	`^P(` + durationComponent + `Y)?(` + durationComponent + `M)?(` + durationComponent + `W)?(` +
+		durationComponent + `D)?(T(` + durationComponent + `H)?(` + durationComponent + `M)?(` +
+		durationComponent + `S)?)?$`,
+)
+
+const durationComponent = `[-+]?\d{1,9}(\.\d{1,6})?`
+
+func parseIncomingDuration(field any) (model.Duration, bool) {
+	str, ok := field.(string)
+
+	if !ok {
+		return "", false
+	}
+
+	// At least one component is needed, and a T must be followed by one
+	if !durationPattern.MatchString(str) || str == "P" || str[len(str)-1] == 'T' {
+		return "", false
+	}
+
+	return model.Duration(str), true
+}
@@ -4,7 +4,6 @@ import (
 	"crudly/model"
 	"crudly/util"
 	"fmt"
-	"math"
 
 	"github.com/google/uuid"
 )
@@ -61,7 +60,7 @@ func validateField(
 	}
 
 	switch fieldDefinition.Type {
-	case model.FieldTypeId:
+	case model.FieldTypeId, model.FieldTypeReference:
 		stringVal, ok := field.(string)
 
 		if !ok {
@@ -78,19 +77,71 @@ func validateField(
 
 		return nil
 	case model.FieldTypeInteger:
-		floatVal, ok := field.(float64)
+		integer, ok := parseIncomingInteger(field)
 
 		if !ok {
 			return fmt.Errorf("field: \"%s\" is not a valid integer", fieldName)
 		}
 
-		truncated := math.Trunc(floatVal)
+		if !isIntegerInRange(integer) {
+			return fmt.Errorf("field: \"%s\" is not a valid integer, %w", fieldName, errIntegerRange)
+		}
 
-		if truncated != floatVal {
-			return fmt.Errorf("field: \"%s\" is not a valid integer", fieldName)
+		err := validateNumberBounds(integer, fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
+		entity[fieldName] = integer
+
+		return nil
+	case model.FieldTypeBigInt:
+		bigInt, ok := parseIncomingBigInt(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid bigint", fieldName)
+		}
+
+		err := validateNumberBounds(bigInt, fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
+		entity[fieldName] = bigInt
+
+		return nil
+	case model.FieldTypeFloat:
+		float, ok := parseIncomingFloat(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid float", fieldName)
+		}
+
+		err := validateNumberBounds(float, fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
+		entity[fieldName] = float
+
+		return nil
+	case model.FieldTypeDecimal:
+		decimalResult := parseIncomingDecimal(field, fieldDefinition)
+
+		if decimalResult.IsErr() {
+			return fmt.Errorf("error parsing field \"%s\" as a decimal: %w", fieldName, decimalResult.UnwrapErr())
+		}
+
+		err := validateNumberBounds(decimalResult.Unwrap(), fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
 		}
 
-		entity[fieldName] = int(truncated)
+		entity[fieldName] = decimalResult.Unwrap()
 
 		return nil
 	case model.FieldTypeBoolean:
@@ -104,12 +155,18 @@ func validateField(
 
 		return nil
 	case model.FieldTypeString:
-		_, ok := field.(string)
+		str, ok := field.(string)
 
 		if !ok {
 			return fmt.Errorf("field: \"%s\" is not a valid string", fieldName)
 		}
 
+		err := validateString(str, fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
 		return nil
 	case model.FieldTypeTime:
 		stringVal, ok := field.(string)
@@ -124,8 +181,54 @@ func validateField(
 			return fmt.Errorf("error parsing field \"%s\" as time: %w", fieldName, timeResult.UnwrapErr())
 		}
 
+		err := validateTimeBounds(timeResult.Unwrap(), fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
 		entity[fieldName] = timeResult.Unwrap()
 
+		return nil
+	case model.FieldTypeJson:
+		jsonField, ok := parseIncomingJson(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid json object or array", fieldName)
+		}
+
+		entity[fieldName] = jsonField
+
+		return nil
+	case model.FieldTypeArray:
+		arrayResult := parseIncomingArray(field, fieldDefinition)
+
+		if arrayResult.IsErr() {
+			return fmt.Errorf("error parsing field \"%s\" as an array: %w", fieldName, arrayResult.UnwrapErr())
+		}
+
+		entity[fieldName] = arrayResult.Unwrap()
+
+		return nil
+	case model.FieldTypeDate:
+		date, ok := parseIncomingDate(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid date", fieldName)
+		}
+
+		entity[fieldName] = date
+
+		return nil
+	case model.FieldTypeDuration:
+		duration, ok := parseIncomingDuration(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid ISO 8601 duration", fieldName)
+		}
+
+		entity[fieldName] = duration
+
 		return nil
 	case model.FieldTypeEnum:
 		val, ok := field.(string)
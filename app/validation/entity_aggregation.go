@@ -0,0 +1,299 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
+	"fmt"
+	"time"
+
+	// Timezones are validated against an embedded database so validation
+	// doesn't depend on the host having zoneinfo installed
+	_ "time/tzdata"
+)
+
+type entityAggregationValidator struct{}
+
+func NewEntityAggregationValidator() entityAggregationValidator {
+	return entityAggregationValidator{}
+}
+
+func (e *entityAggregationValidator) ValidateEntityAggregation(
+	filterExpression model.EntityFilterExpression,
+	groupBy []model.FieldName,
+	entityAggregations model.EntityAggregations,
+	entityHistogram optional.O[model.EntityHistogram],
+	tableSchema model.TableSchema,
+) error {
+	if len(entityAggregations) == 0 {
+		return fmt.Errorf("at least one aggregation must be requested")
+	}
+
+	for i, fieldName := range groupBy {
+		if _, ok := tableSchema[fieldName]; !ok {
+			return fmt.Errorf("group by field: \"%s\" does not exist", fieldName)
+		}
+
+		if util.Contains(groupBy[:i], fieldName) {
+			return fmt.Errorf("group by field: \"%s\" is repeated", fieldName)
+		}
+	}
+
+	seenAggregations := []string{}
+
+	for _, entityAggregation := range entityAggregations {
+		if util.Contains(seenAggregations, entityAggregation.String()) {
+			return fmt.Errorf("aggregation: \"%s\" is repeated", entityAggregation.String())
+		}
+
+		seenAggregations = append(seenAggregations, entityAggregation.String())
+
+		if entityAggregation.FieldName.IsNone() {
+			if entityAggregation.Type != model.EntityAggregationTypeCount {
+				return fmt.Errorf("aggregation: \"%s\" requires a field", entityAggregation.Type.String())
+			}
+
+			continue
+		}
+
+		fieldName := entityAggregation.FieldName.Unwrap()
+
+		fieldDefinition, ok := tableSchema[fieldName]
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" does not exist", fieldName)
+		}
+
+		if !isValidEntityAggregation(fieldDefinition.Type, entityAggregation.Type) {
+			return fmt.Errorf(
+				"aggregation: \"%s\" is not valid for field type \"%s\"",
+				entityAggregation.Type.String(),
+				fieldDefinition.Type.String(),
+			)
+		}
+	}
+
+	if entityHistogram.IsSome() {
+		err := validateEntityHistogram(entityHistogram.Unwrap(), filterExpression, groupBy, tableSchema)
+
+		if err != nil {
+			return fmt.Errorf("error validating histogram: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (e *entityAggregationValidator) ValidateFieldValues(
+	fieldName model.FieldName,
+	tableSchema model.TableSchema,
+) error {
+	fieldDefinition, ok := tableSchema[fieldName]
+
+	if !ok {
+		return fmt.Errorf("field: \"%s\" does not exist", fieldName)
+	}
+
+	if fieldDefinition.Type != model.FieldTypeString && fieldDefinition.Type != model.FieldTypeEnum {
+		return fmt.Errorf(
+			"values can't be listed for field type \"%s\"",
+			fieldDefinition.Type.String(),
+		)
+	}
+
+	return nil
+}
+
+func validateEntityHistogram(
+	entityHistogram model.EntityHistogram,
+	filterExpression model.EntityFilterExpression,
+	groupBy []model.FieldName,
+	tableSchema model.TableSchema,
+) error {
+	fieldName := entityHistogram.FieldName
+
+	fieldDefinition, ok := tableSchema[fieldName]
+
+	if !ok {
+		return fmt.Errorf("field: \"%s\" does not exist", fieldName)
+	}
+
+	if fieldDefinition.Type != model.FieldTypeTime {
+		return fmt.Errorf("field: \"%s\" is not a time field", fieldName)
+	}
+
+	if util.Contains(groupBy, fieldName) {
+		return fmt.Errorf("field: \"%s\" can't also be grouped by", fieldName)
+	}
+
+	if entityHistogram.Unit.IsSome() == entityHistogram.Interval.IsSome() {
+		return fmt.Errorf("exactly one of a unit or a fixed interval must be given")
+	}
+
+	if entityHistogram.Interval.IsSome() {
+		interval := entityHistogram.Interval.Unwrap()
+
+		if interval < time.Second || interval%time.Second != 0 {
+			return fmt.Errorf("interval: \"%s\" must be a positive whole number of seconds", interval)
+		}
+	}
+
+	if entityHistogram.Timezone.IsSome() {
+		_, err := time.LoadLocation(entityHistogram.Timezone.Unwrap())
+
+		if err != nil || entityHistogram.Timezone.Unwrap() == "" || entityHistogram.Timezone.Unwrap() == "Local" {
+			return fmt.Errorf("timezone: \"%s\" is not a valid IANA timezone", entityHistogram.Timezone.Unwrap())
+		}
+	}
+
+	// Without both bounds the buckets span the matching entities, which
+	// aren't known until the histogram is fetched
+	lowerBound, upperBound := entityHistogram.GetBounds(filterExpression)
+
+	if lowerBound.IsSome() && upperBound.IsSome() {
+		bucketCount := getEntityHistogramBucketCount(entityHistogram, lowerBound.Unwrap(), upperBound.Unwrap())
+
+		if bucketCount > model.MaxHistogramBuckets {
+			return fmt.Errorf(
+				"histogram would have %d buckets, at most %d are allowed",
+				bucketCount,
+				model.MaxHistogramBuckets,
+			)
+		}
+	}
+
+	return nil
+}
+
+// Counts the buckets from the one holding start to the one holding end, laid
+// out on the wall clock of the histogram's timezone as the histogram query
+// does
+func getEntityHistogramBucketCount(entityHistogram model.EntityHistogram, start time.Time, end time.Time) int64 {
+	if end.Before(start) {
+		return 0
+	}
+
+	location := time.UTC
+
+	if entityHistogram.Timezone.IsSome() {
+		location, _ = time.LoadLocation(entityHistogram.Timezone.Unwrap())
+	}
+
+	startWallClock := getWallClockSeconds(start.In(location))
+	endWallClock := getWallClockSeconds(end.In(location))
+
+	if entityHistogram.Interval.IsSome() {
+		seconds := int64(entityHistogram.Interval.Unwrap() / time.Second)
+
+		return floorDiv(endWallClock, seconds) - floorDiv(startWallClock, seconds) + 1
+	}
+
+	switch entityHistogram.Unit.Unwrap() {
+	case model.HistogramUnitMinute:
+		return floorDiv(endWallClock, 60) - floorDiv(startWallClock, 60) + 1
+	case model.HistogramUnitHour:
+		return floorDiv(endWallClock, 60*60) - floorDiv(startWallClock, 60*60) + 1
+	case model.HistogramUnitDay:
+		return floorDiv(endWallClock, 24*60*60) - floorDiv(startWallClock, 24*60*60) + 1
+	case model.HistogramUnitWeek:
+		// The epoch was a Thursday and weeks start on a Monday
+		mondayOffset := int64(3 * 24 * 60 * 60)
+
+		return floorDiv(endWallClock+mondayOffset, 7*24*60*60) - floorDiv(startWallClock+mondayOffset, 7*24*60*60) + 1
+	case model.HistogramUnitMonth:
+		startLocal := start.In(location)
+		endLocal := end.In(location)
+
+		return int64(endLocal.Year()-startLocal.Year())*12 + int64(endLocal.Month()-startLocal.Month()) + 1
+	}
+	panic(fmt.Sprintf("invalid histogram unit has entered the system: %+v", entityHistogram.Unit.Unwrap()))
+}
+
+// Seconds since the epoch as read off a wall clock, so ignoring any jumps made
+// by daylight saving
+func getWallClockSeconds(t time.Time) int64 {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC).Unix()
+}
+
+func floorDiv(a int64, b int64) int64 {
+	if a%b != 0 && (a < 0) != (b < 0) {
+		return a/b - 1
+	}
+
+	return a / b
+}
+
+func isValidEntityAggregation(fieldType model.FieldType, entityAggregationType model.EntityAggregationType) bool {
+	validityMap := map[model.FieldType][]model.EntityAggregationType{
+		model.FieldTypeId: {
+			model.EntityAggregationTypeCount,
+		},
+		model.FieldTypeReference: {
+			model.EntityAggregationTypeCount,
+		},
+		model.FieldTypeInteger: {
+			model.EntityAggregationTypeCount,
+			model.EntityAggregationTypeSum,
+			model.EntityAggregationTypeAvg,
+			model.EntityAggregationTypeMin,
+			model.EntityAggregationTypeMax,
+		},
+		model.FieldTypeBigInt: {
+			model.EntityAggregationTypeCount,
+			model.EntityAggregationTypeSum,
+			model.EntityAggregationTypeAvg,
+			model.EntityAggregationTypeMin,
+			model.EntityAggregationTypeMax,
+		},
+		model.FieldTypeFloat: {
+			model.EntityAggregationTypeCount,
+			model.EntityAggregationTypeSum,
+			model.EntityAggregationTypeAvg,
+			model.EntityAggregationTypeMin,
+			model.EntityAggregationTypeMax,
+		},
+		model.FieldTypeDecimal: {
+			model.EntityAggregationTypeCount,
+			model.EntityAggregationTypeSum,
+			model.EntityAggregationTypeAvg,
+			model.EntityAggregationTypeMin,
+			model.EntityAggregationTypeMax,
+		},
+		model.FieldTypeString: {
+			model.EntityAggregationTypeCount,
+		},
+		model.FieldTypeBoolean: {
+			model.EntityAggregationTypeCount,
+		},
+		model.FieldTypeTime: {
+			model.EntityAggregationTypeCount,
+			model.EntityAggregationTypeMin,
+			model.EntityAggregationTypeMax,
+		},
+		model.FieldTypeEnum: {
+			model.EntityAggregationTypeCount,
+		},
+		model.FieldTypeJson: {
+			model.EntityAggregationTypeCount,
+		},
+		model.FieldTypeArray: {
+			model.EntityAggregationTypeCount,
+		},
+		model.FieldTypeDate: {
+			model.EntityAggregationTypeCount,
+			model.EntityAggregationTypeMin,
+			model.EntityAggregationTypeMax,
+		},
+		model.FieldTypeDuration: {
+			model.EntityAggregationTypeCount,
+			model.EntityAggregationTypeSum,
+			model.EntityAggregationTypeAvg,
+			model.EntityAggregationTypeMin,
+			model.EntityAggregationTypeMax,
+		},
+	}
+
+	validAggregations := validityMap[fieldType]
+
+	return util.Contains(validAggregations, entityAggregationType)
+}
@@ -0,0 +1,212 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"testing"
+	"time"
+)
+
+func getUnitHistogram(unit model.HistogramUnit, timezone optional.O[string]) model.EntityHistogram {
+	return model.EntityHistogram{
+		FieldName: "at",
+		Unit:      optional.Some(unit),
+		Interval:  optional.None[time.Duration](),
+		Timezone:  timezone,
+	}
+}
+
+func getIntervalHistogram(interval time.Duration) model.EntityHistogram {
+	return model.EntityHistogram{
+		FieldName: "at",
+		Unit:      optional.None[model.HistogramUnit](),
+		Interval:  optional.Some(interval),
+		Timezone:  optional.None[string](),
+	}
+}
+
+func mustParseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func TestGetEntityHistogramBucketCount(t *testing.T) {
+	utc := optional.None[string]()
+
+	tests := []struct {
+		name                string
+		entityHistogram     model.EntityHistogram
+		start               string
+		end                 string
+		expectedBucketCount int64
+	}{
+		{
+			name:                "end before start",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitDay, utc),
+			start:               "2023-01-02T00:00:00Z",
+			end:                 "2023-01-01T00:00:00Z",
+			expectedBucketCount: 0,
+		},
+		{
+			name:                "single bucket",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitDay, utc),
+			start:               "2023-01-01T00:00:00Z",
+			end:                 "2023-01-01T23:59:59Z",
+			expectedBucketCount: 1,
+		},
+		{
+			name:                "partial minutes at either end",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitMinute, utc),
+			start:               "2023-01-01T00:00:30Z",
+			end:                 "2023-01-01T00:02:10Z",
+			expectedBucketCount: 3,
+		},
+		{
+			name:                "hours",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitHour, utc),
+			start:               "2023-01-01T10:59:59Z",
+			end:                 "2023-01-01T11:00:00Z",
+			expectedBucketCount: 2,
+		},
+		{
+			name:                "weeks start on a monday",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitWeek, utc),
+			start:               "2023-01-01T12:00:00Z",
+			end:                 "2023-01-02T00:00:00Z",
+			expectedBucketCount: 2,
+		},
+		{
+			name:                "a whole week",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitWeek, utc),
+			start:               "2023-01-02T00:00:00Z",
+			end:                 "2023-01-08T23:59:59Z",
+			expectedBucketCount: 1,
+		},
+		{
+			name:                "months across a year",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitMonth, utc),
+			start:               "2022-11-30T00:00:00Z",
+			end:                 "2023-01-01T00:00:00Z",
+			expectedBucketCount: 3,
+		},
+		{
+			name:                "days on the wall clock of the timezone",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitDay, optional.Some("Asia/Tokyo")),
+			start:               "2023-01-01T23:30:00Z",
+			end:                 "2023-01-02T14:00:00Z",
+			expectedBucketCount: 1,
+		},
+		{
+			name:                "days across a daylight saving change",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitDay, optional.Some("America/New_York")),
+			start:               "2023-03-11T17:00:00Z",
+			end:                 "2023-03-13T16:00:00Z",
+			expectedBucketCount: 3,
+		},
+		{
+			name:                "hours across a daylight saving change",
+			entityHistogram:     getUnitHistogram(model.HistogramUnitHour, optional.Some("America/New_York")),
+			start:               "2023-03-12T06:30:00Z",
+			end:                 "2023-03-12T07:30:00Z",
+			expectedBucketCount: 3,
+		},
+		{
+			name:                "intervals",
+			entityHistogram:     getIntervalHistogram(15 * time.Minute),
+			start:               "2023-01-01T00:14:59Z",
+			end:                 "2023-01-01T00:15:00Z",
+			expectedBucketCount: 2,
+		},
+		{
+			name:                "intervals before the epoch",
+			entityHistogram:     getIntervalHistogram(15 * time.Minute),
+			start:               "1969-12-31T23:50:00Z",
+			end:                 "1970-01-01T00:05:00Z",
+			expectedBucketCount: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bucketCount := getEntityHistogramBucketCount(
+				test.entityHistogram,
+				mustParseTime(test.start),
+				mustParseTime(test.end),
+			)
+
+			if bucketCount != test.expectedBucketCount {
+				t.Errorf("unexpected bucket count: got %d, want %d", bucketCount, test.expectedBucketCount)
+			}
+		})
+	}
+}
+
+func TestValidateEntityAggregationHistogramBuckets(t *testing.T) {
+	tableSchema := model.TableSchema{
+		"at": {Type: model.FieldTypeTime},
+	}
+
+	entityAggregations := model.EntityAggregations{
+		{Type: model.EntityAggregationTypeCount, FieldName: optional.None[model.FieldName]()},
+	}
+
+	start := mustParseTime("2000-01-01T00:00:00Z")
+
+	getFilter := func(end time.Time) model.EntityFilterExpression {
+		return model.EntityFilter{
+			"at": {
+				{Type: model.FieldFilterTypeGreaterThanEq, Comparator: start},
+				{Type: model.FieldFilterTypeLessThan, Comparator: end},
+			},
+		}.ToExpression()
+	}
+
+	tests := []struct {
+		name             string
+		filterExpression model.EntityFilterExpression
+		expectErr        bool
+	}{
+		{
+			name:             "unbounded",
+			filterExpression: model.EmptyEntityFilterExpression(),
+			expectErr:        false,
+		},
+		{
+			name:             "at the maximum",
+			filterExpression: getFilter(start.AddDate(0, 0, model.MaxHistogramBuckets)),
+			expectErr:        false,
+		},
+		{
+			name:             "over the maximum",
+			filterExpression: getFilter(start.AddDate(0, 0, model.MaxHistogramBuckets+1)),
+			expectErr:        true,
+		},
+	}
+
+	validator := NewEntityAggregationValidator()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validator.ValidateEntityAggregation(
+				test.filterExpression,
+				[]model.FieldName{},
+				entityAggregations,
+				optional.Some(getUnitHistogram(model.HistogramUnitDay, optional.None[string]())),
+				tableSchema,
+			)
+
+			if test.expectErr && err == nil {
+				t.Errorf("expected an error")
+			}
+
+			if !test.expectErr && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
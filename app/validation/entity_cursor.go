@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"crudly/model"
+	"fmt"
+)
+
+type entityCursorValidator struct{}
+
+func NewEntityCursorValidator() entityCursorValidator {
+	return entityCursorValidator{}
+}
+
+// Checks the cursor was generated for the same orders and converts its raw
+// values into their typed field values
+func (e *entityCursorValidator) ValidateEntityCursor(
+	entityCursor model.EntityCursor,
+	entityOrders model.EntityOrders,
+	tableSchema model.TableSchema,
+) error {
+	if len(entityCursor) != len(entityOrders) {
+		return fmt.Errorf("cursor does not match the requested order")
+	}
+
+	for index, entityOrder := range entityOrders {
+		cursorKey := entityCursor[index]
+
+		if cursorKey.FieldName != entityOrder.FieldName || cursorKey.Type != entityOrder.Type {
+			return fmt.Errorf("cursor does not match the requested order")
+		}
+
+		fieldDefinition, ok := tableSchema[cursorKey.FieldName]
+
+		if cursorKey.FieldName == "id" {
+			fieldDefinition, ok = model.FieldDefinition{Type: model.FieldTypeId}, true
+		}
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" does not exist", cursorKey.FieldName)
+		}
+
+		entity := model.Entity{cursorKey.FieldName: cursorKey.Value}
+
+		err := validateField(entity, cursorKey.FieldName, fieldDefinition)
+
+		if err != nil {
+			return err
+		}
+
+		entityCursor[index].Value = entity[cursorKey.FieldName]
+	}
+
+	return nil
+}
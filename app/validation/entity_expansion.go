@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
+	"fmt"
+)
+
+type entityExpansionValidator struct{}
+
+func NewEntityExpansionValidator() entityExpansionValidator {
+	return entityExpansionValidator{}
+}
+
+func (e *entityExpansionValidator) ValidateEntityExpansion(entityExpansion model.EntityExpansion) error {
+	for _, expansionPath := range entityExpansion {
+		if len(expansionPath) == 0 {
+			return fmt.Errorf("expansion paths must not be empty")
+		}
+
+		if len(expansionPath) > model.MaxExpansionDepth {
+			return fmt.Errorf(
+				"expansion path \"%s\" is deeper than the maximum of %d",
+				getExpansionPathString(expansionPath),
+				model.MaxExpansionDepth,
+			)
+		}
+	}
+
+	return nil
+}
+
+// Expanded fields have to be references, and have to be returned when only
+// some fields are
+func (e *entityExpansionValidator) ValidateExpandedField(
+	fieldName model.FieldName,
+	tableSchema model.TableSchema,
+	fieldProjection optional.O[model.FieldProjection],
+) error {
+	fieldDefinition, ok := tableSchema[fieldName]
+
+	if !ok {
+		return fmt.Errorf("field: \"%s\" does not exist", fieldName)
+	}
+
+	if fieldDefinition.Type != model.FieldTypeReference {
+		return fmt.Errorf("field: \"%s\" is not a reference", fieldName)
+	}
+
+	if fieldProjection.IsSome() && !util.Contains(fieldProjection.Unwrap(), fieldName) {
+		return fmt.Errorf("field: \"%s\" is expanded but isn't in the field projection", fieldName)
+	}
+
+	return nil
+}
+
+func getExpansionPathString(expansionPath model.ExpansionPath) string {
+	str := ""
+
+	for i, fieldName := range expansionPath {
+		if i > 0 {
+			str += "."
+		}
+
+		str += fieldName.String()
+	}
+
+	return str
+}
@@ -3,6 +3,8 @@ package validation
 import (
 	"crudly/model"
 	"crudly/util"
+	"crudly/util/optional"
+	"crudly/util/result"
 	"fmt"
 	"strconv"
 
@@ -15,22 +17,41 @@ func NewEntityFilterValidator() entityFilterValidator {
 	return entityFilterValidator{}
 }
 
-func (e *entityFilterValidator) ValidateEntityFilter(
-	entityFilter model.EntityFilter,
+func (e *entityFilterValidator) ValidateEntityFilterExpression(
+	filterExpression *model.EntityFilterExpression,
 	tableSchema model.TableSchema,
 ) error {
+	switch filterExpression.Type {
+	case model.EntityFilterExpressionTypeField:
+		fieldName := filterExpression.FieldName
 
-	for k := range entityFilter {
-		fieldDefinition, ok := tableSchema[k]
+		fieldDefinition, ok := tableSchema[fieldName]
 
 		if !ok {
-			return fmt.Errorf("field: \"%s\" does not exist", k)
+			return fmt.Errorf("field: \"%s\" does not exist", fieldName)
 		}
 
-		err := validateFieldFilter(k, entityFilter, fieldDefinition)
+		err := validateFieldFilter(&filterExpression.FieldFilter, fieldDefinition)
 
 		if err != nil {
-			return fmt.Errorf("error validating field: \"%s\": %w", k, err)
+			return fmt.Errorf("error validating field: \"%s\": %w", fieldName, err)
+		}
+
+		return nil
+	case model.EntityFilterExpressionTypeAnd, model.EntityFilterExpressionTypeOr:
+	case model.EntityFilterExpressionTypeNot:
+		if len(filterExpression.Operands) != 1 {
+			return fmt.Errorf("not filter must have exactly one operand")
+		}
+	default:
+		panic(fmt.Sprintf("invalid entity filter expression type has entered the system: %+v", filterExpression.Type))
+	}
+
+	for index := range filterExpression.Operands {
+		err := e.ValidateEntityFilterExpression(&filterExpression.Operands[index], tableSchema)
+
+		if err != nil {
+			return err
 		}
 	}
 
@@ -41,30 +62,106 @@ func isValidFieldFilter(fieldType model.FieldType, fieldFilterType model.FieldFi
 	validityMap := map[model.FieldType][]model.FieldFilterType{
 		model.FieldTypeId: {
 			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
+			model.FieldFilterTypeIn,
+		},
+		model.FieldTypeReference: {
+			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
+			model.FieldFilterTypeIn,
 		},
 		model.FieldTypeInteger: {
 			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
 			model.FieldFilterTypeGreaterThan,
 			model.FieldFilterTypeGreaterThanEq,
 			model.FieldFilterTypeLessThan,
 			model.FieldFilterTypeLessThanEq,
+			model.FieldFilterTypeIn,
+		},
+		model.FieldTypeBigInt: {
+			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
+			model.FieldFilterTypeGreaterThan,
+			model.FieldFilterTypeGreaterThanEq,
+			model.FieldFilterTypeLessThan,
+			model.FieldFilterTypeLessThanEq,
+			model.FieldFilterTypeIn,
+		},
+		model.FieldTypeFloat: {
+			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
+			model.FieldFilterTypeGreaterThan,
+			model.FieldFilterTypeGreaterThanEq,
+			model.FieldFilterTypeLessThan,
+			model.FieldFilterTypeLessThanEq,
+			model.FieldFilterTypeIn,
+		},
+		model.FieldTypeDecimal: {
+			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
+			model.FieldFilterTypeGreaterThan,
+			model.FieldFilterTypeGreaterThanEq,
+			model.FieldFilterTypeLessThan,
+			model.FieldFilterTypeLessThanEq,
+			model.FieldFilterTypeIn,
 		},
 		model.FieldTypeString: {
 			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
+			model.FieldFilterTypeIn,
+			model.FieldFilterTypeContains,
+			model.FieldFilterTypeStartsWith,
+			model.FieldFilterTypeEndsWith,
 		},
 		model.FieldTypeBoolean: {
 			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
 		},
 		model.FieldTypeTime: {
 			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
 			model.FieldFilterTypeGreaterThan,
 			model.FieldFilterTypeGreaterThanEq,
 			model.FieldFilterTypeLessThan,
 			model.FieldFilterTypeLessThanEq,
+			model.FieldFilterTypeIn,
 		},
 		model.FieldTypeEnum: {
 			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
+			model.FieldFilterTypeIn,
+		},
+		model.FieldTypeJson: {
+			model.FieldFilterTypeContains,
 		},
+		model.FieldTypeArray: {
+			model.FieldFilterTypeContains,
+			model.FieldFilterTypeContainsAny,
+		},
+		model.FieldTypeDate: {
+			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
+			model.FieldFilterTypeGreaterThan,
+			model.FieldFilterTypeGreaterThanEq,
+			model.FieldFilterTypeLessThan,
+			model.FieldFilterTypeLessThanEq,
+			model.FieldFilterTypeIn,
+		},
+		model.FieldTypeDuration: {
+			model.FieldFilterTypeEquals,
+			model.FieldFilterTypeNotEquals,
+			model.FieldFilterTypeGreaterThan,
+			model.FieldFilterTypeGreaterThanEq,
+			model.FieldFilterTypeLessThan,
+			model.FieldFilterTypeLessThanEq,
+			model.FieldFilterTypeIn,
+		},
+	}
+
+	// Null checks are valid for any type, but only make sense on optional fields
+	if fieldFilterType == model.FieldFilterTypeIsNull || fieldFilterType == model.FieldFilterTypeIsNotNull {
+		return true
 	}
 
 	validFilters := validityMap[fieldType]
@@ -73,16 +170,21 @@ func isValidFieldFilter(fieldType model.FieldType, fieldFilterType model.FieldFi
 }
 
 func validateFieldFilter(
-	fieldName model.FieldName,
-	entityFilter model.EntityFilter,
+	parsedFieldFilter *model.FieldFilter,
 	fieldDefinition model.FieldDefinition,
 ) error {
-	parsedFieldFilter := entityFilter[fieldName]
-
-	comparator, ok := parsedFieldFilter.Comparator.(string)
+	if len(parsedFieldFilter.Path) > 0 {
+		switch fieldDefinition.Type {
+		case model.FieldTypeJson:
+			return validateJsonPathFilter(parsedFieldFilter)
+		case model.FieldTypeArray:
+			return validateArrayLengthFilter(parsedFieldFilter)
+		}
 
-	if !ok {
-		panic("comparator was not a string.")
+		return fmt.Errorf(
+			"filter path is not valid for field type \"%s\"",
+			fieldDefinition.Type.String(),
+		)
 	}
 
 	if !isValidFieldFilter(fieldDefinition.Type, parsedFieldFilter.Type) {
@@ -93,51 +195,169 @@ func validateFieldFilter(
 		)
 	}
 
+	// Array comparators are parsed as elements
+	comparatorDefinition := fieldDefinition
+
+	if fieldDefinition.Type == model.FieldTypeArray {
+		comparatorDefinition = getArrayElementDefinition(fieldDefinition)
+	}
+
+	switch parsedFieldFilter.Type {
+	case model.FieldFilterTypeIsNull, model.FieldFilterTypeIsNotNull:
+		if !fieldDefinition.IsOptional {
+			return fmt.Errorf(
+				"filter: \"%s\" is only valid for optional fields",
+				parsedFieldFilter.Type.String(),
+			)
+		}
+
+		parsedFieldFilter.Comparator = nil
+
+		return nil
+	case model.FieldFilterTypeIn, model.FieldFilterTypeContainsAny:
+		comparators, ok := parsedFieldFilter.Comparator.([]string)
+
+		if !ok {
+			panic("in comparator was not a string slice.")
+		}
+
+		parsedComparators := []any{}
+
+		for _, comparator := range comparators {
+			comparatorResult := parseFilterComparator(comparator, comparatorDefinition)
+
+			if comparatorResult.IsErr() {
+				return comparatorResult.UnwrapErr()
+			}
+
+			parsedComparators = append(parsedComparators, comparatorResult.Unwrap())
+		}
+
+		parsedFieldFilter.Comparator = parsedComparators
+
+		if fieldDefinition.Type == model.FieldTypeArray {
+			parsedFieldFilter.Comparator = getArrayValue(parsedComparators, comparatorDefinition.Type)
+		}
+
+		return nil
+	}
+
+	comparator, ok := parsedFieldFilter.Comparator.(string)
+
+	if !ok {
+		panic("comparator was not a string.")
+	}
+
+	comparatorResult := parseFilterComparator(comparator, comparatorDefinition)
+
+	if comparatorResult.IsErr() {
+		return comparatorResult.UnwrapErr()
+	}
+
+	parsedFieldFilter.Comparator = comparatorResult.Unwrap()
+
+	// Arrays are compared against arrays, so a single element becomes an
+	// array of one
+	if fieldDefinition.Type == model.FieldTypeArray {
+		parsedFieldFilter.Comparator = getArrayValue([]any{comparatorResult.Unwrap()}, comparatorDefinition.Type)
+	}
+
+	return nil
+}
+
+func parseFilterComparator(comparator string, fieldDefinition model.FieldDefinition) result.R[any] {
 	switch fieldDefinition.Type {
-	case model.FieldTypeId:
+	case model.FieldTypeId, model.FieldTypeReference:
 		uuidVal, err := uuid.Parse(comparator)
 
 		if err != nil {
-			return fmt.Errorf("filter comparator is not an id: %s", comparator)
+			return result.Errf[any]("filter comparator is not an id: %s", comparator)
 		}
 
-		parsedFieldFilter.Comparator = uuidVal
+		return result.Ok[any](uuidVal)
 	case model.FieldTypeInteger:
-		intNum, err := strconv.Atoi(comparator)
+		intNum, ok := parseIntegerComparator(comparator)
+
+		if !ok {
+			return result.Errf[any]("filter comparator is not a valid integer: %s", comparator)
+		}
+
+		if !isIntegerInRange(intNum) {
+			return result.Errf[any]("filter comparator is not a valid integer: %s, %w", comparator, errIntegerRange)
+		}
+
+		return result.Ok[any](intNum)
+	case model.FieldTypeBigInt:
+		bigInt, err := strconv.ParseInt(comparator, 10, 64)
 
 		if err != nil {
-			return fmt.Errorf("filter comparator is not an integer: %s", comparator)
+			return result.Errf[any]("filter comparator is not a bigint: %s", comparator)
+		}
+
+		return result.Ok[any](bigInt)
+	case model.FieldTypeFloat:
+		float, err := strconv.ParseFloat(comparator, 64)
+
+		if err != nil {
+			return result.Errf[any]("filter comparator is not a float: %s", comparator)
+		}
+
+		return result.Ok[any](float)
+	case model.FieldTypeDecimal:
+		// Comparators aren't held to the field's precision and scale, it's
+		// valid to compare against a value that couldn't be stored
+		decimalResult := parseDecimal(comparator, optional.None[uint](), optional.None[uint]())
+
+		if decimalResult.IsErr() {
+			return result.Errf[any]("filter comparator is not a decimal: %s", comparator)
 		}
 
-		parsedFieldFilter.Comparator = intNum
+		return result.Ok[any](decimalResult.Unwrap())
 	case model.FieldTypeString:
+		return result.Ok[any](comparator)
 	case model.FieldTypeBoolean:
 		if comparator == "true" {
-			parsedFieldFilter.Comparator = true
+			return result.Ok[any](true)
 		} else if comparator == "false" {
-			parsedFieldFilter.Comparator = false
-		} else {
-			return fmt.Errorf("filter comparator is not a boolean: %s", comparator)
+			return result.Ok[any](false)
 		}
+
+		return result.Errf[any]("filter comparator is not a boolean: %s", comparator)
 	case model.FieldTypeTime:
 		timeResult := util.ValidateIncomingTime(comparator)
 
 		if timeResult.IsErr() {
-			return fmt.Errorf("filter comparator is not a timestamp: %s", comparator)
+			return result.Errf[any]("filter comparator is not a timestamp: %s", comparator)
 		}
 
-		parsedFieldFilter.Comparator = timeResult.Unwrap()
+		return result.Ok[any](timeResult.Unwrap())
 	case model.FieldTypeEnum:
 		vals := fieldDefinition.Values
 
 		if !util.Contains(vals.Unwrap(), comparator) {
-			return fmt.Errorf("filter comparator is not a valid enum value: %s", comparator)
+			return result.Errf[any]("filter comparator is not a valid enum value: %s", comparator)
 		}
-	default:
-		panic(fmt.Sprintf("invalid field type has entered the system: %+v", fieldDefinition.Type))
-	}
 
-	entityFilter[fieldName] = parsedFieldFilter
+		return result.Ok[any](comparator)
+	case model.FieldTypeJson:
+		return parseJsonComparator(comparator)
+	case model.FieldTypeDate:
+		date, ok := parseIncomingDate(comparator)
 
-	return nil
+		if !ok {
+			return result.Errf[any]("filter comparator is not a date: %s", comparator)
+		}
+
+		return result.Ok[any](date)
+	case model.FieldTypeDuration:
+		duration, ok := parseIncomingDuration(comparator)
+
+		if !ok {
+			return result.Errf[any]("filter comparator is not a duration: %s", comparator)
+		}
+
+		return result.Ok[any](duration)
+	}
+
+	panic(fmt.Sprintf("invalid field type has entered the system: %+v", fieldDefinition.Type))
 }
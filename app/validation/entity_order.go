@@ -41,11 +41,24 @@ func (e *entityOrderValidator) ValidateEntityOrders(
 
 func isValidFieldOrder(fieldType model.FieldType, fieldOrderType model.FieldOrderType) bool {
 	validityMap := map[model.FieldType][]model.FieldOrderType{
-		model.FieldTypeId: {},
+		model.FieldTypeId:        {},
+		model.FieldTypeReference: {},
 		model.FieldTypeInteger: {
 			model.FieldOrderTypeAscending,
 			model.FieldOrderTypeDescending,
 		},
+		model.FieldTypeBigInt: {
+			model.FieldOrderTypeAscending,
+			model.FieldOrderTypeDescending,
+		},
+		model.FieldTypeFloat: {
+			model.FieldOrderTypeAscending,
+			model.FieldOrderTypeDescending,
+		},
+		model.FieldTypeDecimal: {
+			model.FieldOrderTypeAscending,
+			model.FieldOrderTypeDescending,
+		},
 		model.FieldTypeString: {
 			model.FieldOrderTypeAscending,
 			model.FieldOrderTypeDescending,
@@ -55,7 +68,17 @@ func isValidFieldOrder(fieldType model.FieldType, fieldOrderType model.FieldOrde
 			model.FieldOrderTypeAscending,
 			model.FieldOrderTypeDescending,
 		},
-		model.FieldTypeEnum: {},
+		model.FieldTypeEnum:  {},
+		model.FieldTypeJson:  {},
+		model.FieldTypeArray: {},
+		model.FieldTypeDate: {
+			model.FieldOrderTypeAscending,
+			model.FieldOrderTypeDescending,
+		},
+		model.FieldTypeDuration: {
+			model.FieldOrderTypeAscending,
+			model.FieldOrderTypeDescending,
+		},
 	}
 
 	validOrders := validityMap[fieldType]
@@ -0,0 +1,222 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fieldAlterationValidator struct{}
+
+func NewFieldAlterationValidator() fieldAlterationValidator {
+	return fieldAlterationValidator{}
+}
+
+// Checks that a field can be altered to the new definition, regardless of
+// the values it holds
+func (f *fieldAlterationValidator) ValidateFieldAlteration(
+	fieldName model.FieldName,
+	fieldDefinition model.FieldDefinition,
+	newFieldDefinition model.FieldDefinition,
+	tableIndexes model.TableIndexes,
+) error {
+	if !isValidFieldConversion(fieldDefinition.Type, newFieldDefinition.Type) {
+		return fmt.Errorf(
+			"field type \"%s\" can't be converted to \"%s\"",
+			fieldDefinition.Type.String(),
+			newFieldDefinition.Type.String(),
+		)
+	}
+
+	if fieldDefinition.ElementType != newFieldDefinition.ElementType {
+		return fmt.Errorf("array element types can't be changed")
+	}
+
+	if fieldDefinition.References != newFieldDefinition.References ||
+		fieldDefinition.OnDelete != newFieldDefinition.OnDelete {
+		return fmt.Errorf("the table a reference refers to and what happens on delete can't be changed")
+	}
+
+	if fieldDefinition.Unique != newFieldDefinition.Unique {
+		return fmt.Errorf("unique can't be changed, add or delete a unique index instead")
+	}
+
+	if fieldDefinition.Type == newFieldDefinition.Type {
+		return nil
+	}
+
+	// Index filters compare against values of the field's current type
+	for indexName, index := range tableIndexes {
+		if index.Filter.IsSome() && index.Filter.Unwrap().ReferencesField(fieldName) {
+			return fmt.Errorf(
+				"field: \"%s\" is filtered on by index: \"%s\", which has to be deleted before its type can be changed",
+				fieldName,
+				indexName,
+			)
+		}
+	}
+
+	return nil
+}
+
+func isValidFieldConversion(fieldType model.FieldType, newFieldType model.FieldType) bool {
+	numberTypes := []model.FieldType{
+		model.FieldTypeInteger,
+		model.FieldTypeBigInt,
+		model.FieldTypeFloat,
+		model.FieldTypeDecimal,
+		model.FieldTypeString,
+		model.FieldTypeEnum,
+	}
+
+	// The types each type can be converted from. Times and dates aren't
+	// converted to strings as their text in postgres isn't the form they're
+	// written in
+	validityMap := map[model.FieldType][]model.FieldType{
+		model.FieldTypeString: {
+			model.FieldTypeString,
+			model.FieldTypeEnum,
+			model.FieldTypeInteger,
+			model.FieldTypeBigInt,
+			model.FieldTypeFloat,
+			model.FieldTypeDecimal,
+			model.FieldTypeBoolean,
+			model.FieldTypeId,
+			model.FieldTypeDuration,
+		},
+		model.FieldTypeEnum: {
+			model.FieldTypeEnum,
+			model.FieldTypeString,
+		},
+		model.FieldTypeInteger:   numberTypes,
+		model.FieldTypeBigInt:    numberTypes,
+		model.FieldTypeFloat:     numberTypes,
+		model.FieldTypeDecimal:   numberTypes,
+		model.FieldTypeBoolean:   {model.FieldTypeBoolean, model.FieldTypeString},
+		model.FieldTypeId:        {model.FieldTypeId, model.FieldTypeString},
+		model.FieldTypeTime:      {model.FieldTypeTime, model.FieldTypeString},
+		model.FieldTypeDate:      {model.FieldTypeDate, model.FieldTypeString},
+		model.FieldTypeDuration:  {model.FieldTypeDuration, model.FieldTypeString},
+		model.FieldTypeJson:      {model.FieldTypeJson},
+		model.FieldTypeArray:     {model.FieldTypeArray},
+		model.FieldTypeReference: {model.FieldTypeReference},
+	}
+
+	return util.Contains(validityMap[newFieldType], fieldType)
+}
+
+// Converts a stored value into one of the new definition, returning an error
+// if it can't be held by it. Values keeping their type are checked as they
+// are, otherwise their text is read as a value of the new type
+func (f *fieldAlterationValidator) ConvertStoredField(
+	storedFieldValue model.StoredFieldValue,
+	fieldName model.FieldName,
+	fieldDefinition model.FieldDefinition,
+	newFieldDefinition model.FieldDefinition,
+) result.R[any] {
+	var incomingValue any
+
+	if fieldDefinition.Type == newFieldDefinition.Type {
+		incomingValueResult := getIncomingFieldValue(storedFieldValue.Value)
+
+		if incomingValueResult.IsErr() {
+			return incomingValueResult
+		}
+
+		incomingValue = incomingValueResult.Unwrap()
+	} else {
+		incomingValueResult := getIncomingFieldValueFromText(storedFieldValue.Text.Unwrap(), newFieldDefinition)
+
+		if incomingValueResult.IsErr() {
+			return incomingValueResult
+		}
+
+		incomingValue = incomingValueResult.Unwrap()
+	}
+
+	entity := model.Entity{fieldName: incomingValue}
+
+	err := validateField(entity, fieldName, newFieldDefinition)
+
+	if err != nil {
+		return result.Err[any](err)
+	}
+
+	return result.Ok[any](entity[fieldName])
+}
+
+// Gets the form a stored value would be written in
+func getIncomingFieldValue(value any) result.R[any] {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return result.Ok[any](v.String())
+	case time.Time:
+		return result.Ok[any](v.Format(time.RFC3339Nano))
+	case model.Date:
+		return result.Ok[any](v.String())
+	case model.Duration:
+		return result.Ok[any](v.String())
+	case model.Json:
+		decoder := json.NewDecoder(strings.NewReader(v.String()))
+		decoder.UseNumber()
+
+		var jsonValue any
+
+		err := decoder.Decode(&jsonValue)
+
+		if err != nil {
+			return result.Errf[any]("stored json is not valid: %w", err)
+		}
+
+		return result.Ok(jsonValue)
+	case []int:
+		elements := []any{}
+
+		for _, element := range v {
+			elements = append(elements, element)
+		}
+
+		return result.Ok[any](elements)
+	case []string:
+		elements := []any{}
+
+		for _, element := range v {
+			elements = append(elements, element)
+		}
+
+		return result.Ok[any](elements)
+	}
+
+	return result.Ok(value)
+}
+
+// Reads text as a value of the new type, in the form it would be written in.
+// Numbers have to be plain decimals, as that's what postgres will read them as
+func getIncomingFieldValueFromText(text string, newFieldDefinition model.FieldDefinition) result.R[any] {
+	switch newFieldDefinition.Type {
+	case model.FieldTypeInteger, model.FieldTypeBigInt, model.FieldTypeFloat:
+		if parseDecimal(text, optional.None[uint](), optional.None[uint]()).IsErr() {
+			return result.Errf[any]("value: \"%s\" is not a number", text)
+		}
+
+		return result.Ok[any](json.Number(text))
+	case model.FieldTypeBoolean:
+		switch text {
+		case "true":
+			return result.Ok[any](true)
+		case "false":
+			return result.Ok[any](false)
+		}
+
+		return result.Errf[any]("value: \"%s\" is not a boolean", text)
+	}
+
+	return result.Ok[any](text)
+}
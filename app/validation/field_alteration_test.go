@@ -0,0 +1,230 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestIsValidFieldConversion(t *testing.T) {
+	tests := []struct {
+		fieldType     model.FieldType
+		newFieldType  model.FieldType
+		expectedValid bool
+	}{
+		{fieldType: model.FieldTypeString, newFieldType: model.FieldTypeString, expectedValid: true},
+		{fieldType: model.FieldTypeInteger, newFieldType: model.FieldTypeBigInt, expectedValid: true},
+		{fieldType: model.FieldTypeBigInt, newFieldType: model.FieldTypeInteger, expectedValid: true},
+		{fieldType: model.FieldTypeFloat, newFieldType: model.FieldTypeDecimal, expectedValid: true},
+		{fieldType: model.FieldTypeString, newFieldType: model.FieldTypeInteger, expectedValid: true},
+		{fieldType: model.FieldTypeInteger, newFieldType: model.FieldTypeString, expectedValid: true},
+		{fieldType: model.FieldTypeString, newFieldType: model.FieldTypeEnum, expectedValid: true},
+		{fieldType: model.FieldTypeEnum, newFieldType: model.FieldTypeInteger, expectedValid: true},
+		{fieldType: model.FieldTypeString, newFieldType: model.FieldTypeBoolean, expectedValid: true},
+		{fieldType: model.FieldTypeId, newFieldType: model.FieldTypeString, expectedValid: true},
+		{fieldType: model.FieldTypeString, newFieldType: model.FieldTypeId, expectedValid: true},
+		{fieldType: model.FieldTypeString, newFieldType: model.FieldTypeTime, expectedValid: true},
+		{fieldType: model.FieldTypeTime, newFieldType: model.FieldTypeString, expectedValid: false},
+		{fieldType: model.FieldTypeDate, newFieldType: model.FieldTypeString, expectedValid: false},
+		{fieldType: model.FieldTypeBoolean, newFieldType: model.FieldTypeInteger, expectedValid: false},
+		{fieldType: model.FieldTypeInteger, newFieldType: model.FieldTypeBoolean, expectedValid: false},
+		{fieldType: model.FieldTypeEnum, newFieldType: model.FieldTypeBoolean, expectedValid: false},
+		{fieldType: model.FieldTypeString, newFieldType: model.FieldTypeJson, expectedValid: false},
+		{fieldType: model.FieldTypeJson, newFieldType: model.FieldTypeString, expectedValid: false},
+		{fieldType: model.FieldTypeArray, newFieldType: model.FieldTypeArray, expectedValid: true},
+		{fieldType: model.FieldTypeString, newFieldType: model.FieldTypeArray, expectedValid: false},
+		{fieldType: model.FieldTypeId, newFieldType: model.FieldTypeReference, expectedValid: false},
+		{fieldType: model.FieldTypeReference, newFieldType: model.FieldTypeString, expectedValid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fieldType.String()+" to "+test.newFieldType.String(), func(t *testing.T) {
+			valid := isValidFieldConversion(test.fieldType, test.newFieldType)
+
+			if valid != test.expectedValid {
+				t.Errorf("got: %t, want: %t", valid, test.expectedValid)
+			}
+		})
+	}
+}
+
+func TestConvertStoredField(t *testing.T) {
+	id := uuid.MustParse("0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d")
+
+	stored := func(value any, text string) model.StoredFieldValue {
+		return model.StoredFieldValue{Value: value, Text: optional.Some(text)}
+	}
+
+	tests := []struct {
+		name               string
+		storedFieldValue   model.StoredFieldValue
+		fieldDefinition    model.FieldDefinition
+		newFieldDefinition model.FieldDefinition
+		expectedValue      any
+		expectErr          bool
+	}{
+		{
+			name:               "string to integer",
+			storedFieldValue:   stored("42", "42"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeInteger},
+			expectedValue:      42,
+		},
+		{
+			name:               "string with an exponent to integer",
+			storedFieldValue:   stored("1e3", "1e3"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeInteger},
+			expectedValue:      1000,
+		},
+		{
+			name:               "string that isn't a number to integer",
+			storedFieldValue:   stored("forty two", "forty two"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeInteger},
+			expectErr:          true,
+		},
+		{
+			name:               "hexadecimal string to integer",
+			storedFieldValue:   stored("0x2a", "0x2a"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeInteger},
+			expectErr:          true,
+		},
+		{
+			name:               "bigint out of the integer range",
+			storedFieldValue:   stored(int64(3000000000), "3000000000"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeBigInt},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeInteger},
+			expectErr:          true,
+		},
+		{
+			name:               "float printed with an exponent to bigint",
+			storedFieldValue:   stored(1e15, "1e+15"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeFloat},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeBigInt},
+			expectedValue:      int64(1000000000000000),
+		},
+		{
+			name:               "float above the bigint range to bigint",
+			storedFieldValue:   stored(1e19, "1e+19"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeFloat},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeBigInt},
+			expectErr:          true,
+		},
+		{
+			name:               "fractional float to integer",
+			storedFieldValue:   stored(1.5, "1.5"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeFloat},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeInteger},
+			expectErr:          true,
+		},
+		{
+			name:               "integer to string",
+			storedFieldValue:   stored(42, "42"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeInteger},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeString},
+			expectedValue:      "42",
+		},
+		{
+			name:               "string to boolean",
+			storedFieldValue:   stored("true", "true"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeBoolean},
+			expectedValue:      true,
+		},
+		{
+			name:               "string that isn't a boolean to boolean",
+			storedFieldValue:   stored("yes", "yes"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeBoolean},
+			expectErr:          true,
+		},
+		{
+			name:             "string to enum",
+			storedFieldValue: stored("red", "red"),
+			fieldDefinition:  model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{
+				Type:   model.FieldTypeEnum,
+				Values: optional.Some([]string{"red", "green"}),
+			},
+			expectedValue: "red",
+		},
+		{
+			name:             "string that isn't a value to enum",
+			storedFieldValue: stored("blue", "blue"),
+			fieldDefinition:  model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{
+				Type:   model.FieldTypeEnum,
+				Values: optional.Some([]string{"red", "green"}),
+			},
+			expectErr: true,
+		},
+		{
+			name:               "id to string",
+			storedFieldValue:   stored(id, id.String()),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeId},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeString},
+			expectedValue:      id.String(),
+		},
+		{
+			name:               "string to id",
+			storedFieldValue:   stored(id.String(), id.String()),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeId},
+			expectedValue:      id,
+		},
+		{
+			name:               "same type within new constraints",
+			storedFieldValue:   stored("abc", "abc"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, MaxLength: optional.Some[uint](3)},
+			expectedValue:      "abc",
+		},
+		{
+			name:               "same type outside new constraints",
+			storedFieldValue:   stored("abcd", "abcd"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeString},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, MaxLength: optional.Some[uint](3)},
+			expectErr:          true,
+		},
+		{
+			name:               "same type array",
+			storedFieldValue:   stored([]int{1, 2}, "{1,2}"),
+			fieldDefinition:    model.FieldDefinition{Type: model.FieldTypeArray, ElementType: optional.Some(model.FieldTypeInteger)},
+			newFieldDefinition: model.FieldDefinition{Type: model.FieldTypeArray, ElementType: optional.Some(model.FieldTypeInteger)},
+			expectedValue:      []int{1, 2},
+		},
+	}
+
+	validator := NewFieldAlterationValidator()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			convertedFieldResult := validator.ConvertStoredField(
+				test.storedFieldValue,
+				"field",
+				test.fieldDefinition,
+				test.newFieldDefinition,
+			)
+
+			if test.expectErr {
+				if convertedFieldResult.IsOk() {
+					t.Fatalf("expected an error, got: %+v", convertedFieldResult.Unwrap())
+				}
+				return
+			}
+
+			if convertedFieldResult.IsErr() {
+				t.Fatalf("unexpected error: %s", convertedFieldResult.UnwrapErr())
+			}
+
+			if !reflect.DeepEqual(convertedFieldResult.Unwrap(), test.expectedValue) {
+				t.Errorf("got: %#v, want: %#v", convertedFieldResult.Unwrap(), test.expectedValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package validation
+
+import (
+	"crudly/model"
+	"fmt"
+)
+
+type fieldProjectionValidator struct{}
+
+func NewFieldProjectionValidator() fieldProjectionValidator {
+	return fieldProjectionValidator{}
+}
+
+func (f *fieldProjectionValidator) ValidateFieldProjection(
+	fieldProjection model.FieldProjection,
+	tableSchema model.TableSchema,
+) error {
+	for _, fieldName := range fieldProjection {
+		if fieldName == "id" {
+			continue
+		}
+
+		if _, ok := tableSchema[fieldName]; !ok {
+			return fmt.Errorf("field: \"%s\" does not exist", fieldName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"encoding/json"
+	"fmt"
+)
+
+// Json fields hold an object or an array. Scalars are rejected since they'd
+// be better off as a field of their own type
+func parseIncomingJson(field any) (model.Json, bool) {
+	switch field.(type) {
+	case map[string]any, []any:
+	default:
+		return "", false
+	}
+
+	bytes, err := json.Marshal(field)
+
+	if err != nil {
+		return "", false
+	}
+
+	return model.Json(bytes), true
+}
+
+// Unlike stored values a containment comparator can be any JSON value, as an
+// array contains each of its scalar elements
+func parseJsonComparator(comparator string) result.R[any] {
+	if !json.Valid([]byte(comparator)) {
+		return result.Errf[any]("filter comparator is not valid json: %s", comparator)
+	}
+
+	return result.Ok[any](model.Json(comparator))
+}
+
+// Nested values have no declared type, so they're compared as text, except
+// for the ordering filters which compare them as numbers and the containment
+// filter which compares them as JSON
+var jsonPathFilterTypes = []model.FieldFilterType{
+	model.FieldFilterTypeEquals,
+	model.FieldFilterTypeNotEquals,
+	model.FieldFilterTypeGreaterThan,
+	model.FieldFilterTypeGreaterThanEq,
+	model.FieldFilterTypeLessThan,
+	model.FieldFilterTypeLessThanEq,
+	model.FieldFilterTypeIn,
+	model.FieldFilterTypeContains,
+	model.FieldFilterTypeIsNull,
+	model.FieldFilterTypeIsNotNull,
+}
+
+func validateJsonPathFilter(parsedFieldFilter *model.FieldFilter) error {
+	if !util.Contains(jsonPathFilterTypes, parsedFieldFilter.Type) {
+		return fmt.Errorf(
+			"filter: \"%s\" is not valid for a json path",
+			parsedFieldFilter.Type.String(),
+		)
+	}
+
+	switch parsedFieldFilter.Type {
+	case model.FieldFilterTypeIsNull, model.FieldFilterTypeIsNotNull:
+		// A missing path is null, so these are valid on required fields too
+		parsedFieldFilter.Comparator = nil
+
+		return nil
+	case model.FieldFilterTypeIn:
+		comparators, ok := parsedFieldFilter.Comparator.([]string)
+
+		if !ok {
+			panic("in comparator was not a string slice.")
+		}
+
+		parsedComparators := []any{}
+
+		for _, comparator := range comparators {
+			parsedComparators = append(parsedComparators, comparator)
+		}
+
+		parsedFieldFilter.Comparator = parsedComparators
+
+		return nil
+	}
+
+	comparator, ok := parsedFieldFilter.Comparator.(string)
+
+	if !ok {
+		panic("comparator was not a string.")
+	}
+
+	switch parsedFieldFilter.Type {
+	case model.FieldFilterTypeContains:
+		comparatorResult := parseJsonComparator(comparator)
+
+		if comparatorResult.IsErr() {
+			return comparatorResult.UnwrapErr()
+		}
+
+		parsedFieldFilter.Comparator = comparatorResult.Unwrap()
+	case model.FieldFilterTypeGreaterThan,
+		model.FieldFilterTypeGreaterThanEq,
+		model.FieldFilterTypeLessThan,
+		model.FieldFilterTypeLessThanEq:
+		decimalResult := parseDecimal(comparator, optional.None[uint](), optional.None[uint]())
+
+		if decimalResult.IsErr() {
+			return fmt.Errorf("filter comparator is not a number: %s", comparator)
+		}
+
+		parsedFieldFilter.Comparator = decimalResult.Unwrap()
+	}
+
+	return nil
+}
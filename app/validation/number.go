@@ -0,0 +1,258 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Incoming JSON numbers arrive as json.Number so their exact text is kept
+// until the field type is known. Only integer and float fields ever convert
+// them to binary
+
+func parseIncomingInteger(field any) (int, bool) {
+	switch v := field.(type) {
+	case int:
+		return v, true
+	case float64:
+		truncated := math.Trunc(v)
+
+		if truncated != v {
+			return 0, false
+		}
+
+		return int(truncated), true
+	case json.Number:
+		integer, ok := parseIntegerText(v.String())
+
+		return int(integer), ok
+	}
+
+	return 0, false
+}
+
+// Also reads integers written with a fractional part or exponent (e.g. 1.0,
+// 1e3), as floats are when they're large
+func parseIntegerText(text string) (int64, bool) {
+	integer, err := strconv.ParseInt(text, 10, 64)
+
+	if err == nil {
+		return integer, true
+	}
+
+	decimalResult := parseDecimal(text, optional.None[uint](), optional.None[uint]())
+
+	if decimalResult.IsErr() {
+		return 0, false
+	}
+
+	rat, _ := new(big.Rat).SetString(decimalResult.Unwrap().String())
+
+	if !rat.IsInt() || !rat.Num().IsInt64() {
+		return 0, false
+	}
+
+	return rat.Num().Int64(), true
+}
+
+// Integer fields are stored as 32 bit postgres integers, so values outside of
+// that range are rejected rather than failing once they reach postgres. The
+// range is only given in errors for values that are integers, but outside it
+func isIntegerInRange(integer int) bool {
+	return integer >= math.MinInt32 && integer <= math.MaxInt32
+}
+
+var errIntegerRange = fmt.Errorf("integers must be between %d and %d", math.MinInt32, math.MaxInt32)
+
+// Filter comparators on integer fields are bound as integers too, so are held
+// to the same range by their callers
+func parseIntegerComparator(comparator string) (int, bool) {
+	integer, err := strconv.Atoi(comparator)
+
+	return integer, err == nil
+}
+
+// Big integers are also accepted as strings, since that's the only way for
+// JavaScript clients to send values above 2^53 exactly
+func parseIncomingBigInt(field any) (int64, bool) {
+	switch v := field.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case float64:
+		truncated := math.Trunc(v)
+
+		if truncated != v || math.Abs(truncated) > maxExactFloatInteger {
+			return 0, false
+		}
+
+		return int64(truncated), true
+	case json.Number:
+		return parseIntegerText(v.String())
+	case string:
+		bigInt, err := strconv.ParseInt(v, 10, 64)
+
+		return bigInt, err == nil
+	}
+
+	return 0, false
+}
+
+// The largest integer below which every integer can be held exactly by a
+// float64
+const maxExactFloatInteger = 1 << 53
+
+func parseIncomingFloat(field any) (float64, bool) {
+	switch v := field.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case json.Number:
+		float, err := v.Float64()
+
+		if err != nil {
+			return 0, false
+		}
+
+		return float, true
+	}
+
+	return 0, false
+}
+
+// Decimals are accepted as JSON numbers or as strings, so that clients which
+// can't hold them exactly as numbers can still send them
+func parseIncomingDecimal(field any, fieldDefinition model.FieldDefinition) result.R[model.Decimal] {
+	// As in postgres, a precision without a scale means a scale of 0
+	scale := fieldDefinition.Scale
+
+	if fieldDefinition.Precision.IsSome() && scale.IsNone() {
+		scale = optional.Some(uint(0))
+	}
+
+	switch v := field.(type) {
+	case json.Number:
+		return parseDecimal(v.String(), fieldDefinition.Precision, scale)
+	case string:
+		return parseDecimal(v, fieldDefinition.Precision, scale)
+	case model.Decimal:
+		return parseDecimal(v.String(), fieldDefinition.Precision, scale)
+	case int:
+		return parseDecimal(strconv.Itoa(v), fieldDefinition.Precision, scale)
+	}
+
+	return result.Errf[model.Decimal]("value is not a decimal")
+}
+
+var decimalPattern = regexp.MustCompile(`^-?(\d+\.?\d*|\.\d+)([eE]([-+]?\d+))?$`)
+
+const maxDecimalScale = 1000
+
+// Parses text as an exact decimal, rejecting rather than rounding anything
+// that doesn't fit the precision and scale. The result has exactly scale
+// digits after the decimal point when a scale is given, otherwise as few as
+// are needed
+func parseDecimal(
+	text string,
+	precision optional.O[uint],
+	scale optional.O[uint],
+) result.R[model.Decimal] {
+	match := decimalPattern.FindStringSubmatch(text)
+
+	if match == nil {
+		return result.Errf[model.Decimal]("value: \"%s\" is not a decimal", text)
+	}
+
+	// Stops huge exponents from blowing up the size of the number
+	if match[3] != "" {
+		exponent, err := strconv.Atoi(match[3])
+
+		if err != nil || exponent > maxDecimalScale || exponent < -maxDecimalScale {
+			return result.Errf[model.Decimal]("value: \"%s\" is out of range", text)
+		}
+	}
+
+	rat, ok := new(big.Rat).SetString(text)
+
+	if !ok {
+		return result.Errf[model.Decimal]("value: \"%s\" is not a decimal", text)
+	}
+
+	requiredScale := uint(0)
+	shifted := new(big.Rat).Set(rat)
+
+	for !shifted.IsInt() {
+		if requiredScale == maxDecimalScale {
+			return result.Errf[model.Decimal]("value: \"%s\" has too many decimal places", text)
+		}
+
+		shifted.Mul(shifted, big.NewRat(10, 1))
+		requiredScale++
+	}
+
+	outputScale := requiredScale
+
+	if scale.IsSome() {
+		if requiredScale > scale.Unwrap() {
+			return result.Errf[model.Decimal]("value: \"%s\" has more than %d decimal places", text, scale.Unwrap())
+		}
+
+		outputScale = scale.Unwrap()
+	}
+
+	decimal := rat.FloatString(int(outputScale))
+
+	if precision.IsSome() {
+		integerPart, _, _ := strings.Cut(strings.TrimPrefix(decimal, "-"), ".")
+		integerDigits := len(strings.TrimLeft(integerPart, "0"))
+
+		if uint(integerDigits) > precision.Unwrap()-outputScale {
+			return result.Errf[model.Decimal](
+				"value: \"%s\" has more than %d digits before the decimal point",
+				text,
+				precision.Unwrap()-outputScale,
+			)
+		}
+	}
+
+	return result.Ok(model.Decimal(decimal))
+}
+
+func validateDecimalDefinition(fieldName model.FieldName, fieldDefinition model.FieldDefinition) error {
+	if fieldDefinition.Type != model.FieldTypeDecimal {
+		if fieldDefinition.Precision.IsSome() || fieldDefinition.Scale.IsSome() {
+			return fmt.Errorf("non decimal type definition \"%s\" has a precision or scale", fieldName)
+		}
+
+		return nil
+	}
+
+	if fieldDefinition.Scale.IsSome() && fieldDefinition.Precision.IsNone() {
+		return fmt.Errorf("decimal type definition \"%s\" has a scale without a precision", fieldName)
+	}
+
+	if fieldDefinition.Precision.IsNone() {
+		return nil
+	}
+
+	precision := fieldDefinition.Precision.Unwrap()
+
+	if precision < 1 || precision > maxDecimalScale {
+		return fmt.Errorf("decimal type definition \"%s\" must have a precision between 1 and %d", fieldName, maxDecimalScale)
+	}
+
+	if fieldDefinition.Scale.IsSome() && fieldDefinition.Scale.Unwrap() > precision {
+		return fmt.Errorf("decimal type definition \"%s\" has a scale greater than its precision", fieldName)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseIncomingInteger(t *testing.T) {
+	tests := []struct {
+		name            string
+		field           any
+		expectedInteger int
+		expectedOk      bool
+		expectedInRange bool
+	}{
+		{name: "int", field: 42, expectedInteger: 42, expectedOk: true, expectedInRange: true},
+		{name: "whole float", field: 42.0, expectedInteger: 42, expectedOk: true, expectedInRange: true},
+		{name: "fractional float", field: 42.5, expectedOk: false},
+		{name: "number", field: json.Number("-7"), expectedInteger: -7, expectedOk: true, expectedInRange: true},
+		{name: "number with exponent", field: json.Number("1e3"), expectedInteger: 1000, expectedOk: true, expectedInRange: true},
+		{name: "number with fractional part", field: json.Number("1.5"), expectedOk: false},
+		{name: "maximum", field: json.Number("2147483647"), expectedInteger: 2147483647, expectedOk: true, expectedInRange: true},
+		{name: "minimum", field: json.Number("-2147483648"), expectedInteger: -2147483648, expectedOk: true, expectedInRange: true},
+		{name: "above the maximum", field: json.Number("2147483648"), expectedInteger: 2147483648, expectedOk: true},
+		{name: "below the minimum", field: json.Number("-2147483649"), expectedInteger: -2147483649, expectedOk: true},
+		{name: "above the maximum with an exponent", field: json.Number("3e9"), expectedInteger: 3000000000, expectedOk: true},
+		{name: "above the maximum as a float", field: 3000000000.0, expectedInteger: 3000000000, expectedOk: true},
+		{name: "above the maximum as an int", field: 3000000000, expectedInteger: 3000000000, expectedOk: true},
+		{name: "string", field: "1", expectedOk: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			integer, ok := parseIncomingInteger(test.field)
+
+			if ok != test.expectedOk || integer != test.expectedInteger {
+				t.Errorf("got: (%d, %t), want: (%d, %t)", integer, ok, test.expectedInteger, test.expectedOk)
+			}
+
+			if ok && isIntegerInRange(integer) != test.expectedInRange {
+				t.Errorf("got in range: %t, want: %t", isIntegerInRange(integer), test.expectedInRange)
+			}
+		})
+	}
+}
+
+func TestParseIntegerComparator(t *testing.T) {
+	tests := []struct {
+		comparator      string
+		expectedInteger int
+		expectedOk      bool
+		expectedInRange bool
+	}{
+		{comparator: "42", expectedInteger: 42, expectedOk: true, expectedInRange: true},
+		{comparator: "-2147483648", expectedInteger: -2147483648, expectedOk: true, expectedInRange: true},
+		{comparator: "2147483647", expectedInteger: 2147483647, expectedOk: true, expectedInRange: true},
+		{comparator: "3000000000", expectedInteger: 3000000000, expectedOk: true},
+		{comparator: "1.5", expectedOk: false},
+		{comparator: "x", expectedOk: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.comparator, func(t *testing.T) {
+			integer, ok := parseIntegerComparator(test.comparator)
+
+			if ok != test.expectedOk || integer != test.expectedInteger {
+				t.Errorf("got: (%d, %t), want: (%d, %t)", integer, ok, test.expectedInteger, test.expectedOk)
+			}
+
+			if ok && isIntegerInRange(integer) != test.expectedInRange {
+				t.Errorf("got in range: %t, want: %t", isIntegerInRange(integer), test.expectedInRange)
+			}
+		})
+	}
+}
+
+// Only values that are integers are told the range they're outside of
+func TestValidateIntegerFieldError(t *testing.T) {
+	tests := []struct {
+		name               string
+		field              any
+		expectedRangeError bool
+	}{
+		{name: "not a number", field: "abc", expectedRangeError: false},
+		{name: "fractional", field: json.Number("1.5"), expectedRangeError: false},
+		{name: "above the maximum", field: json.Number("2147483648"), expectedRangeError: true},
+		{name: "below the minimum", field: json.Number("-2147483649"), expectedRangeError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entity := model.Entity{"count": test.field}
+
+			err := validateField(entity, "count", model.FieldDefinition{Type: model.FieldTypeInteger})
+
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+
+			if errors.Is(err, errIntegerRange) != test.expectedRangeError {
+				t.Errorf("got: %s, want range error: %t", err, test.expectedRangeError)
+			}
+		})
+	}
+}
+
+func TestParseIncomingBigInt(t *testing.T) {
+	tests := []struct {
+		name           string
+		field          any
+		expectedBigInt int64
+		expectedOk     bool
+	}{
+		{name: "int", field: 42, expectedBigInt: 42, expectedOk: true},
+		{name: "number", field: json.Number("9223372036854775807"), expectedBigInt: 9223372036854775807, expectedOk: true},
+		{name: "number with exponent", field: json.Number("1e+15"), expectedBigInt: 1000000000000000, expectedOk: true},
+		{name: "number with fractional part", field: json.Number("1.5"), expectedOk: false},
+		{name: "number above the maximum", field: json.Number("9223372036854775808"), expectedOk: false},
+		{name: "string", field: "9007199254740993", expectedBigInt: 9007199254740993, expectedOk: true},
+		{name: "float above the exact range", field: 1e16, expectedOk: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bigInt, ok := parseIncomingBigInt(test.field)
+
+			if ok != test.expectedOk || bigInt != test.expectedBigInt {
+				t.Errorf("got: (%d, %t), want: (%d, %t)", bigInt, ok, test.expectedBigInt, test.expectedOk)
+			}
+		})
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	none := optional.None[uint]()
+	some := func(n uint) optional.O[uint] { return optional.Some(n) }
+
+	tests := []struct {
+		name            string
+		text            string
+		precision       optional.O[uint]
+		scale           optional.O[uint]
+		expectedDecimal model.Decimal
+		expectErr       bool
+	}{
+		{name: "integer", text: "42", precision: none, scale: none, expectedDecimal: "42"},
+		{name: "fraction", text: "19.99", precision: none, scale: none, expectedDecimal: "19.99"},
+		{name: "trailing zeros are dropped without a scale", text: "1.50", precision: none, scale: none, expectedDecimal: "1.5"},
+		{name: "no integer part", text: ".5", precision: none, scale: none, expectedDecimal: "0.5"},
+		{name: "no fractional digits", text: "1.", precision: none, scale: none, expectedDecimal: "1"},
+		{name: "negative", text: "-0.25", precision: none, scale: none, expectedDecimal: "-0.25"},
+		{name: "exponent", text: "1.5e3", precision: none, scale: none, expectedDecimal: "1500"},
+		{name: "negative exponent", text: "15E-3", precision: none, scale: none, expectedDecimal: "0.015"},
+		{
+			name:            "more digits than a float64 holds",
+			text:            "12345678901234567890.123456789",
+			precision:       none,
+			scale:           none,
+			expectedDecimal: "12345678901234567890.123456789",
+		},
+		{name: "padded to the scale", text: "1.5", precision: some(5), scale: some(2), expectedDecimal: "1.50"},
+		{name: "integer padded to the scale", text: "7", precision: some(5), scale: some(2), expectedDecimal: "7.00"},
+		{name: "negative padded to the scale", text: "-.5", precision: some(5), scale: some(2), expectedDecimal: "-0.50"},
+		{name: "at the precision", text: "999.99", precision: some(5), scale: some(2), expectedDecimal: "999.99"},
+		{name: "leading zeros don't count to the precision", text: "000999.99", precision: some(5), scale: some(2), expectedDecimal: "999.99"},
+		{name: "over the precision", text: "1000", precision: some(5), scale: some(2), expectErr: true},
+		{name: "negative over the precision", text: "-1000", precision: some(5), scale: some(2), expectErr: true},
+		{name: "over the scale", text: "1.005", precision: some(5), scale: some(2), expectErr: true},
+		{name: "over the scale is never rounded", text: "0.001", precision: none, scale: some(0), expectErr: true},
+		{name: "largest exponent", text: "1e1000", precision: none, scale: none, expectedDecimal: model.Decimal("1" + strings.Repeat("0", 1000))},
+		{name: "exponent too large", text: "1e1001", precision: none, scale: none, expectErr: true},
+		{name: "exponent too small", text: "1e-1001", precision: none, scale: none, expectErr: true},
+		{name: "smallest exponent", text: "1e-1000", precision: none, scale: none, expectedDecimal: model.Decimal("0." + strings.Repeat("0", 999) + "1")},
+		{name: "plus sign", text: "+1", precision: none, scale: none, expectErr: true},
+		{name: "not a number", text: "abc", precision: none, scale: none, expectErr: true},
+		{name: "empty", text: "", precision: none, scale: none, expectErr: true},
+		{name: "infinity", text: "Infinity", precision: none, scale: none, expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decimalResult := parseDecimal(test.text, test.precision, test.scale)
+
+			if test.expectErr {
+				if decimalResult.IsOk() {
+					t.Fatalf("expected an error, got: %s", decimalResult.Unwrap())
+				}
+				return
+			}
+
+			if decimalResult.IsErr() {
+				t.Fatalf("unexpected error: %s", decimalResult.UnwrapErr())
+			}
+
+			if decimalResult.Unwrap() != test.expectedDecimal {
+				t.Errorf("got: %s, want: %s", decimalResult.Unwrap(), test.expectedDecimal)
+			}
+		})
+	}
+}
+
+func TestParseIncomingDecimal(t *testing.T) {
+	money := model.FieldDefinition{
+		Type:      model.FieldTypeDecimal,
+		Precision: optional.Some[uint](10),
+		Scale:     optional.Some[uint](2),
+	}
+
+	tests := []struct {
+		name            string
+		field           any
+		fieldDefinition model.FieldDefinition
+		expectedDecimal model.Decimal
+		expectErr       bool
+	}{
+		{name: "number", field: json.Number("19.99"), fieldDefinition: money, expectedDecimal: "19.99"},
+		{name: "string", field: "19.99", fieldDefinition: money, expectedDecimal: "19.99"},
+		{name: "number padded to the scale", field: json.Number("0.1"), fieldDefinition: money, expectedDecimal: "0.10"},
+		{name: "decimal", field: model.Decimal("5"), fieldDefinition: money, expectedDecimal: "5.00"},
+		{name: "int", field: 5, fieldDefinition: money, expectedDecimal: "5.00"},
+		{name: "float", field: 19.99, fieldDefinition: money, expectErr: true},
+		{name: "bool", field: true, fieldDefinition: money, expectErr: true},
+		{
+			name:            "precision without a scale has no decimal places",
+			field:           json.Number("12"),
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeDecimal, Precision: optional.Some[uint](3)},
+			expectedDecimal: "12",
+		},
+		{
+			name:            "precision without a scale rejects decimal places",
+			field:           json.Number("1.5"),
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeDecimal, Precision: optional.Some[uint](3)},
+			expectErr:       true,
+		},
+		{
+			name:            "no precision keeps every digit",
+			field:           "0.1000000000000000055511151231257827",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeDecimal},
+			expectedDecimal: "0.1000000000000000055511151231257827",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decimalResult := parseIncomingDecimal(test.field, test.fieldDefinition)
+
+			if test.expectErr {
+				if decimalResult.IsOk() {
+					t.Fatalf("expected an error, got: %s", decimalResult.Unwrap())
+				}
+				return
+			}
+
+			if decimalResult.IsErr() {
+				t.Fatalf("unexpected error: %s", decimalResult.UnwrapErr())
+			}
+
+			if decimalResult.Unwrap() != test.expectedDecimal {
+				t.Errorf("got: %s, want: %s", decimalResult.Unwrap(), test.expectedDecimal)
+			}
+		})
+	}
+}
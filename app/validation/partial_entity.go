@@ -4,7 +4,6 @@ import (
 	"crudly/model"
 	"crudly/util"
 	"fmt"
-	"math"
 
 	"github.com/google/uuid"
 )
@@ -44,7 +43,7 @@ func validatePartialField(
 	}
 
 	switch fieldDefinition.Type {
-	case model.FieldTypeId:
+	case model.FieldTypeId, model.FieldTypeReference:
 		stringVal, ok := field.(string)
 
 		if !ok {
@@ -61,19 +60,71 @@ func validatePartialField(
 
 		return nil
 	case model.FieldTypeInteger:
-		floatVal, ok := field.(float64)
+		integer, ok := parseIncomingInteger(field)
 
 		if !ok {
 			return fmt.Errorf("field: \"%s\" is not a valid integer", fieldName)
 		}
 
-		truncated := math.Trunc(floatVal)
+		if !isIntegerInRange(integer) {
+			return fmt.Errorf("field: \"%s\" is not a valid integer, %w", fieldName, errIntegerRange)
+		}
 
-		if truncated != floatVal {
-			return fmt.Errorf("field: \"%s\" is not a valid integer", fieldName)
+		err := validateNumberBounds(integer, fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
+		partialEntity[fieldName] = integer
+
+		return nil
+	case model.FieldTypeBigInt:
+		bigInt, ok := parseIncomingBigInt(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid bigint", fieldName)
+		}
+
+		err := validateNumberBounds(bigInt, fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
+		partialEntity[fieldName] = bigInt
+
+		return nil
+	case model.FieldTypeFloat:
+		float, ok := parseIncomingFloat(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid float", fieldName)
+		}
+
+		err := validateNumberBounds(float, fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
+		partialEntity[fieldName] = float
+
+		return nil
+	case model.FieldTypeDecimal:
+		decimalResult := parseIncomingDecimal(field, fieldDefinition)
+
+		if decimalResult.IsErr() {
+			return fmt.Errorf("error parsing field \"%s\" as a decimal: %w", fieldName, decimalResult.UnwrapErr())
+		}
+
+		err := validateNumberBounds(decimalResult.Unwrap(), fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
 		}
 
-		partialEntity[fieldName] = int(truncated)
+		partialEntity[fieldName] = decimalResult.Unwrap()
 
 		return nil
 	case model.FieldTypeBoolean:
@@ -87,12 +138,18 @@ func validatePartialField(
 
 		return nil
 	case model.FieldTypeString:
-		_, ok := field.(string)
+		str, ok := field.(string)
 
 		if !ok {
 			return fmt.Errorf("field: \"%s\" is not a valid string", fieldName)
 		}
 
+		err := validateString(str, fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
 		return nil
 	case model.FieldTypeTime:
 		stringVal, ok := field.(string)
@@ -107,8 +164,54 @@ func validatePartialField(
 			return fmt.Errorf("error parsing field \"%s\" as time: %w", fieldName, timeResult.UnwrapErr())
 		}
 
+		err := validateTimeBounds(timeResult.Unwrap(), fieldDefinition)
+
+		if err != nil {
+			return fmt.Errorf("field: \"%s\" is not valid: %w", fieldName, err)
+		}
+
 		partialEntity[fieldName] = timeResult.Unwrap()
 
+		return nil
+	case model.FieldTypeJson:
+		jsonField, ok := parseIncomingJson(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid json object or array", fieldName)
+		}
+
+		partialEntity[fieldName] = jsonField
+
+		return nil
+	case model.FieldTypeArray:
+		arrayResult := parseIncomingArrayUpdate(field, fieldDefinition)
+
+		if arrayResult.IsErr() {
+			return fmt.Errorf("error parsing field \"%s\" as an array: %w", fieldName, arrayResult.UnwrapErr())
+		}
+
+		partialEntity[fieldName] = arrayResult.Unwrap()
+
+		return nil
+	case model.FieldTypeDate:
+		date, ok := parseIncomingDate(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid date", fieldName)
+		}
+
+		partialEntity[fieldName] = date
+
+		return nil
+	case model.FieldTypeDuration:
+		duration, ok := parseIncomingDuration(field)
+
+		if !ok {
+			return fmt.Errorf("field: \"%s\" is not a valid ISO 8601 duration", fieldName)
+		}
+
+		partialEntity[fieldName] = duration
+
 		return nil
 	case model.FieldTypeEnum:
 		val, ok := field.(string)
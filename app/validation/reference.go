@@ -0,0 +1,29 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"fmt"
+)
+
+// Whether the referenced table exists depends on the rest of the project, so
+// it's checked by the table manager rather than here
+func validateReferenceDefinition(fieldName model.FieldName, fieldDefinition model.FieldDefinition) error {
+	if fieldDefinition.Type != model.FieldTypeReference {
+		if fieldDefinition.References.IsSome() || fieldDefinition.OnDelete.IsSome() {
+			return fmt.Errorf("non reference type definition \"%s\" has a referenced table or onDelete", fieldName)
+		}
+
+		return nil
+	}
+
+	if fieldDefinition.References.IsNone() || fieldDefinition.References.Unwrap() == "" {
+		return fmt.Errorf("reference type definition \"%s\" must include a referenced table", fieldName)
+	}
+
+	if fieldDefinition.OnDelete == optional.Some(model.ReferenceOnDeleteSetNull) && !fieldDefinition.IsOptional {
+		return fmt.Errorf("reference type definition \"%s\" must be optional to set null on delete", fieldName)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"crudly/model"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+func validateStringDefinition(fieldName model.FieldName, fieldDefinition model.FieldDefinition) error {
+	hasConstraints := fieldDefinition.MinLength.IsSome() ||
+		fieldDefinition.MaxLength.IsSome() ||
+		fieldDefinition.Pattern.IsSome() ||
+		fieldDefinition.Format.IsSome()
+
+	if fieldDefinition.Type != model.FieldTypeString {
+		if hasConstraints {
+			return fmt.Errorf("non string type definition \"%s\" has a length, pattern or format", fieldName)
+		}
+
+		return nil
+	}
+
+	if fieldDefinition.MinLength.IsSome() && fieldDefinition.MaxLength.IsSome() {
+		if fieldDefinition.MinLength.Unwrap() > fieldDefinition.MaxLength.Unwrap() {
+			return fmt.Errorf("string type definition \"%s\" has a minLength greater than its maxLength", fieldName)
+		}
+	}
+
+	if fieldDefinition.Pattern.IsSome() {
+		_, err := regexp.Compile(fieldDefinition.Pattern.Unwrap())
+
+		if err != nil {
+			return fmt.Errorf("string type definition \"%s\" has an invalid pattern: %w", fieldName, err)
+		}
+	}
+
+	return nil
+}
+
+func validateString(str string, fieldDefinition model.FieldDefinition) error {
+	length := uint(utf8.RuneCountInString(str))
+
+	if fieldDefinition.MinLength.IsSome() && length < fieldDefinition.MinLength.Unwrap() {
+		return fmt.Errorf("value is shorter than %d characters", fieldDefinition.MinLength.Unwrap())
+	}
+
+	if fieldDefinition.MaxLength.IsSome() && length > fieldDefinition.MaxLength.Unwrap() {
+		return fmt.Errorf("value is longer than %d characters", fieldDefinition.MaxLength.Unwrap())
+	}
+
+	if fieldDefinition.Pattern.IsSome() {
+		// The pattern was checked when the schema was validated
+		pattern := regexp.MustCompile(fieldDefinition.Pattern.Unwrap())
+
+		if !pattern.MatchString(str) {
+			return fmt.Errorf("value does not match pattern: %s", fieldDefinition.Pattern.Unwrap())
+		}
+	}
+
+	if fieldDefinition.Format.IsSome() && !isValidStringFormat(str, fieldDefinition.Format.Unwrap()) {
+		return fmt.Errorf("value is not a valid %s", fieldDefinition.Format.Unwrap().String())
+	}
+
+	return nil
+}
+
+// Only the hyphenated form, which is also what the column's check constraint
+// accepts
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// RFC 1123 host names, e.g. api.example.com
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+const maxHostnameLength = 253
+
+func isValidStringFormat(str string, stringFormat model.StringFormat) bool {
+	switch stringFormat {
+	case model.StringFormatEmail:
+		// Only a bare address, not a display name with the address in brackets
+		address, err := mail.ParseAddress(str)
+
+		return err == nil && address.Address == str
+	case model.StringFormatUrl:
+		parsedUrl, err := url.Parse(str)
+
+		return err == nil && parsedUrl.Scheme != "" && parsedUrl.Host != ""
+	case model.StringFormatUuid:
+		return uuidPattern.MatchString(str)
+	case model.StringFormatHostname:
+		return len(str) <= maxHostnameLength && hostnamePattern.MatchString(str)
+	case model.StringFormatIpv4:
+		ip := net.ParseIP(str)
+
+		return ip != nil && ip.To4() != nil && !strings.Contains(str, ":")
+	case model.StringFormatIpv6:
+		ip := net.ParseIP(str)
+
+		return ip != nil && strings.Contains(str, ":")
+	}
+
+	panic(fmt.Sprintf("invalid string format has entered the system: %+v", stringFormat))
+}
@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"strings"
+	"testing"
+)
+
+func TestIsValidStringFormat(t *testing.T) {
+	tests := []struct {
+		str           string
+		stringFormat  model.StringFormat
+		expectedValid bool
+	}{
+		{str: "ada@example.com", stringFormat: model.StringFormatEmail, expectedValid: true},
+		{str: "ada+tag@mail.example.com", stringFormat: model.StringFormatEmail, expectedValid: true},
+		{str: "Ada <ada@example.com>", stringFormat: model.StringFormatEmail, expectedValid: false},
+		{str: "ada@", stringFormat: model.StringFormatEmail, expectedValid: false},
+		{str: "ada", stringFormat: model.StringFormatEmail, expectedValid: false},
+
+		{str: "https://example.com/path?q=1", stringFormat: model.StringFormatUrl, expectedValid: true},
+		{str: "ftp://files.example.com", stringFormat: model.StringFormatUrl, expectedValid: true},
+		{str: "example.com/path", stringFormat: model.StringFormatUrl, expectedValid: false},
+		{str: "/path", stringFormat: model.StringFormatUrl, expectedValid: false},
+		{str: "mailto:ada@example.com", stringFormat: model.StringFormatUrl, expectedValid: false},
+
+		{str: "0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d", stringFormat: model.StringFormatUuid, expectedValid: true},
+		{str: "0B0E3C4D-5E6F-4A1B-8C2D-3E4F5A6B7C8D", stringFormat: model.StringFormatUuid, expectedValid: true},
+		{str: "0b0e3c4d5e6f4a1b8c2d3e4f5a6b7c8d", stringFormat: model.StringFormatUuid, expectedValid: false},
+		{str: "{0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d}", stringFormat: model.StringFormatUuid, expectedValid: false},
+
+		{str: "api.example.com", stringFormat: model.StringFormatHostname, expectedValid: true},
+		{str: "localhost", stringFormat: model.StringFormatHostname, expectedValid: true},
+		{str: "-example.com", stringFormat: model.StringFormatHostname, expectedValid: false},
+		{str: "example..com", stringFormat: model.StringFormatHostname, expectedValid: false},
+		{str: "under_score.com", stringFormat: model.StringFormatHostname, expectedValid: false},
+		{str: strings.Repeat("a", 64) + ".com", stringFormat: model.StringFormatHostname, expectedValid: false},
+		{str: strings.Repeat("a.", 127) + "ab", stringFormat: model.StringFormatHostname, expectedValid: false},
+
+		{str: "192.168.0.1", stringFormat: model.StringFormatIpv4, expectedValid: true},
+		{str: "256.0.0.1", stringFormat: model.StringFormatIpv4, expectedValid: false},
+		{str: "1.2.3", stringFormat: model.StringFormatIpv4, expectedValid: false},
+		{str: "::1", stringFormat: model.StringFormatIpv4, expectedValid: false},
+		// Mapped addresses are v6 to postgres' family(inet) too
+		{str: "::ffff:1.2.3.4", stringFormat: model.StringFormatIpv4, expectedValid: false},
+
+		{str: "::1", stringFormat: model.StringFormatIpv6, expectedValid: true},
+		{str: "2001:db8::8a2e:370:7334", stringFormat: model.StringFormatIpv6, expectedValid: true},
+		{str: "::ffff:1.2.3.4", stringFormat: model.StringFormatIpv6, expectedValid: true},
+		{str: "1.2.3.4", stringFormat: model.StringFormatIpv6, expectedValid: false},
+		{str: "2001:db8::g", stringFormat: model.StringFormatIpv6, expectedValid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.stringFormat.String()+" "+test.str, func(t *testing.T) {
+			valid := isValidStringFormat(test.str, test.stringFormat)
+
+			if valid != test.expectedValid {
+				t.Errorf("got: %t, want: %t", valid, test.expectedValid)
+			}
+		})
+	}
+}
+
+func TestValidateString(t *testing.T) {
+	tests := []struct {
+		name            string
+		str             string
+		fieldDefinition model.FieldDefinition
+		expectErr       bool
+	}{
+		{
+			name:            "no constraints",
+			str:             "",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString},
+		},
+		{
+			name:            "at the min length",
+			str:             "abc",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, MinLength: optional.Some[uint](3)},
+		},
+		{
+			name:            "under the min length",
+			str:             "ab",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, MinLength: optional.Some[uint](3)},
+			expectErr:       true,
+		},
+		{
+			name:            "at the max length",
+			str:             "abc",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, MaxLength: optional.Some[uint](3)},
+		},
+		{
+			name:            "over the max length",
+			str:             "abcd",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, MaxLength: optional.Some[uint](3)},
+			expectErr:       true,
+		},
+		{
+			// Three characters, but nine bytes
+			name:            "lengths count characters rather than bytes",
+			str:             "日本語",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, MaxLength: optional.Some[uint](3)},
+		},
+		{
+			name:            "multibyte characters under the min length",
+			str:             "日本",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, MinLength: optional.Some[uint](3)},
+			expectErr:       true,
+		},
+		{
+			name:            "matching the pattern",
+			str:             "SKU-123",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, Pattern: optional.Some(`^SKU-\d+$`)},
+		},
+		{
+			name:            "not matching the pattern",
+			str:             "SKU-12a",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, Pattern: optional.Some(`^SKU-\d+$`)},
+			expectErr:       true,
+		},
+		{
+			name:            "unanchored patterns match anywhere",
+			str:             "order SKU-1 shipped",
+			fieldDefinition: model.FieldDefinition{Type: model.FieldTypeString, Pattern: optional.Some(`SKU-\d+`)},
+		},
+		{
+			name: "in the format",
+			str:  "ada@example.com",
+			fieldDefinition: model.FieldDefinition{
+				Type:   model.FieldTypeString,
+				Format: optional.Some(model.StringFormatEmail),
+			},
+		},
+		{
+			name: "not in the format",
+			str:  "ada",
+			fieldDefinition: model.FieldDefinition{
+				Type:   model.FieldTypeString,
+				Format: optional.Some(model.StringFormatEmail),
+			},
+			expectErr: true,
+		},
+		{
+			name: "in the format but over the max length",
+			str:  "ada@example.com",
+			fieldDefinition: model.FieldDefinition{
+				Type:      model.FieldTypeString,
+				MaxLength: optional.Some[uint](5),
+				Format:    optional.Some(model.StringFormatEmail),
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateString(test.str, test.fieldDefinition)
+
+			if test.expectErr && err == nil {
+				t.Errorf("expected an error")
+			}
+
+			if !test.expectErr && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
+	"fmt"
+)
+
+type tableIndexValidator struct{}
+
+func NewTableIndexValidator() tableIndexValidator {
+	return tableIndexValidator{}
+}
+
+// Also converts the comparators of a partial index's filter into their field
+// types, the same as validating a query filter does
+func (t *tableIndexValidator) ValidateTableIndex(
+	name model.IndexName,
+	index *model.TableIndex,
+	tableSchema model.TableSchema,
+) error {
+	if name == "" {
+		return fmt.Errorf("index name must not be empty")
+	}
+
+	if len(index.Fields) == 0 {
+		return fmt.Errorf("index \"%s\" must include at least one field", name)
+	}
+
+	for i, fieldName := range index.Fields {
+		if util.Contains(index.Fields[:i], fieldName) {
+			return fmt.Errorf("index \"%s\" includes field \"%s\" more than once", name, fieldName)
+		}
+
+		if fieldName == "id" {
+			continue
+		}
+
+		if _, ok := tableSchema[fieldName]; !ok {
+			return fmt.Errorf("index \"%s\" includes field \"%s\" which does not exist", name, fieldName)
+		}
+	}
+
+	if index.Filter.IsNone() {
+		return nil
+	}
+
+	filter := index.Filter.Unwrap()
+	entityFilterValidator := NewEntityFilterValidator()
+
+	err := entityFilterValidator.ValidateEntityFilterExpression(&filter, tableSchema)
+
+	if err != nil {
+		return fmt.Errorf("index \"%s\" has an invalid filter: %w", name, err)
+	}
+
+	index.Filter = optional.Some(filter)
+
+	return nil
+}
@@ -18,7 +18,7 @@ func (t *tableSchemaValidator) ValidateTableSchema(schema model.TableSchema) err
 	}
 
 	for k, v := range schema {
-		if v.Type == model.FieldTypeEnum {
+		if isEnumDefinition(v) {
 			if v.Values.IsNone() {
 				return fmt.Errorf("enum type \"%s\" definition must include a values array", k)
 			}
@@ -27,6 +27,36 @@ func (t *tableSchemaValidator) ValidateTableSchema(schema model.TableSchema) err
 				return fmt.Errorf("non enum type definition \"%s\" has a values array", k)
 			}
 		}
+
+		err := validateDecimalDefinition(k, v)
+
+		if err != nil {
+			return err
+		}
+
+		err = validateArrayDefinition(k, v)
+
+		if err != nil {
+			return err
+		}
+
+		err = validateStringDefinition(k, v)
+
+		if err != nil {
+			return err
+		}
+
+		err = validateBoundsDefinition(k, v)
+
+		if err != nil {
+			return err
+		}
+
+		err = validateReferenceDefinition(k, v)
+
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"crudly/model"
+	"crudly/util"
+	"fmt"
+)
+
+type upsertKeyValidator struct{}
+
+func NewUpsertKeyValidator() upsertKeyValidator {
+	return upsertKeyValidator{}
+}
+
+func (u *upsertKeyValidator) ValidateUpsertKey(
+	upsertKey model.UpsertKey,
+	tableSchema model.TableSchema,
+) error {
+	if len(upsertKey) == 0 {
+		return fmt.Errorf("upsert key must include at least one field")
+	}
+
+	for i, fieldName := range upsertKey {
+		if util.Contains(upsertKey[:i], fieldName) {
+			return fmt.Errorf("field: \"%s\" is included more than once", fieldName)
+		}
+
+		if _, ok := tableSchema[fieldName]; !ok {
+			return fmt.Errorf("field: \"%s\" does not exist", fieldName)
+		}
+	}
+
+	return nil
+}
+
+// Nulls never conflict, so an entity without a value for every key field
+// would always be inserted
+func (u *upsertKeyValidator) ValidateUpsertedEntity(
+	upsertKey model.UpsertKey,
+	entity model.Entity,
+) error {
+	for _, fieldName := range upsertKey {
+		if entity[fieldName] == nil {
+			return fmt.Errorf("entity has no value for upsert field: \"%s\"", fieldName)
+		}
+	}
+
+	return nil
+}
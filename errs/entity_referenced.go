@@ -0,0 +1,7 @@
+package errs
+
+type EntityReferencedError struct{}
+
+func (e EntityReferencedError) Error() string {
+	return "entity is still referenced by other entities"
+}
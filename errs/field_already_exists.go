@@ -0,0 +1,7 @@
+package errs
+
+type FieldAlreadyExistsError struct{}
+
+func (f FieldAlreadyExistsError) Error() string {
+	return "field already exists"
+}
@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type FieldConversionError struct {
+	conversionError error
+}
+
+func NewFieldConversionError(conversionError error) FieldConversionError {
+	return FieldConversionError{
+		conversionError,
+	}
+}
+
+func (f FieldConversionError) Error() string {
+	return fmt.Sprintf("existing entities couldn't be converted: %s", f.conversionError)
+}
@@ -0,0 +1,7 @@
+package errs
+
+type IndexAlreadyExistsError struct{}
+
+func (i IndexAlreadyExistsError) Error() string {
+	return "index already exists"
+}
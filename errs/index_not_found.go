@@ -0,0 +1,7 @@
+package errs
+
+type IndexNotFoundError struct{}
+
+func (i IndexNotFoundError) Error() string {
+	return "index not found"
+}
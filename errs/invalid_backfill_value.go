@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidBackfillValueError struct {
+	validationError error
+}
+
+func NewInvalidBackfillValueError(validationError error) InvalidBackfillValueError {
+	return InvalidBackfillValueError{
+		validationError,
+	}
+}
+
+func (i InvalidBackfillValueError) Error() string {
+	return fmt.Sprintf("backfill value is not valid: %s", i.validationError)
+}
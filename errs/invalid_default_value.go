@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidDefaultValueError struct {
+	validationError error
+}
+
+func NewInvalidDefaultValueError(validationError error) InvalidDefaultValueError {
+	return InvalidDefaultValueError{
+		validationError,
+	}
+}
+
+func (i InvalidDefaultValueError) Error() string {
+	return fmt.Sprintf("default value is not valid: %s", i.validationError)
+}
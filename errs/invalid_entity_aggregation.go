@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidEntityAggregationError struct {
+	validationError error
+}
+
+func NewInvalidEntityAggregationError(validationError error) InvalidEntityAggregationError {
+	return InvalidEntityAggregationError{
+		validationError,
+	}
+}
+
+func (i InvalidEntityAggregationError) Error() string {
+	return fmt.Sprintf("entity aggregation is not valid: %s", i.validationError)
+}
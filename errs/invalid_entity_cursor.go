@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidEntityCursorError struct {
+	validationError error
+}
+
+func NewInvalidEntityCursorError(validationError error) InvalidEntityCursorError {
+	return InvalidEntityCursorError{
+		validationError,
+	}
+}
+
+func (i InvalidEntityCursorError) Error() string {
+	return fmt.Sprintf("entity cursor is not valid: %s", i.validationError)
+}
@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidEntityExpansionError struct {
+	validationError error
+}
+
+func NewInvalidEntityExpansionError(validationError error) InvalidEntityExpansionError {
+	return InvalidEntityExpansionError{
+		validationError,
+	}
+}
+
+func (i InvalidEntityExpansionError) Error() string {
+	return fmt.Sprintf("expansion is not valid: %s", i.validationError)
+}
@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidFieldAlterationError struct {
+	validationError error
+}
+
+func NewInvalidFieldAlterationError(validationError error) InvalidFieldAlterationError {
+	return InvalidFieldAlterationError{
+		validationError,
+	}
+}
+
+func (i InvalidFieldAlterationError) Error() string {
+	return fmt.Sprintf("field alteration is not valid: %s", i.validationError)
+}
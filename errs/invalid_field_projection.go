@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidFieldProjectionError struct {
+	validationError error
+}
+
+func NewInvalidFieldProjectionError(validationError error) InvalidFieldProjectionError {
+	return InvalidFieldProjectionError{
+		validationError,
+	}
+}
+
+func (i InvalidFieldProjectionError) Error() string {
+	return fmt.Sprintf("field projection is not valid: %s", i.validationError)
+}
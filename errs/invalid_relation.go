@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidRelationError struct {
+	validationError error
+}
+
+func NewInvalidRelationError(validationError error) InvalidRelationError {
+	return InvalidRelationError{
+		validationError,
+	}
+}
+
+func (i InvalidRelationError) Error() string {
+	return fmt.Sprintf("relation is not valid: %s", i.validationError)
+}
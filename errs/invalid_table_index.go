@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidTableIndexError struct {
+	validationError error
+}
+
+func NewInvalidTableIndexError(validationError error) InvalidTableIndexError {
+	return InvalidTableIndexError{
+		validationError,
+	}
+}
+
+func (i InvalidTableIndexError) Error() string {
+	return fmt.Sprintf("table index is not valid: %s", i.validationError)
+}
@@ -0,0 +1,17 @@
+package errs
+
+import "fmt"
+
+type InvalidUpsertKeyError struct {
+	validationError error
+}
+
+func NewInvalidUpsertKeyError(validationError error) InvalidUpsertKeyError {
+	return InvalidUpsertKeyError{
+		validationError,
+	}
+}
+
+func (i InvalidUpsertKeyError) Error() string {
+	return fmt.Sprintf("upsert key is not valid: %s", i.validationError)
+}
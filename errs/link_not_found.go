@@ -0,0 +1,7 @@
+package errs
+
+type LinkNotFoundError struct{}
+
+func (l LinkNotFoundError) Error() string {
+	return "link not found"
+}
@@ -0,0 +1,20 @@
+package errs
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ReferencedEntityNotFoundError struct {
+	fieldNames []string
+}
+
+func NewReferencedEntityNotFoundError(fieldNames []string) ReferencedEntityNotFoundError {
+	return ReferencedEntityNotFoundError{
+		fieldNames,
+	}
+}
+
+func (r ReferencedEntityNotFoundError) Error() string {
+	return fmt.Sprintf("referenced entity not found for: %s", strings.Join(r.fieldNames, ", "))
+}
@@ -0,0 +1,7 @@
+package errs
+
+type TableAlreadyExistsError struct{}
+
+func (t TableAlreadyExistsError) Error() string {
+	return "table already exists"
+}
@@ -0,0 +1,20 @@
+package errs
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TableReferencedError struct {
+	referencingFields []string
+}
+
+func NewTableReferencedError(referencingFields []string) TableReferencedError {
+	return TableReferencedError{
+		referencingFields,
+	}
+}
+
+func (t TableReferencedError) Error() string {
+	return fmt.Sprintf("table is still referenced by: %s", strings.Join(t.referencingFields, ", "))
+}
@@ -0,0 +1,20 @@
+package errs
+
+import (
+	"fmt"
+	"strings"
+)
+
+type UniqueViolationError struct {
+	fieldNames []string
+}
+
+func NewUniqueViolationError(fieldNames []string) UniqueViolationError {
+	return UniqueViolationError{
+		fieldNames,
+	}
+}
+
+func (u UniqueViolationError) Error() string {
+	return fmt.Sprintf("unique constraint violated on: %s", strings.Join(u.fieldNames, ", "))
+}
@@ -0,0 +1,330 @@
+package dto
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type EntityAggregationTypeDto string
+
+func (e EntityAggregationTypeDto) ToModel() result.R[model.EntityAggregationType] {
+	switch string(e) {
+	case "count":
+		return result.Ok(model.EntityAggregationTypeCount)
+	case "sum":
+		return result.Ok(model.EntityAggregationTypeSum)
+	case "avg":
+		return result.Ok(model.EntityAggregationTypeAvg)
+	case "min":
+		return result.Ok(model.EntityAggregationTypeMin)
+	case "max":
+		return result.Ok(model.EntityAggregationTypeMax)
+	}
+	return result.Errf[model.EntityAggregationType]("unrecognised aggregation type: %s", string(e))
+}
+
+// Either a bare aggregation type (e.g. count) or one applied to a field
+// (e.g. sum(price))
+type EntityAggregationDto string
+
+func (e EntityAggregationDto) ToModel() result.R[model.EntityAggregation] {
+	aggregationType, fieldName, hasField := strings.Cut(string(e), "(")
+
+	entityAggregationTypeResult := EntityAggregationTypeDto(aggregationType).ToModel()
+
+	if entityAggregationTypeResult.IsErr() {
+		return result.Err[model.EntityAggregation](entityAggregationTypeResult.UnwrapErr())
+	}
+
+	entityAggregation := model.EntityAggregation{
+		Type:      entityAggregationTypeResult.Unwrap(),
+		FieldName: optional.None[model.FieldName](),
+	}
+
+	if !hasField {
+		return result.Ok(entityAggregation)
+	}
+
+	if !strings.HasSuffix(fieldName, ")") {
+		return result.Errf[model.EntityAggregation]("aggregation: \"%s\" is missing a closing bracket", string(e))
+	}
+
+	fieldNameResult := FieldNameDto(strings.TrimSuffix(fieldName, ")")).ToModel()
+
+	if fieldNameResult.IsErr() {
+		return result.Errf[model.EntityAggregation]("error parsing aggregation field name: %w", fieldNameResult.UnwrapErr())
+	}
+
+	if fieldNameResult.Unwrap() == "" {
+		return result.Errf[model.EntityAggregation]("aggregation: \"%s\" has an empty field name", string(e))
+	}
+
+	entityAggregation.FieldName = optional.Some(fieldNameResult.Unwrap())
+
+	return result.Ok(entityAggregation)
+}
+
+type HistogramUnitDto string
+
+func (h HistogramUnitDto) ToModel() result.R[model.HistogramUnit] {
+	switch string(h) {
+	case "minute":
+		return result.Ok(model.HistogramUnitMinute)
+	case "hour":
+		return result.Ok(model.HistogramUnitHour)
+	case "day":
+		return result.Ok(model.HistogramUnitDay)
+	case "week":
+		return result.Ok(model.HistogramUnitWeek)
+	case "month":
+		return result.Ok(model.HistogramUnitMonth)
+	}
+	return result.Errf[model.HistogramUnit]("unrecognised histogram unit: %s", string(h))
+}
+
+// Reads the histogram, interval and timezone query params. The interval is
+// either a calendar unit (e.g. day) or a fixed duration (e.g. 15m)
+func GetEntityHistogramFromQuery(query url.Values) result.R[optional.O[model.EntityHistogram]] {
+	if !query.Has("histogram") {
+		if query.Has("interval") || query.Has("timezone") {
+			return result.Errf[optional.O[model.EntityHistogram]]("interval and timezone can only be given with a histogram")
+		}
+
+		return result.Ok(optional.None[model.EntityHistogram]())
+	}
+
+	fieldNameResult := FieldNameDto(query.Get("histogram")).ToModel()
+
+	if fieldNameResult.IsErr() {
+		return result.Errf[optional.O[model.EntityHistogram]]("error parsing histogram field name: %w", fieldNameResult.UnwrapErr())
+	}
+
+	entityHistogram := model.EntityHistogram{
+		FieldName: fieldNameResult.Unwrap(),
+		Unit:      optional.None[model.HistogramUnit](),
+		Interval:  optional.None[time.Duration](),
+		Timezone:  optional.None[string](),
+	}
+
+	interval := query.Get("interval")
+
+	if interval == "" {
+		return result.Errf[optional.O[model.EntityHistogram]]("histogram requires an interval")
+	}
+
+	histogramUnitResult := HistogramUnitDto(interval).ToModel()
+
+	if histogramUnitResult.IsOk() {
+		entityHistogram.Unit = optional.Some(histogramUnitResult.Unwrap())
+	} else {
+		duration, err := time.ParseDuration(interval)
+
+		if err != nil {
+			return result.Errf[optional.O[model.EntityHistogram]]("unrecognised histogram interval: %s", interval)
+		}
+
+		entityHistogram.Interval = optional.Some(duration)
+	}
+
+	if query.Has("timezone") {
+		entityHistogram.Timezone = optional.Some(query.Get("timezone"))
+	}
+
+	return result.Ok(optional.Some(entityHistogram))
+}
+
+// Reads the filter, groupBy, aggregate and histogram query params. When no
+// aggregates are given the rows in each group are counted
+func GetEntityAggregationQueryFromQuery(query url.Values) result.R[model.EntityAggregationQuery] {
+	entityFilterResult := GetEntityFilterFromQuery(query)
+
+	if entityFilterResult.IsErr() {
+		return result.Err[model.EntityAggregationQuery](entityFilterResult.UnwrapErr())
+	}
+
+	groupBy := []model.FieldName{}
+
+	for _, groupByQuery := range query["groupBy"] {
+		for _, fieldName := range strings.Split(groupByQuery, ",") {
+			fieldNameResult := FieldNameDto(fieldName).ToModel()
+
+			if fieldNameResult.IsErr() {
+				return result.Errf[model.EntityAggregationQuery]("error parsing group by field name: %w", fieldNameResult.UnwrapErr())
+			}
+
+			groupBy = append(groupBy, fieldNameResult.Unwrap())
+		}
+	}
+
+	entityAggregations := model.EntityAggregations{}
+
+	for _, aggregateQuery := range query["aggregate"] {
+		entityAggregationResult := EntityAggregationDto(aggregateQuery).ToModel()
+
+		if entityAggregationResult.IsErr() {
+			return result.Err[model.EntityAggregationQuery](entityAggregationResult.UnwrapErr())
+		}
+
+		entityAggregations = append(entityAggregations, entityAggregationResult.Unwrap())
+	}
+
+	if len(entityAggregations) == 0 {
+		entityAggregations = append(entityAggregations, model.EntityAggregation{
+			Type:      model.EntityAggregationTypeCount,
+			FieldName: optional.None[model.FieldName](),
+		})
+	}
+
+	entityHistogramResult := GetEntityHistogramFromQuery(query)
+
+	if entityHistogramResult.IsErr() {
+		return result.Err[model.EntityAggregationQuery](entityHistogramResult.UnwrapErr())
+	}
+
+	return result.Ok(model.EntityAggregationQuery{
+		Filter:       entityFilterResult.Unwrap(),
+		GroupBy:      groupBy,
+		Aggregations: entityAggregations,
+		Histogram:    entityHistogramResult.Unwrap(),
+	})
+}
+
+type EntityAggregationResultDto struct {
+	Group  map[FieldNameDto]FieldDto `json:"group"`
+	Values map[string]FieldDto       `json:"values"`
+}
+
+type EntityAggregationResponseDto struct {
+	Results []EntityAggregationResultDto `json:"results"`
+}
+
+func (b BigIntFormatDto) FormatEntityAggregationResponse(
+	entityAggregationResponseDto EntityAggregationResponseDto,
+) EntityAggregationResponseDto {
+	for _, resultDto := range entityAggregationResponseDto.Results {
+		for fieldName, fieldDto := range resultDto.Group {
+			resultDto.Group[fieldName] = b.FormatField(fieldDto)
+		}
+
+		for aggregation, fieldDto := range resultDto.Values {
+			resultDto.Values[aggregation] = b.FormatField(fieldDto)
+		}
+	}
+
+	return entityAggregationResponseDto
+}
+
+func GetEntityAggregationResponseDto(entityAggregationResponse model.EntityAggregationResponse) EntityAggregationResponseDto {
+	results := []EntityAggregationResultDto{}
+
+	for _, entityAggregationResult := range entityAggregationResponse.Results {
+		resultDto := EntityAggregationResultDto{
+			Group:  map[FieldNameDto]FieldDto{},
+			Values: map[string]FieldDto{},
+		}
+
+		for fieldName, field := range entityAggregationResult.Group {
+			resultDto.Group[GetFieldNameDto(fieldName)] = GetFieldDto(field)
+		}
+
+		for i, entityAggregation := range entityAggregationResponse.Aggregations {
+			resultDto.Values[entityAggregation.String()] = GetFieldDto(entityAggregationResult.Values[i])
+		}
+
+		results = append(results, resultDto)
+	}
+
+	return EntityAggregationResponseDto{
+		Results: results,
+	}
+}
+
+type FieldValuesOrderTypeDto string
+
+func (f FieldValuesOrderTypeDto) ToModel() result.R[model.FieldValuesOrderType] {
+	switch string(f) {
+	case "", "count":
+		return result.Ok(model.FieldValuesOrderTypeCount)
+	case "value":
+		return result.Ok(model.FieldValuesOrderTypeValue)
+	}
+	return result.Errf[model.FieldValuesOrderType]("unrecognised field values order: %s", string(f))
+}
+
+// Reads the filter, order, limit and offset query params
+func GetFieldValuesQueryFromQuery(query url.Values) result.R[model.FieldValuesQuery] {
+	entityFilterResult := GetEntityFilterFromQuery(query)
+
+	if entityFilterResult.IsErr() {
+		return result.Err[model.FieldValuesQuery](entityFilterResult.UnwrapErr())
+	}
+
+	orderResult := FieldValuesOrderTypeDto(query.Get("order")).ToModel()
+
+	if orderResult.IsErr() {
+		return result.Err[model.FieldValuesQuery](orderResult.UnwrapErr())
+	}
+
+	paginationParams := model.PaginationParams{
+		Limit:  model.DefaultPaginationLimit,
+		Offset: model.DefaultPaginationOffset,
+	}
+
+	if query.Get("limit") != "" {
+		limitResult := PaginationLimitPathParam(query.Get("limit")).ToModel()
+
+		if limitResult.IsErr() {
+			return result.Err[model.FieldValuesQuery](limitResult.UnwrapErr())
+		}
+
+		paginationParams.Limit = limitResult.Unwrap()
+	}
+
+	if query.Get("offset") != "" {
+		offsetResult := PaginationOffsetPathParam(query.Get("offset")).ToModel()
+
+		if offsetResult.IsErr() {
+			return result.Err[model.FieldValuesQuery](offsetResult.UnwrapErr())
+		}
+
+		paginationParams.Offset = offsetResult.Unwrap()
+	}
+
+	return result.Ok(model.FieldValuesQuery{
+		Filter:           entityFilterResult.Unwrap(),
+		Order:            orderResult.Unwrap(),
+		PaginationParams: paginationParams,
+	})
+}
+
+type FieldValueCountDto struct {
+	Value FieldDto `json:"value"`
+	Count int      `json:"count"`
+}
+
+type GetFieldValuesResponseDto struct {
+	Values []FieldValueCountDto `json:"values"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+func GetGetFieldValuesResponseDto(getFieldValuesResponse model.GetFieldValuesResponse) GetFieldValuesResponseDto {
+	values := []FieldValueCountDto{}
+
+	for _, fieldValueCount := range getFieldValuesResponse.Values {
+		values = append(values, FieldValueCountDto{
+			Value: GetFieldDto(fieldValueCount.Value),
+			Count: int(fieldValueCount.Count),
+		})
+	}
+
+	return GetFieldValuesResponseDto{
+		Values: values,
+		Limit:  int(getFieldValuesResponse.Limit),
+		Offset: int(getFieldValuesResponse.Offset),
+	}
+}
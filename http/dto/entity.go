@@ -2,9 +2,14 @@ package dto
 
 import (
 	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
 	"crudly/util/result"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,11 +29,35 @@ func (e EntityIdDto) ToModel() result.R[model.EntityId] {
 
 type FieldDto any
 
-const TimeFormat = "2006-01-02T15:04:05"
+// RFC 3339 in UTC to the microsecond, the precision times are stored at
+const TimeFormat = "2006-01-02T15:04:05.000000Z07:00"
 
 func GetFieldDto(field model.Field) FieldDto {
 	if time, ok := field.(time.Time); ok {
-		return time.Format(TimeFormat)
+		return time.UTC().Format(TimeFormat)
+	}
+
+	// Written out as a JSON number with exactly the stored digits
+	if decimal, ok := field.(model.Decimal); ok {
+		return json.Number(decimal.String())
+	}
+
+	if date, ok := field.(model.Date); ok {
+		return date.String()
+	}
+
+	if duration, ok := field.(model.Duration); ok {
+		return duration.String()
+	}
+
+	// Passed through as is rather than as a string holding the JSON
+	if jsonField, ok := field.(model.Json); ok {
+		return json.RawMessage(jsonField.String())
+	}
+
+	// Expanded references hold the referenced entity in place of its id
+	if entity, ok := field.(model.Entity); ok {
+		return GetEntityDto(entity)
 	}
 
 	return FieldDto(any(field))
@@ -41,6 +70,20 @@ func FieldDtoToModel(f FieldDto) result.R[model.Field] {
 
 type EntityDto map[FieldNameDto]FieldDto
 
+func (e *EntityDto) UnmarshalJSON(data []byte) error {
+	entity := map[FieldNameDto]FieldDto{}
+
+	err := unmarshalJsonPreservingNumbers(data, &entity)
+
+	if err != nil {
+		return err
+	}
+
+	*e = entity
+
+	return nil
+}
+
 func (e EntityDto) ToModel() result.R[model.Entity] {
 	res := model.Entity{}
 
@@ -113,6 +156,20 @@ func GetEntitiesDto(entities model.Entities) EntitiesDto {
 
 type PartialEntityDto map[FieldNameDto]FieldDto
 
+func (p *PartialEntityDto) UnmarshalJSON(data []byte) error {
+	partialEntity := map[FieldNameDto]FieldDto{}
+
+	err := unmarshalJsonPreservingNumbers(data, &partialEntity)
+
+	if err != nil {
+		return err
+	}
+
+	*p = partialEntity
+
+	return nil
+}
+
 func (p PartialEntityDto) ToModel() result.R[model.PartialEntity] {
 	res := model.PartialEntity{}
 
@@ -137,18 +194,193 @@ func (p PartialEntityDto) ToModel() result.R[model.PartialEntity] {
 	return result.Ok(res)
 }
 
+type EntityCountTypeDto string
+
+func (e EntityCountTypeDto) ToModel() result.R[model.EntityCountType] {
+	switch string(e) {
+	case "", "exact":
+		return result.Ok(model.EntityCountTypeExact)
+	case "estimated":
+		return result.Ok(model.EntityCountTypeEstimated)
+	case "none":
+		return result.Ok(model.EntityCountTypeNone)
+	}
+	return result.Errf[model.EntityCountType]("unrecognised count type: %s", string(e))
+}
+
+func GetEntityCountTypeDto(countType model.EntityCountType) EntityCountTypeDto {
+	return EntityCountTypeDto(countType.String())
+}
+
 type GetEntitiesResponseDto struct {
-	Entities   EntitiesDto `json:"entities"`
-	TotalCount int         `json:"totalCount"`
-	Limit      int         `json:"limit"`
-	Offset     int         `json:"offset"`
+	Entities   EntitiesDto        `json:"entities"`
+	CountType  EntityCountTypeDto `json:"countType"`
+	TotalCount *int               `json:"totalCount"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+	Cursor     *EntityCursorDto   `json:"cursor,omitempty"`
+	NextCursor *EntityCursorDto   `json:"nextCursor"`
 }
 
 func GetGetEntitiesResponseDto(entities model.GetEntitiesResponse) GetEntitiesResponseDto {
+	var totalCount *int
+
+	if entities.CountType != model.EntityCountTypeNone {
+		totalCount = util.Ptr(int(entities.TotalCount))
+	}
+
+	var cursor, nextCursor *EntityCursorDto
+
+	if entities.Cursor.IsSome() {
+		cursor = util.Ptr(GetEntityCursorDto(entities.Cursor.Unwrap()))
+	}
+
+	if entities.NextCursor.IsSome() {
+		nextCursor = util.Ptr(GetEntityCursorDto(entities.NextCursor.Unwrap()))
+	}
+
 	return GetEntitiesResponseDto{
 		Entities:   GetEntitiesDto(entities.Entities),
-		TotalCount: int(entities.TotalCount),
+		CountType:  GetEntityCountTypeDto(entities.CountType),
+		TotalCount: totalCount,
 		Limit:      int(entities.Limit),
 		Offset:     int(entities.Offset),
+		Cursor:     cursor,
+		NextCursor: nextCursor,
+	}
+}
+
+type EntityQueryDto struct {
+	Filter *EntityFilterExpressionDto `json:"filter,omitempty"`
+	Order  []EntityOrderDto           `json:"order,omitempty"`
+	Limit  *uint                      `json:"limit,omitempty"`
+	Offset *uint                      `json:"offset,omitempty"`
+	Cursor *EntityCursorDto           `json:"cursor,omitempty"`
+	Count  EntityCountTypeDto         `json:"count,omitempty"`
+	Fields *FieldProjectionDto        `json:"fields,omitempty"`
+	Expand EntityExpansionDto         `json:"expand,omitempty"`
+}
+
+func (e EntityQueryDto) ToModel() result.R[model.EntityQuery] {
+	entityQuery := model.EntityQuery{
+		FilterExpression: model.EmptyEntityFilterExpression(),
+		Orders:           model.EntityOrders{},
+		PaginationParams: model.PaginationParams{
+			Limit:  model.DefaultPaginationLimit,
+			Offset: model.DefaultPaginationOffset,
+		},
+	}
+
+	if e.Filter != nil {
+		filterExpressionResult := e.Filter.ToModel()
+
+		if filterExpressionResult.IsErr() {
+			return result.Errf[model.EntityQuery]("error parsing filter: %w", filterExpressionResult.UnwrapErr())
+		}
+
+		entityQuery.FilterExpression = filterExpressionResult.Unwrap()
+	}
+
+	for _, orderDto := range e.Order {
+		orderResult := orderDto.ToModel()
+
+		if orderResult.IsErr() {
+			return result.Err[model.EntityQuery](orderResult.UnwrapErr())
+		}
+
+		entityQuery.Orders = append(entityQuery.Orders, orderResult.Unwrap())
+	}
+
+	if e.Limit != nil {
+		entityQuery.PaginationParams.Limit = model.PaginationLimit(*e.Limit)
+	}
+
+	if e.Offset != nil {
+		entityQuery.PaginationParams.Offset = model.PaginationOffset(*e.Offset)
+	}
+
+	countTypeResult := e.Count.ToModel()
+
+	if countTypeResult.IsErr() {
+		return result.Err[model.EntityQuery](countTypeResult.UnwrapErr())
 	}
+
+	entityQuery.CountType = countTypeResult.Unwrap()
+
+	if e.Fields != nil {
+		fieldProjectionResult := e.Fields.ToModel()
+
+		if fieldProjectionResult.IsErr() {
+			return result.Err[model.EntityQuery](fieldProjectionResult.UnwrapErr())
+		}
+
+		entityQuery.FieldProjection = optional.Some(fieldProjectionResult.Unwrap())
+	}
+
+	if e.Cursor != nil {
+		cursorResult := e.Cursor.ToModel()
+
+		if cursorResult.IsErr() {
+			return result.Err[model.EntityQuery](cursorResult.UnwrapErr())
+		}
+
+		entityQuery.PaginationParams.Cursor = optional.Some(cursorResult.Unwrap())
+	}
+
+	entityExpansionResult := e.Expand.ToModel()
+
+	if entityExpansionResult.IsErr() {
+		return result.Err[model.EntityQuery](entityExpansionResult.UnwrapErr())
+	}
+
+	entityQuery.Expansion = entityExpansionResult.Unwrap()
+
+	return result.Ok(entityQuery)
+}
+
+// How bigint fields are written out. JavaScript clients can't hold integers
+// above 2^53 exactly so can ask for them as strings instead
+type BigIntFormatDto string
+
+const (
+	BigIntFormatNumber BigIntFormatDto = "number"
+	BigIntFormatString BigIntFormatDto = "string"
+)
+
+func GetBigIntFormatFromQuery(query url.Values) result.R[BigIntFormatDto] {
+	switch query.Get("bigIntFormat") {
+	case "", "number":
+		return result.Ok(BigIntFormatNumber)
+	case "string":
+		return result.Ok(BigIntFormatString)
+	}
+	return result.Errf[BigIntFormatDto]("unrecognised bigint format: %s", query.Get("bigIntFormat"))
+}
+
+func (b BigIntFormatDto) FormatField(fieldDto FieldDto) FieldDto {
+	if bigInt, ok := fieldDto.(int64); ok && b == BigIntFormatString {
+		return strconv.FormatInt(bigInt, 10)
+	}
+
+	if entityDto, ok := fieldDto.(EntityDto); ok {
+		return b.FormatEntity(entityDto)
+	}
+
+	return fieldDto
+}
+
+func (b BigIntFormatDto) FormatEntity(entityDto EntityDto) EntityDto {
+	for fieldName, fieldDto := range entityDto {
+		entityDto[fieldName] = b.FormatField(fieldDto)
+	}
+
+	return entityDto
+}
+
+func (b BigIntFormatDto) FormatEntities(entitiesDto EntitiesDto) EntitiesDto {
+	for _, entityDto := range entitiesDto {
+		b.FormatEntity(entityDto)
+	}
+
+	return entitiesDto
 }
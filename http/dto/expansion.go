@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"crudly/model"
+	"crudly/util/result"
+	"net/url"
+	"strings"
+)
+
+// Each expansion is a dot separated path through reference fields, e.g.
+// customer.company
+type EntityExpansionDto []string
+
+func (e EntityExpansionDto) ToModel() result.R[model.EntityExpansion] {
+	entityExpansion := model.EntityExpansion{}
+
+	for _, expansionPathDto := range e {
+		expansionPath := model.ExpansionPath{}
+
+		for _, fieldName := range strings.Split(expansionPathDto, ".") {
+			if fieldName == "" {
+				return result.Errf[model.EntityExpansion]("expansion path \"%s\" contains an empty field name", expansionPathDto)
+			}
+
+			fieldNameResult := FieldNameDto(fieldName).ToModel()
+
+			if fieldNameResult.IsErr() {
+				return result.Errf[model.EntityExpansion]("error parsing field name: %w", fieldNameResult.UnwrapErr())
+			}
+
+			expansionPath = append(expansionPath, fieldNameResult.Unwrap())
+		}
+
+		entityExpansion = append(entityExpansion, expansionPath)
+	}
+
+	return result.Ok(entityExpansion)
+}
+
+// Reads comma separated expansion paths from any expand query params
+func GetEntityExpansionFromQuery(query url.Values) result.R[model.EntityExpansion] {
+	entityExpansionDto := EntityExpansionDto{}
+
+	for _, expandQuery := range query["expand"] {
+		entityExpansionDto = append(entityExpansionDto, strings.Split(expandQuery, ",")...)
+	}
+
+	return entityExpansionDto.ToModel()
+}
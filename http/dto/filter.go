@@ -3,71 +3,405 @@ package dto
 import (
 	"crudly/model"
 	"crudly/util/result"
+	"encoding/json"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
+type fieldFilterOperator struct {
+	token      string
+	filterType model.FieldFilterType
+}
+
+// Where operators share a prefix the longer one must come first, as ties on
+// position are broken by order in this list
+var fieldFilterOperators = []fieldFilterOperator{
+	{" is not null", model.FieldFilterTypeIsNotNull},
+	{" is null", model.FieldFilterTypeIsNull},
+	{" in ", model.FieldFilterTypeIn},
+	{" containsAny ", model.FieldFilterTypeContainsAny},
+	{" contains ", model.FieldFilterTypeContains},
+	{" startsWith ", model.FieldFilterTypeStartsWith},
+	{" endsWith ", model.FieldFilterTypeEndsWith},
+	{"!=", model.FieldFilterTypeNotEquals},
+	{">=", model.FieldFilterTypeGreaterThanEq},
+	{"<=", model.FieldFilterTypeLessThanEq},
+	{">", model.FieldFilterTypeGreaterThan},
+	{"<", model.FieldFilterTypeLessThan},
+	{"=", model.FieldFilterTypeEquals},
+}
+
 func GetEntityFilterFromQuery(query url.Values) result.R[model.EntityFilter] {
 	filterQuery := query["filter"]
 
 	entityFilter := model.EntityFilter{}
 
 	for _, v := range filterQuery {
-		fieldFilterTypeResult := getFieldFilterTypeFromQuery(v)
+		fieldFilterResult := getFieldFilterFromQuery(v)
+
+		if fieldFilterResult.IsErr() {
+			return result.Err[model.EntityFilter](fieldFilterResult.UnwrapErr())
+		}
+
+		fieldFilter := fieldFilterResult.Unwrap().fieldFilter
+		fieldPathResult := getFilterFieldPath(fieldFilterResult.Unwrap().fieldName)
+
+		if fieldPathResult.IsErr() {
+			return result.Err[model.EntityFilter](fieldPathResult.UnwrapErr())
+		}
+
+		fieldName := fieldPathResult.Unwrap().fieldName
+		fieldFilter.Path = fieldPathResult.Unwrap().path
+
+		entityFilter[fieldName] = append(entityFilter[fieldName], fieldFilter)
+	}
+
+	return result.Ok(entityFilter)
+}
+
+type filterFieldPath struct {
+	fieldName model.FieldName
+	path      []string
+}
+
+// Anything after the first dot is a path to a value nested inside a json
+// field, e.g. meta.color or meta.tags.0
+func getFilterFieldPath(field string) result.R[filterFieldPath] {
+	segments := strings.Split(field, ".")
+
+	for _, segment := range segments {
+		if segment == "" {
+			return result.Errf[filterFieldPath]("invalid filter field: %s", field)
+		}
+	}
+
+	fieldNameResult := FieldNameDto(segments[0]).ToModel()
+
+	if fieldNameResult.IsErr() {
+		return result.Errf[filterFieldPath]("error parsing filter field name: %w", fieldNameResult.UnwrapErr())
+	}
+
+	return result.Ok(filterFieldPath{
+		fieldName: fieldNameResult.Unwrap(),
+		path:      segments[1:],
+	})
+}
+
+type parsedFieldFilter struct {
+	fieldName   string
+	fieldFilter model.FieldFilter
+}
 
-		if fieldFilterTypeResult.IsErr() {
-			return result.Err[model.EntityFilter](fieldFilterTypeResult.UnwrapErr())
+func getFieldFilterFromQuery(filterQuery string) result.R[parsedFieldFilter] {
+	operatorIndex := -1
+	var operator fieldFilterOperator
+
+	for _, o := range fieldFilterOperators {
+		index := strings.Index(filterQuery, o.token)
+
+		if index == -1 {
+			continue
+		}
+
+		if operatorIndex == -1 || index < operatorIndex {
+			operatorIndex = index
+			operator = o
 		}
+	}
+
+	if operatorIndex == -1 {
+		return result.Errf[parsedFieldFilter]("invalid filter type: %s", filterQuery)
+	}
 
-		fieldFilterType := fieldFilterTypeResult.Unwrap()
+	fieldName := filterQuery[:operatorIndex]
+	comparator := filterQuery[operatorIndex+len(operator.token):]
 
-		vals := strings.Split(v, fieldFilterType.String())
+	if fieldName == "" {
+		return result.Errf[parsedFieldFilter]("invalid filter: %s", filterQuery)
+	}
+
+	fieldFilter := model.FieldFilter{
+		Type: operator.filterType,
+	}
 
-		if len(vals) != 2 {
-			return result.Errf[model.EntityFilter](
-				"invalid filter: %s", v,
+	switch operator.filterType {
+	case model.FieldFilterTypeIsNull, model.FieldFilterTypeIsNotNull:
+		if comparator != "" {
+			return result.Errf[parsedFieldFilter]("invalid filter: %s", filterQuery)
+		}
+	case model.FieldFilterTypeIn, model.FieldFilterTypeContainsAny:
+		if !strings.HasPrefix(comparator, "(") || !strings.HasSuffix(comparator, ")") {
+			return result.Errf[parsedFieldFilter](
+				"invalid filter: %s, %s filter values must be wrapped in brackets",
+				filterQuery,
+				operator.filterType.String(),
 			)
 		}
 
-		fieldNameDto := FieldNameDto(vals[0])
-		fieldNameResult := fieldNameDto.ToModel()
+		values := comparator[1 : len(comparator)-1]
 
-		if fieldNameResult.IsErr() {
-			err := fieldNameResult.UnwrapErr()
-			return result.Errf[model.EntityFilter]("error parsing filter field name: %w", err)
+		if values == "" {
+			return result.Errf[parsedFieldFilter](
+				"invalid filter: %s, %s filter must have at least one value",
+				filterQuery,
+				operator.filterType.String(),
+			)
 		}
 
-		comparator := vals[1]
+		valuesResult := splitFieldFilterValues(values)
 
-		entityFilter[fieldNameResult.Unwrap()] = model.FieldFilter{
-			Type:       fieldFilterType,
-			Comparator: comparator,
+		if valuesResult.IsErr() {
+			return result.Errf[parsedFieldFilter]("invalid filter: %s, %w", filterQuery, valuesResult.UnwrapErr())
 		}
+
+		fieldFilter.Comparator = valuesResult.Unwrap()
+	default:
+		fieldFilter.Comparator = comparator
 	}
 
-	return result.Ok(entityFilter)
+	return result.Ok(parsedFieldFilter{
+		fieldName:   fieldName,
+		fieldFilter: fieldFilter,
+	})
 }
 
-func getFieldFilterTypeFromQuery(filterTypeQuery string) result.R[model.FieldFilterType] {
-	if strings.Contains(filterTypeQuery, ">=") {
-		return result.Ok(model.FieldFilterTypeGreaterThanEq)
+// Values are separated by commas. Commas and backslashes that are part of a
+// value are escaped with a backslash, e.g. (a\,b,c\\) holds the values "a,b"
+// and "c\". The POST query endpoint takes values as a json list instead
+func splitFieldFilterValues(values string) result.R[[]string] {
+	splitValues := []string{}
+	value := strings.Builder{}
+
+	for i := 0; i < len(values); i++ {
+		switch values[i] {
+		case ',':
+			splitValues = append(splitValues, value.String())
+			value.Reset()
+		case '\\':
+			if i+1 == len(values) || (values[i+1] != ',' && values[i+1] != '\\') {
+				return result.Errf[[]string]("backslashes must be followed by a comma or another backslash")
+			}
+
+			i++
+			value.WriteByte(values[i])
+		default:
+			value.WriteByte(values[i])
+		}
 	}
 
-	if strings.Contains(filterTypeQuery, "<=") {
+	return result.Ok(append(splitValues, value.String()))
+}
+
+type FieldFilterTypeDto string
+
+func (f FieldFilterTypeDto) ToModel() result.R[model.FieldFilterType] {
+	switch string(f) {
+	case "=":
+		return result.Ok(model.FieldFilterTypeEquals)
+	case "!=":
+		return result.Ok(model.FieldFilterTypeNotEquals)
+	case ">":
+		return result.Ok(model.FieldFilterTypeGreaterThan)
+	case ">=":
+		return result.Ok(model.FieldFilterTypeGreaterThanEq)
+	case "<":
+		return result.Ok(model.FieldFilterTypeLessThan)
+	case "<=":
 		return result.Ok(model.FieldFilterTypeLessThanEq)
+	case "in":
+		return result.Ok(model.FieldFilterTypeIn)
+	case "is null":
+		return result.Ok(model.FieldFilterTypeIsNull)
+	case "is not null":
+		return result.Ok(model.FieldFilterTypeIsNotNull)
+	case "contains":
+		return result.Ok(model.FieldFilterTypeContains)
+	case "startsWith":
+		return result.Ok(model.FieldFilterTypeStartsWith)
+	case "endsWith":
+		return result.Ok(model.FieldFilterTypeEndsWith)
+	case "containsAny":
+		return result.Ok(model.FieldFilterTypeContainsAny)
 	}
+	return result.Errf[model.FieldFilterType]("unrecognised filter operator: %s", string(f))
+}
 
-	if strings.Contains(filterTypeQuery, ">") {
-		return result.Ok(model.FieldFilterTypeGreaterThan)
+// Exactly one of And, Or, Not or Field should be set. Field nodes also take
+// an Op and, unless Op is a null check, a Value
+type EntityFilterExpressionDto struct {
+	And   *[]EntityFilterExpressionDto `json:"and,omitempty"`
+	Or    *[]EntityFilterExpressionDto `json:"or,omitempty"`
+	Not   *EntityFilterExpressionDto   `json:"not,omitempty"`
+	Field *FieldNameDto                `json:"field,omitempty"`
+	Op    FieldFilterTypeDto           `json:"op,omitempty"`
+	Value any                          `json:"value,omitempty"`
+}
+
+func (e *EntityFilterExpressionDto) UnmarshalJSON(data []byte) error {
+	type entityFilterExpressionDto EntityFilterExpressionDto
+
+	return unmarshalJsonPreservingNumbers(data, (*entityFilterExpressionDto)(e))
+}
+
+func (e EntityFilterExpressionDto) ToModel() result.R[model.EntityFilterExpression] {
+	nodeCount := 0
+
+	for _, present := range []bool{e.And != nil, e.Or != nil, e.Not != nil, e.Field != nil} {
+		if present {
+			nodeCount++
+		}
 	}
 
-	if strings.Contains(filterTypeQuery, "<") {
-		return result.Ok(model.FieldFilterTypeLessThan)
+	if nodeCount != 1 {
+		return result.Errf[model.EntityFilterExpression](
+			"filter expression must have exactly one of: and, or, not, field",
+		)
 	}
 
-	if strings.Contains(filterTypeQuery, "=") {
-		return result.Ok(model.FieldFilterTypeEquals)
+	switch {
+	case e.And != nil:
+		return getEntityFilterExpressionOperands(model.EntityFilterExpressionTypeAnd, *e.And)
+	case e.Or != nil:
+		return getEntityFilterExpressionOperands(model.EntityFilterExpressionTypeOr, *e.Or)
+	case e.Not != nil:
+		return getEntityFilterExpressionOperands(model.EntityFilterExpressionTypeNot, []EntityFilterExpressionDto{*e.Not})
+	}
+
+	fieldPathResult := getFilterFieldPath(string(*e.Field))
+
+	if fieldPathResult.IsErr() {
+		return result.Err[model.EntityFilterExpression](fieldPathResult.UnwrapErr())
+	}
+
+	fieldFilterTypeResult := e.Op.ToModel()
+
+	if fieldFilterTypeResult.IsErr() {
+		return result.Err[model.EntityFilterExpression](fieldFilterTypeResult.UnwrapErr())
+	}
+
+	fieldFilter := model.FieldFilter{
+		Type: fieldFilterTypeResult.Unwrap(),
+		Path: fieldPathResult.Unwrap().path,
+	}
+
+	switch fieldFilter.Type {
+	case model.FieldFilterTypeIsNull, model.FieldFilterTypeIsNotNull:
+		if e.Value != nil {
+			return result.Errf[model.EntityFilterExpression]("filter: \"%s\" does not take a value", e.Op)
+		}
+	case model.FieldFilterTypeIn, model.FieldFilterTypeContainsAny:
+		values, ok := e.Value.([]any)
+
+		if !ok || len(values) == 0 {
+			return result.Errf[model.EntityFilterExpression]("filter: \"%s\" value must be a non-empty array", e.Op)
+		}
+
+		comparators := []string{}
+
+		for _, value := range values {
+			comparatorResult := getFilterComparatorFromJson(value)
+
+			if comparatorResult.IsErr() {
+				return result.Err[model.EntityFilterExpression](comparatorResult.UnwrapErr())
+			}
+
+			comparators = append(comparators, comparatorResult.Unwrap())
+		}
+
+		fieldFilter.Comparator = comparators
+	default:
+		comparatorResult := getFilterComparatorFromJson(e.Value)
+
+		if comparatorResult.IsErr() {
+			return result.Err[model.EntityFilterExpression](comparatorResult.UnwrapErr())
+		}
+
+		fieldFilter.Comparator = comparatorResult.Unwrap()
+	}
+
+	return result.Ok(model.EntityFilterExpression{
+		Type:        model.EntityFilterExpressionTypeField,
+		FieldName:   fieldPathResult.Unwrap().fieldName,
+		FieldFilter: fieldFilter,
+	})
+}
+
+func GetEntityFilterExpressionDto(filterExpression model.EntityFilterExpression) EntityFilterExpressionDto {
+	operands := []EntityFilterExpressionDto{}
+
+	for _, operand := range filterExpression.Operands {
+		operands = append(operands, GetEntityFilterExpressionDto(operand))
+	}
+
+	switch filterExpression.Type {
+	case model.EntityFilterExpressionTypeAnd:
+		return EntityFilterExpressionDto{And: &operands}
+	case model.EntityFilterExpressionTypeOr:
+		return EntityFilterExpressionDto{Or: &operands}
+	case model.EntityFilterExpressionTypeNot:
+		return EntityFilterExpressionDto{Not: &operands[0]}
+	}
+
+	fieldFilter := filterExpression.FieldFilter
+	segments := append([]string{filterExpression.FieldName.String()}, fieldFilter.Path...)
+
+	if fieldFilter.Length {
+		segments = append(segments, "length")
+	}
+
+	field := FieldNameDto(strings.Join(segments, "."))
+
+	return EntityFilterExpressionDto{
+		Field: &field,
+		Op:    FieldFilterTypeDto(fieldFilter.Type.String()),
+		Value: fieldFilter.Comparator,
+	}
+}
+
+func getEntityFilterExpressionOperands(
+	expressionType model.EntityFilterExpressionType,
+	operandDtos []EntityFilterExpressionDto,
+) result.R[model.EntityFilterExpression] {
+	operands := []model.EntityFilterExpression{}
+
+	for _, operandDto := range operandDtos {
+		operandResult := operandDto.ToModel()
+
+		if operandResult.IsErr() {
+			return operandResult
+		}
+
+		operands = append(operands, operandResult.Unwrap())
 	}
 
-	return result.Errf[model.FieldFilterType]("invalid filter type: %s", filterTypeQuery)
+	return result.Ok(model.EntityFilterExpression{
+		Type:     expressionType,
+		Operands: operands,
+	})
+}
+
+// Filter values are validated against the table schema from their string
+// form, the same as query string filters, so JSON scalars are normalised here
+func getFilterComparatorFromJson(value any) result.R[string] {
+	switch v := value.(type) {
+	case string:
+		return result.Ok(v)
+	case json.Number:
+		return result.Ok(v.String())
+	case float64:
+		return result.Ok(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		return result.Ok(strconv.FormatBool(v))
+	case map[string]any, []any:
+		// Objects and arrays are only valid for json containment
+		bytes, err := json.Marshal(v)
+
+		if err != nil {
+			return result.Errf[string]("invalid filter value: %v", value)
+		}
+
+		return result.Ok(string(bytes))
+	}
+	return result.Errf[string]("invalid filter value: %v", value)
 }
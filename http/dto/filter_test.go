@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"crudly/model"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestGetEntityFilterFromQuery(t *testing.T) {
+	tests := []struct {
+		name           string
+		filters        []string
+		expectedFilter model.EntityFilter
+		expectErr      bool
+	}{
+		{
+			name:    "comparison",
+			filters: []string{"age>=18", "age<65"},
+			expectedFilter: model.EntityFilter{
+				"age": {
+					{Type: model.FieldFilterTypeGreaterThanEq, Path: []string{}, Comparator: "18"},
+					{Type: model.FieldFilterTypeLessThan, Path: []string{}, Comparator: "65"},
+				},
+			},
+		},
+		{
+			name:    "null check",
+			filters: []string{"name is not null"},
+			expectedFilter: model.EntityFilter{
+				"name": {{Type: model.FieldFilterTypeIsNotNull, Path: []string{}}},
+			},
+		},
+		{
+			name:    "in",
+			filters: []string{"name in (a,b,c)"},
+			expectedFilter: model.EntityFilter{
+				"name": {{Type: model.FieldFilterTypeIn, Path: []string{}, Comparator: []string{"a", "b", "c"}}},
+			},
+		},
+		{
+			name:    "in with escaped commas and backslashes",
+			filters: []string{`name in (a\,b,c\\,\\\,d,)`},
+			expectedFilter: model.EntityFilter{
+				"name": {{Type: model.FieldFilterTypeIn, Path: []string{}, Comparator: []string{"a,b", `c\`, `\,d`, ""}}},
+			},
+		},
+		{
+			name:    "containsAny with escaped comma",
+			filters: []string{`tags containsAny (x\,y)`},
+			expectedFilter: model.EntityFilter{
+				"tags": {{Type: model.FieldFilterTypeContainsAny, Path: []string{}, Comparator: []string{"x,y"}}},
+			},
+		},
+		{
+			name:    "json path",
+			filters: []string{"meta.color=red"},
+			expectedFilter: model.EntityFilter{
+				"meta": {{Type: model.FieldFilterTypeEquals, Path: []string{"color"}, Comparator: "red"}},
+			},
+		},
+		{
+			name:      "in with trailing backslash",
+			filters:   []string{`name in (a\)`},
+			expectErr: true,
+		},
+		{
+			name:      "in with unknown escape",
+			filters:   []string{`name in (a\b)`},
+			expectErr: true,
+		},
+		{
+			name:      "in without brackets",
+			filters:   []string{"name in a,b"},
+			expectErr: true,
+		},
+		{
+			name:      "in without values",
+			filters:   []string{"name in ()"},
+			expectErr: true,
+		},
+		{
+			name:      "no operator",
+			filters:   []string{"name"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entityFilterResult := GetEntityFilterFromQuery(url.Values{"filter": test.filters})
+
+			if test.expectErr {
+				if entityFilterResult.IsOk() {
+					t.Fatalf("expected an error, got: %+v", entityFilterResult.Unwrap())
+				}
+				return
+			}
+
+			if entityFilterResult.IsErr() {
+				t.Fatalf("unexpected error: %s", entityFilterResult.UnwrapErr())
+			}
+
+			if !reflect.DeepEqual(entityFilterResult.Unwrap(), test.expectedFilter) {
+				t.Errorf("unexpected filter:\n got: %#v\nwant: %#v", entityFilterResult.Unwrap(), test.expectedFilter)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Decodes numbers as json.Number rather than float64 so that their exact
+// text reaches validation, where the field type decides how they're parsed.
+// Dtos that call this from their own UnmarshalJSON pass v as a local alias of
+// their type, which has none of its methods, so that decoding doesn't recurse
+func unmarshalJsonPreservingNumbers(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(v)
+}
@@ -41,3 +41,36 @@ func GetEntityOrderFromQuery(query url.Values) result.R[model.EntityOrders] {
 
 	return result.Ok(entityOrders)
 }
+
+type EntityOrderDto struct {
+	Field     FieldNameDto `json:"field"`
+	Direction string       `json:"direction,omitempty"`
+}
+
+func (e EntityOrderDto) ToModel() result.R[model.EntityOrder] {
+	fieldNameResult := e.Field.ToModel()
+
+	if fieldNameResult.IsErr() {
+		return result.Errf[model.EntityOrder]("error parsing order field name: %w", fieldNameResult.UnwrapErr())
+	}
+
+	fieldOrderType := model.FieldOrderTypeAscending
+
+	switch e.Direction {
+	case "", "asc":
+		fieldOrderType = model.FieldOrderTypeAscending
+	case "desc":
+		fieldOrderType = model.FieldOrderTypeDescending
+	default:
+		return result.Errf[model.EntityOrder](
+			"invalid order type for field \"%s\": \"%s\"",
+			fieldNameResult.Unwrap().String(),
+			e.Direction,
+		)
+	}
+
+	return result.Ok(model.EntityOrder{
+		Type:      fieldOrderType,
+		FieldName: fieldNameResult.Unwrap(),
+	})
+}
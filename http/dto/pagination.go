@@ -2,8 +2,12 @@ package dto
 
 import (
 	"crudly/model"
+	"crudly/util/optional"
 	"crudly/util/result"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"strconv"
 )
 
@@ -38,3 +42,130 @@ func (p PaginationOffsetPathParam) ToModel() result.R[model.PaginationOffset] {
 
 	return result.Ok(model.PaginationOffset(uint(offset)))
 }
+
+// Reads the limit, offset and cursor query params, falling back to the
+// default limit and offset
+func GetPaginationParamsFromQuery(query url.Values) result.R[model.PaginationParams] {
+	paginationParams := model.PaginationParams{
+		Limit:  model.DefaultPaginationLimit,
+		Offset: model.DefaultPaginationOffset,
+	}
+
+	paginationLimitPathParam := PaginationLimitPathParam(query.Get("limit"))
+
+	if paginationLimitPathParam != "" {
+		limitResult := paginationLimitPathParam.ToModel()
+
+		if limitResult.IsErr() {
+			return result.Errf[model.PaginationParams]("invalid limit query param: %w", limitResult.UnwrapErr())
+		}
+
+		paginationParams.Limit = limitResult.Unwrap()
+	}
+
+	paginationOffsetPathParam := PaginationOffsetPathParam(query.Get("offset"))
+
+	if paginationOffsetPathParam != "" {
+		offsetResult := paginationOffsetPathParam.ToModel()
+
+		if offsetResult.IsErr() {
+			return result.Errf[model.PaginationParams]("invalid offset query param: %w", offsetResult.UnwrapErr())
+		}
+
+		paginationParams.Offset = offsetResult.Unwrap()
+	}
+
+	entityCursorDto := EntityCursorDto(query.Get("cursor"))
+
+	if entityCursorDto != "" {
+		cursorResult := entityCursorDto.ToModel()
+
+		if cursorResult.IsErr() {
+			return result.Errf[model.PaginationParams]("invalid cursor query param: %w", cursorResult.UnwrapErr())
+		}
+
+		paginationParams.Cursor = optional.Some(cursorResult.Unwrap())
+	}
+
+	return result.Ok(paginationParams)
+}
+
+type entityCursorKeyDto struct {
+	Field     FieldNameDto `json:"f"`
+	Direction string       `json:"d"`
+	Value     FieldDto     `json:"v"`
+}
+
+// An opaque, url safe encoding of an entity cursor
+type EntityCursorDto string
+
+func (e EntityCursorDto) ToModel() result.R[model.EntityCursor] {
+	jsonBytes, err := base64.RawURLEncoding.DecodeString(string(e))
+
+	if err != nil {
+		return result.Errf[model.EntityCursor]("cursor is not valid")
+	}
+
+	keyDtos := []entityCursorKeyDto{}
+
+	err = unmarshalJsonPreservingNumbers(jsonBytes, &keyDtos)
+
+	if err != nil {
+		return result.Errf[model.EntityCursor]("cursor is not valid")
+	}
+
+	entityCursor := model.EntityCursor{}
+
+	for _, keyDto := range keyDtos {
+		fieldNameResult := keyDto.Field.ToModel()
+
+		if fieldNameResult.IsErr() {
+			return result.Errf[model.EntityCursor]("cursor is not valid")
+		}
+
+		fieldOrderType := model.FieldOrderTypeAscending
+
+		switch keyDto.Direction {
+		case "asc":
+			fieldOrderType = model.FieldOrderTypeAscending
+		case "desc":
+			fieldOrderType = model.FieldOrderTypeDescending
+		default:
+			return result.Errf[model.EntityCursor]("cursor is not valid")
+		}
+
+		entityCursor = append(entityCursor, model.EntityCursorKey{
+			FieldName: fieldNameResult.Unwrap(),
+			Type:      fieldOrderType,
+			Value:     keyDto.Value,
+		})
+	}
+
+	return result.Ok(entityCursor)
+}
+
+func GetEntityCursorDto(entityCursor model.EntityCursor) EntityCursorDto {
+	keyDtos := []entityCursorKeyDto{}
+
+	for _, key := range entityCursor {
+		direction := "asc"
+
+		if key.Type == model.FieldOrderTypeDescending {
+			direction = "desc"
+		}
+
+		keyDtos = append(keyDtos, entityCursorKeyDto{
+			Field:     GetFieldNameDto(key.FieldName),
+			Direction: direction,
+			Value:     GetFieldDto(key.Value),
+		})
+	}
+
+	jsonBytes, err := json.Marshal(keyDtos)
+
+	if err != nil {
+		panic("error marshalling entity cursor json")
+	}
+
+	return EntityCursorDto(base64.RawURLEncoding.EncodeToString(jsonBytes))
+}
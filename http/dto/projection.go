@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"net/url"
+	"strings"
+)
+
+type FieldProjectionDto []FieldNameDto
+
+func (f FieldProjectionDto) ToModel() result.R[model.FieldProjection] {
+	fieldProjection := model.FieldProjection{}
+
+	for _, fieldNameDto := range f {
+		if fieldNameDto == "" {
+			return result.Errf[model.FieldProjection]("field projection contains an empty field name")
+		}
+
+		fieldNameResult := fieldNameDto.ToModel()
+
+		if fieldNameResult.IsErr() {
+			return result.Errf[model.FieldProjection]("error parsing field name: %w", fieldNameResult.UnwrapErr())
+		}
+
+		fieldProjection = append(fieldProjection, fieldNameResult.Unwrap())
+	}
+
+	return result.Ok(fieldProjection)
+}
+
+// Reads comma separated field names from any fields query params, returning
+// None when none are present so that every field is returned
+func GetFieldProjectionFromQuery(query url.Values) result.R[optional.O[model.FieldProjection]] {
+	fieldsQueries, ok := query["fields"]
+
+	if !ok {
+		return result.Ok(optional.None[model.FieldProjection]())
+	}
+
+	fieldProjectionDto := FieldProjectionDto{}
+
+	for _, fieldsQuery := range fieldsQueries {
+		for _, fieldName := range strings.Split(fieldsQuery, ",") {
+			fieldProjectionDto = append(fieldProjectionDto, FieldNameDto(fieldName))
+		}
+	}
+
+	fieldProjectionResult := fieldProjectionDto.ToModel()
+
+	if fieldProjectionResult.IsErr() {
+		return result.Err[optional.O[model.FieldProjection]](fieldProjectionResult.UnwrapErr())
+	}
+
+	return result.Ok(optional.Some(fieldProjectionResult.Unwrap()))
+}
@@ -2,8 +2,10 @@ package dto
 
 import (
 	"crudly/model"
+	"crudly/util"
 	"crudly/util/optional"
 	"crudly/util/result"
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -24,6 +26,20 @@ func (t FieldTypeDto) ToModel() result.R[model.FieldType] {
 		return result.Ok(model.FieldTypeTime)
 	case "enum":
 		return result.Ok(model.FieldTypeEnum)
+	case "float":
+		return result.Ok(model.FieldTypeFloat)
+	case "decimal":
+		return result.Ok(model.FieldTypeDecimal)
+	case "bigint":
+		return result.Ok(model.FieldTypeBigInt)
+	case "json":
+		return result.Ok(model.FieldTypeJson)
+	case "date":
+		return result.Ok(model.FieldTypeDate)
+	case "duration":
+		return result.Ok(model.FieldTypeDuration)
+	case "reference":
+		return result.Ok(model.FieldTypeReference)
 	}
 	return result.Err[model.FieldType](fmt.Errorf("unrecognised field type: %s", string(t)))
 }
@@ -42,38 +58,260 @@ func GetFieldTypeDto(fieldType model.FieldType) FieldTypeDto {
 		return FieldTypeDto("time")
 	case model.FieldTypeEnum:
 		return FieldTypeDto("enum")
+	case model.FieldTypeFloat:
+		return FieldTypeDto("float")
+	case model.FieldTypeDecimal:
+		return FieldTypeDto("decimal")
+	case model.FieldTypeBigInt:
+		return FieldTypeDto("bigint")
+	case model.FieldTypeJson:
+		return FieldTypeDto("json")
+	case model.FieldTypeDate:
+		return FieldTypeDto("date")
+	case model.FieldTypeDuration:
+		return FieldTypeDto("duration")
+	case model.FieldTypeReference:
+		return FieldTypeDto("reference")
 	}
 	panic(fmt.Sprintf("invalid field type has entered the system: %+v", fieldType))
 }
 
 type FieldDefinitionDto struct {
-	Type       FieldTypeDto `json:"type"`
-	Values     *[]string    `json:"values,omitempty"`
-	IsOptional bool         `json:"isOptional"`
+	Type       FieldTypeDto          `json:"type"`
+	Values     *[]string             `json:"values,omitempty"`
+	IsOptional bool                  `json:"isOptional"`
+	Unique     bool                  `json:"unique"`
+	Precision  *uint                 `json:"precision,omitempty"`
+	Scale      *uint                 `json:"scale,omitempty"`
+	MinLength  *uint                 `json:"minLength,omitempty"`
+	MaxLength  *uint                 `json:"maxLength,omitempty"`
+	Pattern    *string               `json:"pattern,omitempty"`
+	Format     *StringFormatDto      `json:"format,omitempty"`
+	Min        *json.Number          `json:"min,omitempty"`
+	Max        *json.Number          `json:"max,omitempty"`
+	NotBefore  *string               `json:"notBefore,omitempty"`
+	NotAfter   *string               `json:"notAfter,omitempty"`
+	References *TableNameDto         `json:"references,omitempty"`
+	OnDelete   *ReferenceOnDeleteDto `json:"onDelete,omitempty"`
 }
 
+// Array types are written as their element type followed by [], e.g.
+// string[]
+const arrayTypeSuffix = "[]"
+
 func (d FieldDefinitionDto) ToModel() result.R[model.FieldDefinition] {
+	fieldDefinition := model.FieldDefinition{
+		Values:     optional.FromPointer(d.Values),
+		IsOptional: d.IsOptional,
+		Unique:     d.Unique,
+		Precision:  optional.FromPointer(d.Precision),
+		Scale:      optional.FromPointer(d.Scale),
+		MinLength:  optional.FromPointer(d.MinLength),
+		MaxLength:  optional.FromPointer(d.MaxLength),
+		Pattern:    optional.FromPointer(d.Pattern),
+	}
+
+	if d.Format != nil {
+		stringFormatResult := d.Format.ToModel()
+
+		if stringFormatResult.IsErr() {
+			return result.Err[model.FieldDefinition](fmt.Errorf("error parsing string format: %w", stringFormatResult.UnwrapErr()))
+		}
+
+		fieldDefinition.Format = optional.Some(stringFormatResult.Unwrap())
+	}
+
+	if d.References != nil {
+		referencesResult := d.References.ToModel()
+
+		if referencesResult.IsErr() {
+			return result.Err[model.FieldDefinition](fmt.Errorf("error parsing referenced table: %w", referencesResult.UnwrapErr()))
+		}
+
+		fieldDefinition.References = optional.Some(referencesResult.Unwrap())
+	}
+
+	if d.OnDelete != nil {
+		onDeleteResult := d.OnDelete.ToModel()
+
+		if onDeleteResult.IsErr() {
+			return result.Err[model.FieldDefinition](fmt.Errorf("error parsing onDelete: %w", onDeleteResult.UnwrapErr()))
+		}
+
+		fieldDefinition.OnDelete = optional.Some(onDeleteResult.Unwrap())
+	}
+
+	if d.Min != nil {
+		fieldDefinition.Min = optional.Some(model.Decimal(d.Min.String()))
+	}
+
+	if d.Max != nil {
+		fieldDefinition.Max = optional.Some(model.Decimal(d.Max.String()))
+	}
+
+	if d.NotBefore != nil {
+		notBeforeResult := util.ValidateIncomingTime(*d.NotBefore)
+
+		if notBeforeResult.IsErr() {
+			return result.Err[model.FieldDefinition](fmt.Errorf("error parsing notBefore: %w", notBeforeResult.UnwrapErr()))
+		}
+
+		fieldDefinition.NotBefore = optional.Some(notBeforeResult.Unwrap())
+	}
+
+	if d.NotAfter != nil {
+		notAfterResult := util.ValidateIncomingTime(*d.NotAfter)
+
+		if notAfterResult.IsErr() {
+			return result.Err[model.FieldDefinition](fmt.Errorf("error parsing notAfter: %w", notAfterResult.UnwrapErr()))
+		}
+
+		fieldDefinition.NotAfter = optional.Some(notAfterResult.Unwrap())
+	}
+
+	if strings.HasSuffix(string(d.Type), arrayTypeSuffix) {
+		elementTypeDto := FieldTypeDto(strings.TrimSuffix(string(d.Type), arrayTypeSuffix))
+		elementTypeResult := elementTypeDto.ToModel()
+
+		if elementTypeResult.IsErr() {
+			return result.Err[model.FieldDefinition](fmt.Errorf("error parsing array element type: %w", elementTypeResult.UnwrapErr()))
+		}
+
+		fieldDefinition.Type = model.FieldTypeArray
+		fieldDefinition.ElementType = optional.Some(elementTypeResult.Unwrap())
+
+		return result.Ok(fieldDefinition)
+	}
+
 	fieldTypeResult := d.Type.ToModel()
 
 	if fieldTypeResult.IsErr() {
 		return result.Err[model.FieldDefinition](fmt.Errorf("error parsing field type: %w", fieldTypeResult.UnwrapErr()))
 	}
 
-	fieldType := fieldTypeResult.Unwrap()
+	fieldDefinition.Type = fieldTypeResult.Unwrap()
 
-	return result.Ok(model.FieldDefinition{
-		Type:       fieldType,
-		Values:     optional.FromPointer(d.Values),
-		IsOptional: d.IsOptional,
-	})
+	return result.Ok(fieldDefinition)
 }
 
 func GetFieldDefinitionDto(d model.FieldDefinition) FieldDefinitionDto {
+	var fieldTypeDto FieldTypeDto
+
+	if d.Type == model.FieldTypeArray {
+		fieldTypeDto = GetFieldTypeDto(d.ElementType.Unwrap()) + arrayTypeSuffix
+	} else {
+		fieldTypeDto = GetFieldTypeDto(d.Type)
+	}
+
+	var stringFormatDto *StringFormatDto
+
+	if d.Format.IsSome() {
+		format := GetStringFormatDto(d.Format.Unwrap())
+		stringFormatDto = &format
+	}
+
+	var min *json.Number
+
+	if d.Min.IsSome() {
+		minNumber := json.Number(d.Min.Unwrap().String())
+		min = &minNumber
+	}
+
+	var max *json.Number
+
+	if d.Max.IsSome() {
+		maxNumber := json.Number(d.Max.Unwrap().String())
+		max = &maxNumber
+	}
+
+	var notBefore *string
+
+	if d.NotBefore.IsSome() {
+		notBeforeString := d.NotBefore.Unwrap().UTC().Format(TimeFormat)
+		notBefore = &notBeforeString
+	}
+
+	var notAfter *string
+
+	if d.NotAfter.IsSome() {
+		notAfterString := d.NotAfter.Unwrap().UTC().Format(TimeFormat)
+		notAfter = &notAfterString
+	}
+
+	var references *TableNameDto
+
+	if d.References.IsSome() {
+		referencesDto := GetTableNameDto(d.References.Unwrap())
+		references = &referencesDto
+	}
+
+	var onDelete *ReferenceOnDeleteDto
+
+	if d.OnDelete.IsSome() {
+		onDeleteDto := GetReferenceOnDeleteDto(d.OnDelete.Unwrap())
+		onDelete = &onDeleteDto
+	}
+
 	return FieldDefinitionDto{
-		Type:       GetFieldTypeDto(d.Type),
+		Type:       fieldTypeDto,
 		Values:     d.Values.ToPointer(),
 		IsOptional: d.IsOptional,
+		Unique:     d.Unique,
+		Precision:  d.Precision.ToPointer(),
+		Scale:      d.Scale.ToPointer(),
+		MinLength:  d.MinLength.ToPointer(),
+		MaxLength:  d.MaxLength.ToPointer(),
+		Pattern:    d.Pattern.ToPointer(),
+		Format:     stringFormatDto,
+		Min:        min,
+		Max:        max,
+		NotBefore:  notBefore,
+		NotAfter:   notAfter,
+		References: references,
+		OnDelete:   onDelete,
+	}
+}
+
+type StringFormatDto string
+
+func (s StringFormatDto) ToModel() result.R[model.StringFormat] {
+	switch string(s) {
+	case "email":
+		return result.Ok(model.StringFormatEmail)
+	case "url":
+		return result.Ok(model.StringFormatUrl)
+	case "uuid":
+		return result.Ok(model.StringFormatUuid)
+	case "hostname":
+		return result.Ok(model.StringFormatHostname)
+	case "ipv4":
+		return result.Ok(model.StringFormatIpv4)
+	case "ipv6":
+		return result.Ok(model.StringFormatIpv6)
+	}
+	return result.Errf[model.StringFormat]("unrecognised string format: %s", string(s))
+}
+
+func GetStringFormatDto(stringFormat model.StringFormat) StringFormatDto {
+	return StringFormatDto(stringFormat.String())
+}
+
+type ReferenceOnDeleteDto string
+
+func (r ReferenceOnDeleteDto) ToModel() result.R[model.ReferenceOnDelete] {
+	switch string(r) {
+	case "restrict":
+		return result.Ok(model.ReferenceOnDeleteRestrict)
+	case "cascade":
+		return result.Ok(model.ReferenceOnDeleteCascade)
+	case "setNull":
+		return result.Ok(model.ReferenceOnDeleteSetNull)
 	}
+	return result.Errf[model.ReferenceOnDelete]("unrecognised onDelete: %s", string(r))
+}
+
+func GetReferenceOnDeleteDto(referenceOnDelete model.ReferenceOnDelete) ReferenceOnDeleteDto {
+	return ReferenceOnDeleteDto(referenceOnDelete.String())
 }
 
 type FieldNameDto string
@@ -148,12 +386,78 @@ func GetTableSchemasDto(tableSchemas model.TableSchemas) TableSchemasDto {
 	return result
 }
 
+// Tables are created from either a bare schema, or to declare indexes or a
+// link table along with it, from
+// {"version": 2, "schema": {...}, "indexes": {...}, "link": true}. Field
+// definitions are always objects, so a numeric version can't be mistaken for
+// a field that happens to be called version
+type TableCreationRequestDto struct {
+	Version uint            `json:"version"`
+	Schema  TableSchemaDto  `json:"schema"`
+	Indexes TableIndexesDto `json:"indexes"`
+	Link    bool            `json:"link"`
+}
+
+const tableCreationRequestVersion = 2
+
+func (t *TableCreationRequestDto) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+
+	err := json.Unmarshal(data, &fields)
+
+	if err != nil {
+		return err
+	}
+
+	var version any
+
+	unmarshalJsonPreservingNumbers(fields["version"], &version)
+
+	if _, ok := version.(json.Number); !ok {
+		return unmarshalJsonPreservingNumbers(data, &t.Schema)
+	}
+
+	if version != json.Number(fmt.Sprint(tableCreationRequestVersion)) {
+		return fmt.Errorf("unsupported table creation request version: %s", version)
+	}
+
+	type tableCreationRequestDto TableCreationRequestDto
+
+	return unmarshalJsonPreservingNumbers(data, (*tableCreationRequestDto)(t))
+}
+
+func (t TableCreationRequestDto) ToModel() result.R[model.TableCreationRequest] {
+	schemaResult := t.Schema.ToModel()
+
+	if schemaResult.IsErr() {
+		return result.Errf[model.TableCreationRequest]("error parsing table schema: %w", schemaResult.UnwrapErr())
+	}
+
+	indexesResult := t.Indexes.ToModel()
+
+	if indexesResult.IsErr() {
+		return result.Errf[model.TableCreationRequest]("error parsing table indexes: %w", indexesResult.UnwrapErr())
+	}
+
+	return result.Ok(model.TableCreationRequest{
+		Schema:  schemaResult.Unwrap(),
+		Indexes: indexesResult.Unwrap(),
+		Link:    t.Link,
+	})
+}
+
 type FieldCreationRequestDto struct {
 	Name         FieldNameDto       `json:"name"`
 	Definition   FieldDefinitionDto `json:"schema"`
 	DefaultValue *any               `json:"defaultValue,omitempty"`
 }
 
+func (f *FieldCreationRequestDto) UnmarshalJSON(data []byte) error {
+	type fieldCreationRequestDto FieldCreationRequestDto
+
+	return unmarshalJsonPreservingNumbers(data, (*fieldCreationRequestDto)(f))
+}
+
 func (f FieldCreationRequestDto) ToModel() result.R[model.FieldCreationRequest] {
 	nameResult := f.Name.ToModel()
 
@@ -189,3 +493,280 @@ func (f FieldDeletionRequestDto) ToModel() result.R[model.FieldDeletionRequest]
 		Name: nameResult.Unwrap(),
 	})
 }
+
+type FieldAlterationRequestDto struct {
+	Name          FieldNameDto       `json:"name"`
+	Definition    FieldDefinitionDto `json:"schema"`
+	BackfillValue *any               `json:"backfillValue,omitempty"`
+	DryRun        bool               `json:"dryRun"`
+}
+
+func (f *FieldAlterationRequestDto) UnmarshalJSON(data []byte) error {
+	type fieldAlterationRequestDto FieldAlterationRequestDto
+
+	return unmarshalJsonPreservingNumbers(data, (*fieldAlterationRequestDto)(f))
+}
+
+func (f FieldAlterationRequestDto) ToModel() result.R[model.FieldAlterationRequest] {
+	nameResult := f.Name.ToModel()
+
+	if nameResult.IsErr() {
+		return result.Errf[model.FieldAlterationRequest]("error parsing field name: %w", nameResult.UnwrapErr())
+	}
+
+	definitionResult := f.Definition.ToModel()
+
+	if definitionResult.IsErr() {
+		return result.Errf[model.FieldAlterationRequest]("error parsing field definition: %w", definitionResult.UnwrapErr())
+	}
+
+	return result.Ok(model.FieldAlterationRequest{
+		Name:          nameResult.Unwrap(),
+		Definition:    definitionResult.Unwrap(),
+		BackfillValue: optional.FromPointer(f.BackfillValue),
+		DryRun:        f.DryRun,
+	})
+}
+
+type FieldConversionFailureDto struct {
+	Id    string `json:"id"`
+	Error string `json:"error"`
+}
+
+type FieldAlterationDto struct {
+	DryRun      bool                        `json:"dryRun"`
+	Applied     bool                        `json:"applied"`
+	EntityCount uint                        `json:"entityCount"`
+	FailedCount uint                        `json:"failedCount"`
+	Failures    []FieldConversionFailureDto `json:"failures"`
+}
+
+func GetFieldAlterationDto(fieldAlteration model.FieldAlteration) FieldAlterationDto {
+	failures := []FieldConversionFailureDto{}
+
+	for _, failure := range fieldAlteration.Failures {
+		failures = append(failures, FieldConversionFailureDto{
+			Id:    failure.Id.String(),
+			Error: failure.Error,
+		})
+	}
+
+	return FieldAlterationDto{
+		DryRun:      fieldAlteration.DryRun,
+		Applied:     fieldAlteration.Applied,
+		EntityCount: fieldAlteration.EntityCount,
+		FailedCount: fieldAlteration.FailedCount,
+		Failures:    failures,
+	}
+}
+
+type FieldRenameRequestDto struct {
+	Name    FieldNameDto `json:"name"`
+	NewName FieldNameDto `json:"newName"`
+}
+
+func (f FieldRenameRequestDto) ToModel() result.R[model.FieldRenameRequest] {
+	if f.NewName == "" {
+		return result.Errf[model.FieldRenameRequest]("newName is required")
+	}
+
+	nameResult := f.Name.ToModel()
+
+	if nameResult.IsErr() {
+		return result.Errf[model.FieldRenameRequest]("error parsing field name: %w", nameResult.UnwrapErr())
+	}
+
+	newNameResult := f.NewName.ToModel()
+
+	if newNameResult.IsErr() {
+		return result.Errf[model.FieldRenameRequest]("error parsing new field name: %w", newNameResult.UnwrapErr())
+	}
+
+	return result.Ok(model.FieldRenameRequest{
+		Name:    nameResult.Unwrap(),
+		NewName: newNameResult.Unwrap(),
+	})
+}
+
+type TableRenameRequestDto struct {
+	NewName TableNameDto `json:"newName"`
+}
+
+func (t TableRenameRequestDto) ToModel() result.R[model.TableRenameRequest] {
+	if t.NewName == "" {
+		return result.Errf[model.TableRenameRequest]("newName is required")
+	}
+
+	newNameResult := t.NewName.ToModel()
+
+	if newNameResult.IsErr() {
+		return result.Errf[model.TableRenameRequest]("error parsing new table name: %w", newNameResult.UnwrapErr())
+	}
+
+	return result.Ok(model.TableRenameRequest{
+		NewName: newNameResult.Unwrap(),
+	})
+}
+
+type TimeFieldMigrationResponseDto struct {
+	MigratedFields []FieldNameDto `json:"migratedFields"`
+}
+
+func GetTimeFieldMigrationResponseDto(fieldNames []model.FieldName) TimeFieldMigrationResponseDto {
+	migratedFields := []FieldNameDto{}
+
+	for _, fieldName := range fieldNames {
+		migratedFields = append(migratedFields, GetFieldNameDto(fieldName))
+	}
+
+	return TimeFieldMigrationResponseDto{
+		MigratedFields: migratedFields,
+	}
+}
+
+type IndexNameDto string
+
+func (i IndexNameDto) ToModel() result.R[model.IndexName] {
+	return result.Ok(model.IndexName(string(i)))
+}
+
+func GetIndexNameDto(i model.IndexName) IndexNameDto {
+	return IndexNameDto(string(i))
+}
+
+// Link is only ever output, for the link index of a link table
+type TableIndexDto struct {
+	Fields []FieldNameDto             `json:"fields"`
+	Unique bool                       `json:"unique"`
+	Filter *EntityFilterExpressionDto `json:"filter,omitempty"`
+	Link   bool                       `json:"link,omitempty"`
+}
+
+func (t TableIndexDto) ToModel() result.R[model.TableIndex] {
+	if t.Link {
+		return result.Errf[model.TableIndex]("link indexes can only be made by creating a link table")
+	}
+
+	index := model.TableIndex{
+		Fields: []model.FieldName{},
+		Unique: t.Unique,
+	}
+
+	for _, fieldNameDto := range t.Fields {
+		fieldNameResult := fieldNameDto.ToModel()
+
+		if fieldNameResult.IsErr() {
+			return result.Errf[model.TableIndex]("error parsing field name: %w", fieldNameResult.UnwrapErr())
+		}
+
+		index.Fields = append(index.Fields, fieldNameResult.Unwrap())
+	}
+
+	if t.Filter != nil {
+		filterResult := t.Filter.ToModel()
+
+		if filterResult.IsErr() {
+			return result.Errf[model.TableIndex]("error parsing filter: %w", filterResult.UnwrapErr())
+		}
+
+		index.Filter = optional.Some(filterResult.Unwrap())
+	}
+
+	return result.Ok(index)
+}
+
+func GetTableIndexDto(index model.TableIndex) TableIndexDto {
+	fields := []FieldNameDto{}
+
+	for _, fieldName := range index.Fields {
+		fields = append(fields, GetFieldNameDto(fieldName))
+	}
+
+	var filter *EntityFilterExpressionDto
+
+	if index.Filter.IsSome() {
+		filterDto := GetEntityFilterExpressionDto(index.Filter.Unwrap())
+		filter = &filterDto
+	}
+
+	return TableIndexDto{
+		Fields: fields,
+		Unique: index.Unique,
+		Filter: filter,
+		Link:   index.Link,
+	}
+}
+
+type TableIndexesDto map[IndexNameDto]TableIndexDto
+
+func (t TableIndexesDto) ToModel() result.R[model.TableIndexes] {
+	indexes := model.TableIndexes{}
+
+	for k, v := range t {
+		indexNameResult := k.ToModel()
+
+		if indexNameResult.IsErr() {
+			return result.Errf[model.TableIndexes]("error with index name: %w", indexNameResult.UnwrapErr())
+		}
+
+		indexResult := v.ToModel()
+
+		if indexResult.IsErr() {
+			return result.Errf[model.TableIndexes]("error with index \"%s\": %w", k, indexResult.UnwrapErr())
+		}
+
+		indexes[indexNameResult.Unwrap()] = indexResult.Unwrap()
+	}
+
+	return result.Ok(indexes)
+}
+
+func GetTableIndexesDto(indexes model.TableIndexes) TableIndexesDto {
+	result := TableIndexesDto{}
+
+	for k, v := range indexes {
+		result[GetIndexNameDto(k)] = GetTableIndexDto(v)
+	}
+
+	return result
+}
+
+type IndexCreationRequestDto struct {
+	Name IndexNameDto `json:"name"`
+	TableIndexDto
+}
+
+func (i IndexCreationRequestDto) ToModel() result.R[model.IndexCreationRequest] {
+	nameResult := i.Name.ToModel()
+
+	if nameResult.IsErr() {
+		return result.Errf[model.IndexCreationRequest]("error parsing index name: %w", nameResult.UnwrapErr())
+	}
+
+	indexResult := i.TableIndexDto.ToModel()
+
+	if indexResult.IsErr() {
+		return result.Errf[model.IndexCreationRequest]("error parsing index: %w", indexResult.UnwrapErr())
+	}
+
+	return result.Ok(model.IndexCreationRequest{
+		Name:  nameResult.Unwrap(),
+		Index: indexResult.Unwrap(),
+	})
+}
+
+type IndexDeletionRequestDto struct {
+	Name IndexNameDto `json:"name"`
+}
+
+func (i IndexDeletionRequestDto) ToModel() result.R[model.IndexDeletionRequest] {
+	nameResult := i.Name.ToModel()
+
+	if nameResult.IsErr() {
+		return result.Errf[model.IndexDeletionRequest]("error parsing index name: %w", nameResult.UnwrapErr())
+	}
+
+	return result.Ok(model.IndexDeletionRequest{
+		Name: nameResult.Unwrap(),
+	})
+}
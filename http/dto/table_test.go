@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTableCreationRequestDto(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		expectedRequest model.TableCreationRequest
+		expectErr       bool
+	}{
+		{
+			name: "bare schema",
+			body: `{"name": {"type": "string"}}`,
+			expectedRequest: model.TableCreationRequest{
+				Schema:  model.TableSchema{"name": {Type: model.FieldTypeString}},
+				Indexes: model.TableIndexes{},
+			},
+		},
+		{
+			name: "bare schema with fields called schema, indexes and version",
+			body: `{"schema": {"type": "string"}, "indexes": {"type": "integer"}, "version": {"type": "boolean"}}`,
+			expectedRequest: model.TableCreationRequest{
+				Schema: model.TableSchema{
+					"schema":  {Type: model.FieldTypeString},
+					"indexes": {Type: model.FieldTypeInteger},
+					"version": {Type: model.FieldTypeBoolean},
+				},
+				Indexes: model.TableIndexes{},
+			},
+		},
+		{
+			name: "versioned with indexes",
+			body: `{"version": 2, "schema": {"name": {"type": "string"}}, "indexes": {"byName": {"fields": ["name"], "unique": true}}}`,
+			expectedRequest: model.TableCreationRequest{
+				Schema: model.TableSchema{"name": {Type: model.FieldTypeString}},
+				Indexes: model.TableIndexes{
+					"byName": {Fields: []model.FieldName{"name"}, Unique: true},
+				},
+			},
+		},
+		{
+			name: "versioned without indexes",
+			body: `{"version": 2, "schema": {"indexes": {"type": "string"}}}`,
+			expectedRequest: model.TableCreationRequest{
+				Schema:  model.TableSchema{"indexes": {Type: model.FieldTypeString}},
+				Indexes: model.TableIndexes{},
+			},
+		},
+		{
+			name: "versioned link table",
+			body: `{"version": 2, "schema": {"post": {"type": "reference", "references": "posts"}}, "link": true}`,
+			expectedRequest: model.TableCreationRequest{
+				Schema: model.TableSchema{
+					"post": {Type: model.FieldTypeReference, References: optional.Some[model.TableName]("posts")},
+				},
+				Indexes: model.TableIndexes{},
+				Link:    true,
+			},
+		},
+		{
+			name:      "index declared as a link index",
+			body:      `{"version": 2, "schema": {"name": {"type": "string"}}, "indexes": {"byName": {"fields": ["name"], "link": true}}}`,
+			expectErr: true,
+		},
+		{
+			name:      "unsupported version",
+			body:      `{"version": 3, "schema": {}}`,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var tableCreationRequestDto TableCreationRequestDto
+
+			err := json.Unmarshal([]byte(test.body), &tableCreationRequestDto)
+
+			tableCreationRequest := model.TableCreationRequest{}
+
+			if err == nil {
+				tableCreationRequestResult := tableCreationRequestDto.ToModel()
+
+				if tableCreationRequestResult.IsErr() {
+					err = tableCreationRequestResult.UnwrapErr()
+				} else {
+					tableCreationRequest = tableCreationRequestResult.Unwrap()
+				}
+			}
+
+			if test.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(tableCreationRequest, test.expectedRequest) {
+				t.Errorf("unexpected request:\n got: %+v\nwant: %+v", tableCreationRequest, test.expectedRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"crudly/model"
+	"crudly/util/result"
+	"net/url"
+	"strings"
+)
+
+// Reads the comma separated field names of the upsertOn query param, which is
+// required for upserts
+func GetUpsertKeyFromQuery(query url.Values) result.R[model.UpsertKey] {
+	upsertOnQuery := query.Get("upsertOn")
+
+	if upsertOnQuery == "" {
+		return result.Errf[model.UpsertKey]("upsertOn query param is required")
+	}
+
+	upsertKey := model.UpsertKey{}
+
+	for _, fieldName := range strings.Split(upsertOnQuery, ",") {
+		if fieldName == "" {
+			return result.Errf[model.UpsertKey]("upsertOn contains an empty field name")
+		}
+
+		fieldNameResult := FieldNameDto(fieldName).ToModel()
+
+		if fieldNameResult.IsErr() {
+			return result.Errf[model.UpsertKey]("error parsing field name: %w", fieldNameResult.UnwrapErr())
+		}
+
+		upsertKey = append(upsertKey, fieldNameResult.Unwrap())
+	}
+
+	return result.Ok(upsertKey)
+}
+
+type UpsertedEntityDto struct {
+	Entity  EntityDto `json:"entity"`
+	Created bool      `json:"created"`
+}
+
+func GetUpsertedEntityDto(upsertedEntity model.UpsertedEntity) UpsertedEntityDto {
+	return UpsertedEntityDto{
+		Entity:  GetEntityDto(upsertedEntity.Entity),
+		Created: upsertedEntity.Created,
+	}
+}
+
+type UpsertedEntitiesDto []UpsertedEntityDto
+
+func GetUpsertedEntitiesDto(upsertedEntities []model.UpsertedEntity) UpsertedEntitiesDto {
+	result := UpsertedEntitiesDto{}
+
+	for _, upsertedEntity := range upsertedEntities {
+		result = append(result, GetUpsertedEntityDto(upsertedEntity))
+	}
+
+	return result
+}
@@ -6,6 +6,7 @@ import (
 	"crudly/http/dto"
 	"crudly/http/middleware"
 	"crudly/model"
+	"crudly/util/optional"
 	"crudly/util/result"
 	"encoding/json"
 	"fmt"
@@ -20,6 +21,8 @@ type entityGetter interface {
 		projectId model.ProjectId,
 		tableName model.TableName,
 		id model.EntityId,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
 	) result.R[model.Entity]
 
 	GetEntities(
@@ -28,6 +31,20 @@ type entityGetter interface {
 		entityFilter model.EntityFilter,
 		entityOrders model.EntityOrders,
 		paginationParams model.PaginationParams,
+		countType model.EntityCountType,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
+	) result.R[model.GetEntitiesResponse]
+
+	QueryEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		filterExpression model.EntityFilterExpression,
+		entityOrders model.EntityOrders,
+		paginationParams model.PaginationParams,
+		countType model.EntityCountType,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
 	) result.R[model.GetEntitiesResponse]
 }
 
@@ -50,6 +67,20 @@ type entityCreator interface {
 		tableName model.TableName,
 		entities model.Entities,
 	) error
+
+	UpsertEntity(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		upsertKey model.UpsertKey,
+		entity model.Entity,
+	) result.R[model.UpsertedEntity]
+
+	UpsertEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		upsertKey model.UpsertKey,
+		entities model.Entities,
+	) result.R[[]model.UpsertedEntity]
 }
 
 type entityUpdater interface {
@@ -77,12 +108,29 @@ type entityCountGetter interface {
 	) result.R[uint]
 }
 
+type entityAggregator interface {
+	AggregateEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		entityAggregationQuery model.EntityAggregationQuery,
+	) result.R[model.EntityAggregationResponse]
+
+	GetFieldValues(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		fieldName model.FieldName,
+		fieldValuesQuery model.FieldValuesQuery,
+	) result.R[model.GetFieldValuesResponse]
+}
+
 type entityHandler struct {
 	entityGetter      entityGetter
 	entityCreator     entityCreator
 	entityUpdater     entityUpdater
 	entityDeleter     entityDeleter
 	entityCountGetter entityCountGetter
+	entityAggregator  entityAggregator
+	entityRelator     entityRelator
 }
 
 func NewEntityHandler(
@@ -91,6 +139,8 @@ func NewEntityHandler(
 	entityUpdater entityUpdater,
 	entityDeleter entityDeleter,
 	entityCountGetter entityCountGetter,
+	entityAggregator entityAggregator,
+	entityRelator entityRelator,
 ) entityHandler {
 	return entityHandler{
 		entityGetter,
@@ -98,6 +148,8 @@ func NewEntityHandler(
 		entityUpdater,
 		entityDeleter,
 		entityCountGetter,
+		entityAggregator,
+		entityRelator,
 	}
 }
 
@@ -105,6 +157,15 @@ func (e *entityHandler) GetEntity(w http.ResponseWriter, r *http.Request) {
 	projectId := ctx.GetRequestProjectId(r)
 	tableName := ctx.GetRequestTableName(r)
 
+	bigIntFormatResult := dto.GetBigIntFormatFromQuery(r.URL.Query())
+
+	if bigIntFormatResult.IsErr() {
+		middleware.AttachError(w, bigIntFormatResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid bigIntFormat query param"))
+		return
+	}
+
 	vars := mux.Vars(r)
 
 	entityIdDto := dto.EntityIdDto(vars["id"])
@@ -118,10 +179,30 @@ func (e *entityHandler) GetEntity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fieldProjectionResult := dto.GetFieldProjectionFromQuery(r.URL.Query())
+
+	if fieldProjectionResult.IsErr() {
+		middleware.AttachError(w, fieldProjectionResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid fields query param"))
+		return
+	}
+
+	entityExpansionResult := dto.GetEntityExpansionFromQuery(r.URL.Query())
+
+	if entityExpansionResult.IsErr() {
+		middleware.AttachError(w, entityExpansionResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid expand query param"))
+		return
+	}
+
 	entityResult := e.entityGetter.GetEntity(
 		projectId,
 		tableName,
 		entityIdResult.Unwrap(),
+		fieldProjectionResult.Unwrap(),
+		entityExpansionResult.Unwrap(),
 	)
 
 	if entityResult.IsErr() {
@@ -129,6 +210,18 @@ func (e *entityHandler) GetEntity(w http.ResponseWriter, r *http.Request) {
 
 		middleware.AttachError(w, err)
 
+		if invalidFieldProjectionError, ok := err.(errs.InvalidFieldProjectionError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidFieldProjectionError.Error()))
+			return
+		}
+
+		if invalidEntityExpansionError, ok := err.(errs.InvalidEntityExpansionError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityExpansionError.Error()))
+			return
+		}
+
 		if _, ok := err.(errs.EntityNotFoundError); ok {
 			w.WriteHeader(404)
 			w.Write([]byte("entity not found"))
@@ -140,7 +233,7 @@ func (e *entityHandler) GetEntity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	entityDto := dto.GetEntityDto(entityResult.Unwrap())
+	entityDto := bigIntFormatResult.Unwrap().FormatEntity(dto.GetEntityDto(entityResult.Unwrap()))
 
 	resBodyBytes, _ := json.Marshal(entityDto)
 
@@ -152,6 +245,15 @@ func (e *entityHandler) GetEntities(w http.ResponseWriter, r *http.Request) {
 	projectId := ctx.GetRequestProjectId(r)
 	tableName := ctx.GetRequestTableName(r)
 
+	bigIntFormatResult := dto.GetBigIntFormatFromQuery(r.URL.Query())
+
+	if bigIntFormatResult.IsErr() {
+		middleware.AttachError(w, bigIntFormatResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid bigIntFormat query param"))
+		return
+	}
+
 	paginationParams := model.PaginationParams{
 		Limit:  model.DefaultPaginationLimit,
 		Offset: model.DefaultPaginationOffset,
@@ -187,6 +289,21 @@ func (e *entityHandler) GetEntities(w http.ResponseWriter, r *http.Request) {
 		paginationParams.Offset = offsetResult.Unwrap()
 	}
 
+	entityCursorDto := dto.EntityCursorDto(r.URL.Query().Get("cursor"))
+
+	if entityCursorDto != "" {
+		cursorResult := entityCursorDto.ToModel()
+
+		if cursorResult.IsErr() {
+			middleware.AttachError(w, cursorResult.UnwrapErr())
+			w.WriteHeader(400)
+			w.Write([]byte("invalid cursor query param"))
+			return
+		}
+
+		paginationParams.Cursor = optional.Some(cursorResult.Unwrap())
+	}
+
 	entityFilterResult := dto.GetEntityFilterFromQuery(r.URL.Query())
 
 	if entityFilterResult.IsErr() {
@@ -207,12 +324,42 @@ func (e *entityHandler) GetEntities(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	countTypeResult := dto.EntityCountTypeDto(r.URL.Query().Get("count")).ToModel()
+
+	if countTypeResult.IsErr() {
+		middleware.AttachError(w, countTypeResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid count query param"))
+		return
+	}
+
+	fieldProjectionResult := dto.GetFieldProjectionFromQuery(r.URL.Query())
+
+	if fieldProjectionResult.IsErr() {
+		middleware.AttachError(w, fieldProjectionResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid fields query param"))
+		return
+	}
+
+	entityExpansionResult := dto.GetEntityExpansionFromQuery(r.URL.Query())
+
+	if entityExpansionResult.IsErr() {
+		middleware.AttachError(w, entityExpansionResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid expand query param"))
+		return
+	}
+
 	entitiesResult := e.entityGetter.GetEntities(
 		projectId,
 		tableName,
 		entityFilterResult.Unwrap(),
 		entityOrderResult.Unwrap(),
 		paginationParams,
+		countTypeResult.Unwrap(),
+		fieldProjectionResult.Unwrap(),
+		entityExpansionResult.Unwrap(),
 	)
 
 	if entitiesResult.IsErr() {
@@ -232,12 +379,133 @@ func (e *entityHandler) GetEntities(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if invalidEntityCursorError, ok := err.(errs.InvalidEntityCursorError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityCursorError.Error()))
+			return
+		}
+
+		if invalidFieldProjectionError, ok := err.(errs.InvalidFieldProjectionError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidFieldProjectionError.Error()))
+			return
+		}
+
+		if invalidEntityExpansionError, ok := err.(errs.InvalidEntityExpansionError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityExpansionError.Error()))
+			return
+		}
+
 		w.WriteHeader(500)
 		w.Write([]byte("unexpected error getting entities"))
 		return
 	}
 
 	getEntitiesResponseDto := dto.GetGetEntitiesResponseDto(entitiesResult.Unwrap())
+	bigIntFormatResult.Unwrap().FormatEntities(getEntitiesResponseDto.Entities)
+
+	resBodyBytes, _ := json.Marshal(getEntitiesResponseDto)
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resBodyBytes)
+}
+
+func (e *entityHandler) QueryEntities(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	bigIntFormatResult := dto.GetBigIntFormatFromQuery(r.URL.Query())
+
+	if bigIntFormatResult.IsErr() {
+		middleware.AttachError(w, bigIntFormatResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid bigIntFormat query param"))
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		panic("error reading body")
+	}
+
+	var entityQueryDto dto.EntityQueryDto
+	err = json.Unmarshal(bodyBytes, &entityQueryDto)
+
+	if err != nil {
+		middleware.AttachError(w, err)
+		w.WriteHeader(400)
+		w.Write([]byte("invalid request body"))
+		return
+	}
+
+	entityQueryResult := entityQueryDto.ToModel()
+
+	if entityQueryResult.IsErr() {
+		err := entityQueryResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	entityQuery := entityQueryResult.Unwrap()
+
+	entitiesResult := e.entityGetter.QueryEntities(
+		projectId,
+		tableName,
+		entityQuery.FilterExpression,
+		entityQuery.Orders,
+		entityQuery.PaginationParams,
+		entityQuery.CountType,
+		entityQuery.FieldProjection,
+		entityQuery.Expansion,
+	)
+
+	if entitiesResult.IsErr() {
+		err := entitiesResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+
+		if invalidEntityFilterError, ok := err.(errs.InvalidEntityFilterError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityFilterError.Error()))
+			return
+		}
+
+		if invalidEntityOrderError, ok := err.(errs.InvalidEntityOrderError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityOrderError.Error()))
+			return
+		}
+
+		if invalidEntityCursorError, ok := err.(errs.InvalidEntityCursorError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityCursorError.Error()))
+			return
+		}
+
+		if invalidFieldProjectionError, ok := err.(errs.InvalidFieldProjectionError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidFieldProjectionError.Error()))
+			return
+		}
+
+		if invalidEntityExpansionError, ok := err.(errs.InvalidEntityExpansionError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityExpansionError.Error()))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error querying entities"))
+		return
+	}
+
+	getEntitiesResponseDto := dto.GetGetEntitiesResponseDto(entitiesResult.Unwrap())
+	bigIntFormatResult.Unwrap().FormatEntities(getEntitiesResponseDto.Entities)
 
 	resBodyBytes, _ := json.Marshal(getEntitiesResponseDto)
 
@@ -302,6 +570,18 @@ func (e *entityHandler) PutEntity(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if err, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.UniqueViolationError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
 		w.WriteHeader(500)
 		w.Write([]byte("unexpected error creating entity"))
 		return
@@ -350,6 +630,18 @@ func (e *entityHandler) PostEntity(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if err, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.UniqueViolationError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
 		w.WriteHeader(500)
 		w.Write([]byte("unexpected error creating entity"))
 		return
@@ -396,6 +688,18 @@ func (e *entityHandler) PostEntityBatch(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
+		if err, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.UniqueViolationError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
 		w.WriteHeader(500)
 		w.Write([]byte("unexpected error creating entity"))
 		return
@@ -404,10 +708,185 @@ func (e *entityHandler) PostEntityBatch(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(201)
 }
 
+func (e *entityHandler) UpsertEntity(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	bigIntFormatResult := dto.GetBigIntFormatFromQuery(r.URL.Query())
+
+	if bigIntFormatResult.IsErr() {
+		middleware.AttachError(w, bigIntFormatResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid bigIntFormat query param"))
+		return
+	}
+
+	upsertKeyResult := dto.GetUpsertKeyFromQuery(r.URL.Query())
+
+	if upsertKeyResult.IsErr() {
+		middleware.AttachError(w, upsertKeyResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid upsertOn query param"))
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		panic("error reading body")
+	}
+
+	var entityDto dto.EntityDto
+	json.Unmarshal(bodyBytes, &entityDto)
+
+	entityResult := entityDto.ToModel()
+
+	if entityResult.IsErr() {
+		middleware.AttachError(w, entityResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid entity"))
+		return
+	}
+
+	upsertedEntityResult := e.entityCreator.UpsertEntity(
+		projectId,
+		tableName,
+		upsertKeyResult.Unwrap(),
+		entityResult.Unwrap(),
+	)
+
+	if upsertedEntityResult.IsErr() {
+		err := upsertedEntityResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		writeUpsertError(w, err)
+		return
+	}
+
+	upsertedEntityDto := dto.GetUpsertedEntityDto(upsertedEntityResult.Unwrap())
+	upsertedEntityDto.Entity = bigIntFormatResult.Unwrap().FormatEntity(upsertedEntityDto.Entity)
+
+	resBodyBytes, _ := json.Marshal(upsertedEntityDto)
+
+	w.Header().Set("content-type", "application/json")
+
+	if upsertedEntityDto.Created {
+		w.WriteHeader(201)
+	}
+
+	w.Write(resBodyBytes)
+}
+
+func (e *entityHandler) UpsertEntityBatch(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	bigIntFormatResult := dto.GetBigIntFormatFromQuery(r.URL.Query())
+
+	if bigIntFormatResult.IsErr() {
+		middleware.AttachError(w, bigIntFormatResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid bigIntFormat query param"))
+		return
+	}
+
+	upsertKeyResult := dto.GetUpsertKeyFromQuery(r.URL.Query())
+
+	if upsertKeyResult.IsErr() {
+		middleware.AttachError(w, upsertKeyResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid upsertOn query param"))
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		panic("error reading body")
+	}
+
+	var entitiesDto dto.EntitiesDto
+	json.Unmarshal(bodyBytes, &entitiesDto)
+
+	entitiesResult := entitiesDto.ToModel()
+
+	if entitiesResult.IsErr() {
+		middleware.AttachError(w, entitiesResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid entities array"))
+		return
+	}
+
+	upsertedEntitiesResult := e.entityCreator.UpsertEntities(
+		projectId,
+		tableName,
+		upsertKeyResult.Unwrap(),
+		entitiesResult.Unwrap(),
+	)
+
+	if upsertedEntitiesResult.IsErr() {
+		err := upsertedEntitiesResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		writeUpsertError(w, err)
+		return
+	}
+
+	upsertedEntitiesDto := dto.GetUpsertedEntitiesDto(upsertedEntitiesResult.Unwrap())
+
+	for i := range upsertedEntitiesDto {
+		upsertedEntitiesDto[i].Entity = bigIntFormatResult.Unwrap().FormatEntity(upsertedEntitiesDto[i].Entity)
+	}
+
+	resBodyBytes, _ := json.Marshal(upsertedEntitiesDto)
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resBodyBytes)
+}
+
+func writeUpsertError(w http.ResponseWriter, err error) {
+	if err, ok := err.(errs.InvalidEntityError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err, ok := err.(errs.InvalidUpsertKeyError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	// Another unique field or index clashes with an existing entity
+	if err, ok := err.(errs.UniqueViolationError); ok {
+		w.WriteHeader(409)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(500)
+	w.Write([]byte("unexpected error upserting entity"))
+}
+
 func (e *entityHandler) PatchEntity(w http.ResponseWriter, r *http.Request) {
 	projectId := ctx.GetRequestProjectId(r)
 	tableName := ctx.GetRequestTableName(r)
 
+	bigIntFormatResult := dto.GetBigIntFormatFromQuery(r.URL.Query())
+
+	if bigIntFormatResult.IsErr() {
+		middleware.AttachError(w, bigIntFormatResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid bigIntFormat query param"))
+		return
+	}
+
 	vars := mux.Vars(r)
 
 	entityIdDto := dto.EntityIdDto(vars["id"])
@@ -463,12 +942,24 @@ func (e *entityHandler) PatchEntity(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if err, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.UniqueViolationError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
 		w.WriteHeader(500)
 		w.Write([]byte("unexpected error updating entity"))
 		return
 	}
 
-	entityDto := dto.GetEntityDto(entityResult.Unwrap())
+	entityDto := bigIntFormatResult.Unwrap().FormatEntity(dto.GetEntityDto(entityResult.Unwrap()))
 
 	resBodyBytes, _ := json.Marshal(entityDto)
 
@@ -508,6 +999,12 @@ func (e *entityHandler) DeleteEntity(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if err, ok := err.(errs.EntityReferencedError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
 		w.WriteHeader(500)
 		w.Write([]byte("unexpected error deleting entity"))
 		return
@@ -527,6 +1024,32 @@ func (e *entityHandler) GetTotalEntityCount(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	entityHistogramResult := dto.GetEntityHistogramFromQuery(r.URL.Query())
+
+	if entityHistogramResult.IsErr() {
+		middleware.AttachError(w, entityHistogramResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte(entityHistogramResult.UnwrapErr().Error()))
+		return
+	}
+
+	// A histogram of counts is just an aggregation so is served by that
+	// instead
+	if entityHistogramResult.Unwrap().IsSome() {
+		e.writeEntityAggregation(w, projectId, tableName, model.EntityAggregationQuery{
+			Filter:  entityFilterResult.Unwrap(),
+			GroupBy: []model.FieldName{},
+			Aggregations: model.EntityAggregations{
+				{
+					Type:      model.EntityAggregationTypeCount,
+					FieldName: optional.None[model.FieldName](),
+				},
+			},
+			Histogram: entityHistogramResult.Unwrap(),
+		}, dto.BigIntFormatNumber)
+		return
+	}
+
 	totalEntityCountResult := e.entityCountGetter.GetTotalEntityCount(
 		projectId,
 		tableName,
@@ -557,3 +1080,138 @@ func (e *entityHandler) GetTotalEntityCount(w http.ResponseWriter, r *http.Reque
 	w.Header().Set("content-type", "application/json")
 	w.Write([]byte(fmt.Sprintf("{\"totalCount\":%d}", totalEntityCountResult.Unwrap())))
 }
+
+func (e *entityHandler) GetEntityAggregation(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	bigIntFormatResult := dto.GetBigIntFormatFromQuery(r.URL.Query())
+
+	if bigIntFormatResult.IsErr() {
+		middleware.AttachError(w, bigIntFormatResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid bigIntFormat query param"))
+		return
+	}
+
+	entityAggregationQueryResult := dto.GetEntityAggregationQueryFromQuery(r.URL.Query())
+
+	if entityAggregationQueryResult.IsErr() {
+		middleware.AttachError(w, entityAggregationQueryResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte(entityAggregationQueryResult.UnwrapErr().Error()))
+		return
+	}
+
+	e.writeEntityAggregation(
+		w,
+		projectId,
+		tableName,
+		entityAggregationQueryResult.Unwrap(),
+		bigIntFormatResult.Unwrap(),
+	)
+}
+
+func (e *entityHandler) writeEntityAggregation(
+	w http.ResponseWriter,
+	projectId model.ProjectId,
+	tableName model.TableName,
+	entityAggregationQuery model.EntityAggregationQuery,
+	bigIntFormat dto.BigIntFormatDto,
+) {
+	entityAggregationResult := e.entityAggregator.AggregateEntities(
+		projectId,
+		tableName,
+		entityAggregationQuery,
+	)
+
+	if entityAggregationResult.IsErr() {
+		err := entityAggregationResult.UnwrapErr()
+		middleware.AttachError(w, err)
+
+		if invalidEntityFilterError, ok := err.(errs.InvalidEntityFilterError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityFilterError.Error()))
+			return
+		}
+
+		if invalidEntityAggregationError, ok := err.(errs.InvalidEntityAggregationError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityAggregationError.Error()))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error aggregating entities"))
+		return
+	}
+
+	entityAggregationResponseDto := bigIntFormat.FormatEntityAggregationResponse(
+		dto.GetEntityAggregationResponseDto(entityAggregationResult.Unwrap()),
+	)
+
+	resBodyBytes, _ := json.Marshal(entityAggregationResponseDto)
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resBodyBytes)
+}
+
+func (e *entityHandler) GetFieldValues(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	vars := mux.Vars(r)
+
+	fieldNameResult := dto.FieldNameDto(vars["fieldName"]).ToModel()
+
+	if fieldNameResult.IsErr() {
+		middleware.AttachError(w, fieldNameResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid field name"))
+		return
+	}
+
+	fieldValuesQueryResult := dto.GetFieldValuesQueryFromQuery(r.URL.Query())
+
+	if fieldValuesQueryResult.IsErr() {
+		middleware.AttachError(w, fieldValuesQueryResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte(fieldValuesQueryResult.UnwrapErr().Error()))
+		return
+	}
+
+	fieldValuesResult := e.entityAggregator.GetFieldValues(
+		projectId,
+		tableName,
+		fieldNameResult.Unwrap(),
+		fieldValuesQueryResult.Unwrap(),
+	)
+
+	if fieldValuesResult.IsErr() {
+		err := fieldValuesResult.UnwrapErr()
+		middleware.AttachError(w, err)
+
+		if invalidEntityFilterError, ok := err.(errs.InvalidEntityFilterError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityFilterError.Error()))
+			return
+		}
+
+		if invalidEntityAggregationError, ok := err.(errs.InvalidEntityAggregationError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(invalidEntityAggregationError.Error()))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error getting field values"))
+		return
+	}
+
+	getFieldValuesResponseDto := dto.GetGetFieldValuesResponseDto(fieldValuesResult.Unwrap())
+
+	resBodyBytes, _ := json.Marshal(getFieldValuesResponseDto)
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resBodyBytes)
+}
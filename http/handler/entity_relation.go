@@ -0,0 +1,462 @@
+package handler
+
+import (
+	"crudly/ctx"
+	"crudly/errs"
+	"crudly/http/dto"
+	"crudly/http/middleware"
+	"crudly/model"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type entityRelator interface {
+	GetRelatedEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		id model.EntityId,
+		relatedTableName model.TableName,
+		via optional.O[model.FieldName],
+		entityFilter model.EntityFilter,
+		entityOrders model.EntityOrders,
+		paginationParams model.PaginationParams,
+		countType model.EntityCountType,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
+	) result.R[model.GetEntitiesResponse]
+
+	GetLinkedEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		id model.EntityId,
+		linkTableName model.TableName,
+		paginationParams model.PaginationParams,
+		countType model.EntityCountType,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
+	) result.R[model.GetEntitiesResponse]
+
+	CreateLink(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		id model.EntityId,
+		linkTableName model.TableName,
+		otherId model.EntityId,
+	) result.R[bool]
+
+	DeleteLink(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		id model.EntityId,
+		linkTableName model.TableName,
+		otherId model.EntityId,
+	) error
+}
+
+func (e *entityHandler) GetRelatedEntities(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	bigIntFormatResult := dto.GetBigIntFormatFromQuery(r.URL.Query())
+
+	if bigIntFormatResult.IsErr() {
+		middleware.AttachError(w, bigIntFormatResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid bigIntFormat query param"))
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	entityIdResult := dto.EntityIdDto(vars["id"]).ToModel()
+
+	if entityIdResult.IsErr() {
+		middleware.AttachError(w, entityIdResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid entity id"))
+		return
+	}
+
+	relatedTableNameResult := dto.TableNameDto(vars["relatedTableName"]).ToModel()
+
+	if relatedTableNameResult.IsErr() {
+		middleware.AttachError(w, relatedTableNameResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid related table name"))
+		return
+	}
+
+	via := optional.None[model.FieldName]()
+
+	if viaQuery := r.URL.Query().Get("via"); viaQuery != "" {
+		viaResult := dto.FieldNameDto(viaQuery).ToModel()
+
+		if viaResult.IsErr() {
+			middleware.AttachError(w, viaResult.UnwrapErr())
+			w.WriteHeader(400)
+			w.Write([]byte("invalid via query param"))
+			return
+		}
+
+		via = optional.Some(viaResult.Unwrap())
+	}
+
+	paginationParamsResult := dto.GetPaginationParamsFromQuery(r.URL.Query())
+
+	if paginationParamsResult.IsErr() {
+		err := paginationParamsResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	entityFilterResult := dto.GetEntityFilterFromQuery(r.URL.Query())
+
+	if entityFilterResult.IsErr() {
+		middleware.AttachError(w, entityFilterResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte(entityFilterResult.UnwrapErr().Error()))
+		return
+	}
+
+	entityOrderResult := dto.GetEntityOrderFromQuery(r.URL.Query())
+
+	if entityOrderResult.IsErr() {
+		err := entityOrderResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	countTypeResult := dto.EntityCountTypeDto(r.URL.Query().Get("count")).ToModel()
+
+	if countTypeResult.IsErr() {
+		middleware.AttachError(w, countTypeResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid count query param"))
+		return
+	}
+
+	fieldProjectionResult := dto.GetFieldProjectionFromQuery(r.URL.Query())
+
+	if fieldProjectionResult.IsErr() {
+		middleware.AttachError(w, fieldProjectionResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid fields query param"))
+		return
+	}
+
+	entityExpansionResult := dto.GetEntityExpansionFromQuery(r.URL.Query())
+
+	if entityExpansionResult.IsErr() {
+		middleware.AttachError(w, entityExpansionResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid expand query param"))
+		return
+	}
+
+	entitiesResult := e.entityRelator.GetRelatedEntities(
+		projectId,
+		tableName,
+		entityIdResult.Unwrap(),
+		relatedTableNameResult.Unwrap(),
+		via,
+		entityFilterResult.Unwrap(),
+		entityOrderResult.Unwrap(),
+		paginationParamsResult.Unwrap(),
+		countTypeResult.Unwrap(),
+		fieldProjectionResult.Unwrap(),
+		entityExpansionResult.Unwrap(),
+	)
+
+	if entitiesResult.IsErr() {
+		err := entitiesResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		writeRelationError(w, err, "unexpected error getting related entities")
+		return
+	}
+
+	getEntitiesResponseDto := dto.GetGetEntitiesResponseDto(entitiesResult.Unwrap())
+	bigIntFormatResult.Unwrap().FormatEntities(getEntitiesResponseDto.Entities)
+
+	resBodyBytes, _ := json.Marshal(getEntitiesResponseDto)
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resBodyBytes)
+}
+
+func (e *entityHandler) GetLinkedEntities(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	bigIntFormatResult := dto.GetBigIntFormatFromQuery(r.URL.Query())
+
+	if bigIntFormatResult.IsErr() {
+		middleware.AttachError(w, bigIntFormatResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid bigIntFormat query param"))
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	entityIdResult := dto.EntityIdDto(vars["id"]).ToModel()
+
+	if entityIdResult.IsErr() {
+		middleware.AttachError(w, entityIdResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid entity id"))
+		return
+	}
+
+	linkTableNameResult := dto.TableNameDto(vars["linkTableName"]).ToModel()
+
+	if linkTableNameResult.IsErr() {
+		middleware.AttachError(w, linkTableNameResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid link table name"))
+		return
+	}
+
+	paginationParamsResult := dto.GetPaginationParamsFromQuery(r.URL.Query())
+
+	if paginationParamsResult.IsErr() {
+		err := paginationParamsResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	countTypeResult := dto.EntityCountTypeDto(r.URL.Query().Get("count")).ToModel()
+
+	if countTypeResult.IsErr() {
+		middleware.AttachError(w, countTypeResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid count query param"))
+		return
+	}
+
+	fieldProjectionResult := dto.GetFieldProjectionFromQuery(r.URL.Query())
+
+	if fieldProjectionResult.IsErr() {
+		middleware.AttachError(w, fieldProjectionResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid fields query param"))
+		return
+	}
+
+	entityExpansionResult := dto.GetEntityExpansionFromQuery(r.URL.Query())
+
+	if entityExpansionResult.IsErr() {
+		middleware.AttachError(w, entityExpansionResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid expand query param"))
+		return
+	}
+
+	entitiesResult := e.entityRelator.GetLinkedEntities(
+		projectId,
+		tableName,
+		entityIdResult.Unwrap(),
+		linkTableNameResult.Unwrap(),
+		paginationParamsResult.Unwrap(),
+		countTypeResult.Unwrap(),
+		fieldProjectionResult.Unwrap(),
+		entityExpansionResult.Unwrap(),
+	)
+
+	if entitiesResult.IsErr() {
+		err := entitiesResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		writeRelationError(w, err, "unexpected error getting linked entities")
+		return
+	}
+
+	getEntitiesResponseDto := dto.GetGetEntitiesResponseDto(entitiesResult.Unwrap())
+	bigIntFormatResult.Unwrap().FormatEntities(getEntitiesResponseDto.Entities)
+
+	resBodyBytes, _ := json.Marshal(getEntitiesResponseDto)
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resBodyBytes)
+}
+
+func (e *entityHandler) PutLink(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	linkParamsResult := getLinkParams(mux.Vars(r))
+
+	if linkParamsResult.IsErr() {
+		err := linkParamsResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	linkParams := linkParamsResult.Unwrap()
+
+	createdResult := e.entityRelator.CreateLink(
+		projectId,
+		tableName,
+		linkParams.id,
+		linkParams.linkTableName,
+		linkParams.otherId,
+	)
+
+	if createdResult.IsErr() {
+		err := createdResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		writeRelationError(w, err, "unexpected error creating link")
+		return
+	}
+
+	// Linking entities that are already linked is a no-op
+	if createdResult.Unwrap() {
+		w.WriteHeader(201)
+	}
+}
+
+func (e *entityHandler) DeleteLink(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	linkParamsResult := getLinkParams(mux.Vars(r))
+
+	if linkParamsResult.IsErr() {
+		err := linkParamsResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	linkParams := linkParamsResult.Unwrap()
+
+	err := e.entityRelator.DeleteLink(
+		projectId,
+		tableName,
+		linkParams.id,
+		linkParams.linkTableName,
+		linkParams.otherId,
+	)
+
+	if err != nil {
+		middleware.AttachError(w, err)
+		writeRelationError(w, err, "unexpected error deleting link")
+		return
+	}
+}
+
+type linkParams struct {
+	id            model.EntityId
+	linkTableName model.TableName
+	otherId       model.EntityId
+}
+
+func getLinkParams(vars map[string]string) result.R[linkParams] {
+	entityIdResult := dto.EntityIdDto(vars["id"]).ToModel()
+
+	if entityIdResult.IsErr() {
+		return result.Errf[linkParams]("invalid entity id")
+	}
+
+	linkTableNameResult := dto.TableNameDto(vars["linkTableName"]).ToModel()
+
+	if linkTableNameResult.IsErr() {
+		return result.Errf[linkParams]("invalid link table name")
+	}
+
+	otherIdResult := dto.EntityIdDto(vars["otherId"]).ToModel()
+
+	if otherIdResult.IsErr() {
+		return result.Errf[linkParams]("invalid other entity id")
+	}
+
+	return result.Ok(linkParams{
+		entityIdResult.Unwrap(),
+		linkTableNameResult.Unwrap(),
+		otherIdResult.Unwrap(),
+	})
+}
+
+func writeRelationError(w http.ResponseWriter, err error, unexpectedMessage string) {
+	if err, ok := err.(errs.InvalidRelationError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err, ok := err.(errs.InvalidEntityFilterError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err, ok := err.(errs.InvalidEntityOrderError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err, ok := err.(errs.InvalidEntityCursorError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err, ok := err.(errs.InvalidFieldProjectionError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err, ok := err.(errs.InvalidEntityExpansionError); ok {
+		w.WriteHeader(400)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if _, ok := err.(errs.TableNotFoundError); ok {
+		w.WriteHeader(404)
+		w.Write([]byte("table not found"))
+		return
+	}
+
+	if _, ok := err.(errs.EntityNotFoundError); ok {
+		w.WriteHeader(404)
+		w.Write([]byte("entity not found"))
+		return
+	}
+
+	if err, ok := err.(errs.LinkNotFoundError); ok {
+		w.WriteHeader(404)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(500)
+	w.Write([]byte(unexpectedMessage))
+}
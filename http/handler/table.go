@@ -21,6 +21,8 @@ type tableCreator interface {
 		projectId model.ProjectId,
 		tableName model.TableName,
 		schema model.TableSchema,
+		indexes model.TableIndexes,
+		link bool,
 	) error
 }
 
@@ -51,12 +53,75 @@ type tableFieldDeleter interface {
 	) error
 }
 
+type tableFieldRenamer interface {
+	RenameField(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		name model.FieldName,
+		newName model.FieldName,
+	) error
+}
+
+type tableRenamer interface {
+	RenameTable(
+		projectId model.ProjectId,
+		name model.TableName,
+		newName model.TableName,
+	) error
+}
+
+type tableFieldAlterer interface {
+	AlterField(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		fieldAlterationRequest model.FieldAlterationRequest,
+	) result.R[model.FieldAlteration]
+}
+
+type tableTimeFieldMigrator interface {
+	MigrateTimeFields(
+		projectId model.ProjectId,
+		tableName model.TableName,
+	) result.R[[]model.FieldName]
+}
+
+type tableIndexGetter interface {
+	GetTableIndexes(
+		projectId model.ProjectId,
+		tableName model.TableName,
+	) result.R[model.TableIndexes]
+}
+
+type tableIndexAdder interface {
+	AddIndex(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		name model.IndexName,
+		index model.TableIndex,
+	) error
+}
+
+type tableIndexDeleter interface {
+	DeleteIndex(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		name model.IndexName,
+	) error
+}
+
 type tableHandler struct {
-	tableCreator      tableCreator
-	tableSchemaGetter tableSchemaGetter
-	tableDeleter      tableDeleter
-	tableFieldAdder   tableFieldAdder
-	tableFieldDeleter tableFieldDeleter
+	tableCreator           tableCreator
+	tableSchemaGetter      tableSchemaGetter
+	tableDeleter           tableDeleter
+	tableFieldAdder        tableFieldAdder
+	tableFieldDeleter      tableFieldDeleter
+	tableTimeFieldMigrator tableTimeFieldMigrator
+	tableIndexGetter       tableIndexGetter
+	tableIndexAdder        tableIndexAdder
+	tableIndexDeleter      tableIndexDeleter
+	tableFieldRenamer      tableFieldRenamer
+	tableRenamer           tableRenamer
+	tableFieldAlterer      tableFieldAlterer
 }
 
 func NewTableHandler(
@@ -65,6 +130,13 @@ func NewTableHandler(
 	tableDeleter tableDeleter,
 	tableFieldAdder tableFieldAdder,
 	tableFieldDeleter tableFieldDeleter,
+	tableTimeFieldMigrator tableTimeFieldMigrator,
+	tableIndexGetter tableIndexGetter,
+	tableIndexAdder tableIndexAdder,
+	tableIndexDeleter tableIndexDeleter,
+	tableFieldRenamer tableFieldRenamer,
+	tableRenamer tableRenamer,
+	tableFieldAlterer tableFieldAlterer,
 ) tableHandler {
 	return tableHandler{
 		tableCreator,
@@ -72,6 +144,13 @@ func NewTableHandler(
 		tableDeleter,
 		tableFieldAdder,
 		tableFieldDeleter,
+		tableTimeFieldMigrator,
+		tableIndexGetter,
+		tableIndexAdder,
+		tableIndexDeleter,
+		tableFieldRenamer,
+		tableRenamer,
+		tableFieldAlterer,
 	}
 }
 
@@ -97,22 +176,33 @@ func (t *tableHandler) PutTable(w http.ResponseWriter, r *http.Request) {
 		panic("error reading body")
 	}
 
-	var tableSchemaDto dto.TableSchemaDto
-	json.Unmarshal(bodyBytes, &tableSchemaDto)
+	var tableCreationRequestDto dto.TableCreationRequestDto
+	err = json.Unmarshal(bodyBytes, &tableCreationRequestDto)
 
-	tableSchemaResult := tableSchemaDto.ToModel()
+	if err != nil {
+		middleware.AttachError(w, err)
+		w.WriteHeader(400)
+		w.Write([]byte("invalid table schema"))
+		return
+	}
 
-	if tableSchemaResult.IsErr() {
-		middleware.AttachError(w, tableSchemaResult.UnwrapErr())
+	tableCreationRequestResult := tableCreationRequestDto.ToModel()
+
+	if tableCreationRequestResult.IsErr() {
+		middleware.AttachError(w, tableCreationRequestResult.UnwrapErr())
 		w.WriteHeader(400)
 		w.Write([]byte("invalid table schema"))
 		return
 	}
 
+	tableCreationRequest := tableCreationRequestResult.Unwrap()
+
 	err = t.tableCreator.CreateTable(
 		projectId,
 		tableNameResult.Unwrap(),
-		tableSchemaResult.Unwrap(),
+		tableCreationRequest.Schema,
+		tableCreationRequest.Indexes,
+		tableCreationRequest.Link,
 	)
 
 	if err != nil {
@@ -124,6 +214,12 @@ func (t *tableHandler) PutTable(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if err, ok := err.(errs.InvalidTableIndexError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
 		w.WriteHeader(500)
 		w.Write([]byte("unexpected error creating table"))
 		return
@@ -214,6 +310,13 @@ func (t *tableHandler) DeleteTable(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		middleware.AttachError(w, err)
+
+		if err, ok := err.(errs.TableReferencedError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
 		w.WriteHeader(500)
 		w.Write([]byte("unexpected error deleting table"))
 		return
@@ -275,6 +378,30 @@ func (t *tableHandler) AddField(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if err, ok := err.(errs.InvalidTableError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.InvalidDefaultValueError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.ReferencedEntityNotFoundError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.UniqueViolationError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
 		w.WriteHeader(500)
 		w.Write([]byte("unexpected error creating field"))
 		return
@@ -341,3 +468,441 @@ func (t *tableHandler) DeleteField(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(200)
 }
+
+func (t *tableHandler) RenameField(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+
+	vars := mux.Vars(r)
+
+	tableNameDto := dto.TableNameDto(vars["tableName"])
+
+	tableNameResult := tableNameDto.ToModel()
+
+	if tableNameResult.IsErr() {
+		middleware.AttachError(w, tableNameResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid table name"))
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		panic("error reading body")
+	}
+
+	var fieldRenameRequestDto dto.FieldRenameRequestDto
+	json.Unmarshal(bodyBytes, &fieldRenameRequestDto)
+
+	fieldRenameRequestResult := fieldRenameRequestDto.ToModel()
+
+	if fieldRenameRequestResult.IsErr() {
+		middleware.AttachError(w, fieldRenameRequestResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid request body"))
+		return
+	}
+
+	fieldRenameRequest := fieldRenameRequestResult.Unwrap()
+
+	err = t.tableFieldRenamer.RenameField(
+		projectId,
+		tableNameResult.Unwrap(),
+		fieldRenameRequest.Name,
+		fieldRenameRequest.NewName,
+	)
+
+	if err != nil {
+		middleware.AttachError(w, err)
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			w.WriteHeader(404)
+			w.Write([]byte("table not found"))
+			return
+		}
+
+		if _, ok := err.(errs.FieldNotFoundError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte("field not found"))
+			return
+		}
+
+		if err, ok := err.(errs.InvalidTableError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.FieldAlreadyExistsError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error renaming field"))
+		return
+	}
+
+	w.WriteHeader(200)
+}
+
+func (t *tableHandler) RenameTable(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+
+	vars := mux.Vars(r)
+
+	tableNameDto := dto.TableNameDto(vars["tableName"])
+
+	tableNameResult := tableNameDto.ToModel()
+
+	if tableNameResult.IsErr() {
+		middleware.AttachError(w, tableNameResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid table name"))
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		panic("error reading body")
+	}
+
+	var tableRenameRequestDto dto.TableRenameRequestDto
+	json.Unmarshal(bodyBytes, &tableRenameRequestDto)
+
+	tableRenameRequestResult := tableRenameRequestDto.ToModel()
+
+	if tableRenameRequestResult.IsErr() {
+		middleware.AttachError(w, tableRenameRequestResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid request body"))
+		return
+	}
+
+	err = t.tableRenamer.RenameTable(
+		projectId,
+		tableNameResult.Unwrap(),
+		tableRenameRequestResult.Unwrap().NewName,
+	)
+
+	if err != nil {
+		middleware.AttachError(w, err)
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			w.WriteHeader(404)
+			w.Write([]byte("table not found"))
+			return
+		}
+
+		if err, ok := err.(errs.TableAlreadyExistsError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error renaming table"))
+		return
+	}
+
+	w.WriteHeader(200)
+}
+
+func (t *tableHandler) AlterField(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+
+	vars := mux.Vars(r)
+
+	tableNameDto := dto.TableNameDto(vars["tableName"])
+
+	tableNameResult := tableNameDto.ToModel()
+
+	if tableNameResult.IsErr() {
+		middleware.AttachError(w, tableNameResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid table name"))
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		panic("error reading body")
+	}
+
+	var fieldAlterationRequestDto dto.FieldAlterationRequestDto
+	json.Unmarshal(bodyBytes, &fieldAlterationRequestDto)
+
+	fieldAlterationRequestResult := fieldAlterationRequestDto.ToModel()
+
+	if fieldAlterationRequestResult.IsErr() {
+		middleware.AttachError(w, fieldAlterationRequestResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid request body"))
+		return
+	}
+
+	fieldAlterationResult := t.tableFieldAlterer.AlterField(
+		projectId,
+		tableNameResult.Unwrap(),
+		fieldAlterationRequestResult.Unwrap(),
+	)
+
+	if fieldAlterationResult.IsErr() {
+		err := fieldAlterationResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			w.WriteHeader(404)
+			w.Write([]byte("table not found"))
+			return
+		}
+
+		if _, ok := err.(errs.FieldNotFoundError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte("field not found"))
+			return
+		}
+
+		if err, ok := err.(errs.InvalidTableError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.InvalidFieldAlterationError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if err, ok := err.(errs.InvalidBackfillValueError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		// Entities written since they were checked couldn't be converted
+		if err, ok := err.(errs.FieldConversionError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error altering field"))
+		return
+	}
+
+	fieldAlteration := fieldAlterationResult.Unwrap()
+
+	resBodyBytes, _ := json.Marshal(dto.GetFieldAlterationDto(fieldAlteration))
+
+	w.Header().Set("content-type", "application/json")
+
+	// The report says which entities couldn't be converted
+	if !fieldAlteration.DryRun && !fieldAlteration.Applied {
+		w.WriteHeader(409)
+	}
+
+	w.Write(resBodyBytes)
+}
+
+func (t *tableHandler) MigrateTimeFields(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+
+	vars := mux.Vars(r)
+
+	tableNameDto := dto.TableNameDto(vars["tableName"])
+
+	tableNameResult := tableNameDto.ToModel()
+
+	if tableNameResult.IsErr() {
+		middleware.AttachError(w, tableNameResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid table name"))
+		return
+	}
+
+	migratedFieldsResult := t.tableTimeFieldMigrator.MigrateTimeFields(projectId, tableNameResult.Unwrap())
+
+	if migratedFieldsResult.IsErr() {
+		err := migratedFieldsResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			w.WriteHeader(404)
+			w.Write([]byte("table not found"))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error migrating time fields"))
+		return
+	}
+
+	timeFieldMigrationResponseDto := dto.GetTimeFieldMigrationResponseDto(migratedFieldsResult.Unwrap())
+
+	resBodyBytes, _ := json.Marshal(timeFieldMigrationResponseDto)
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resBodyBytes)
+}
+
+func (t *tableHandler) GetIndexes(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	tableIndexesResult := t.tableIndexGetter.GetTableIndexes(projectId, tableName)
+
+	if tableIndexesResult.IsErr() {
+		err := tableIndexesResult.UnwrapErr()
+
+		middleware.AttachError(w, err)
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			w.WriteHeader(404)
+			w.Write([]byte("table not found"))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error getting indexes"))
+		return
+	}
+
+	tableIndexesDto := dto.GetTableIndexesDto(tableIndexesResult.Unwrap())
+
+	resBodyBytes, _ := json.Marshal(tableIndexesDto)
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resBodyBytes)
+}
+
+func (t *tableHandler) AddIndex(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	bodyBytes, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		panic("error reading body")
+	}
+
+	var indexCreationRequestDto dto.IndexCreationRequestDto
+	json.Unmarshal(bodyBytes, &indexCreationRequestDto)
+
+	indexCreationRequestResult := indexCreationRequestDto.ToModel()
+
+	if indexCreationRequestResult.IsErr() {
+		middleware.AttachError(w, indexCreationRequestResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid request body"))
+		return
+	}
+
+	indexCreationRequest := indexCreationRequestResult.Unwrap()
+
+	err = t.tableIndexAdder.AddIndex(
+		projectId,
+		tableName,
+		indexCreationRequest.Name,
+		indexCreationRequest.Index,
+	)
+
+	if err != nil {
+		middleware.AttachError(w, err)
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			w.WriteHeader(404)
+			w.Write([]byte("table not found"))
+			return
+		}
+
+		if err, ok := err.(errs.InvalidTableIndexError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		if _, ok := err.(errs.IndexAlreadyExistsError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte("index already exists"))
+			return
+		}
+
+		// Existing entities already clash on the index's fields
+		if err, ok := err.(errs.UniqueViolationError); ok {
+			w.WriteHeader(409)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error creating index"))
+		return
+	}
+
+	w.WriteHeader(200)
+}
+
+func (t *tableHandler) DeleteIndex(w http.ResponseWriter, r *http.Request) {
+	projectId := ctx.GetRequestProjectId(r)
+	tableName := ctx.GetRequestTableName(r)
+
+	bodyBytes, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		panic("error reading body")
+	}
+
+	var indexDeletionRequestDto dto.IndexDeletionRequestDto
+	json.Unmarshal(bodyBytes, &indexDeletionRequestDto)
+
+	indexDeletionRequestResult := indexDeletionRequestDto.ToModel()
+
+	if indexDeletionRequestResult.IsErr() {
+		middleware.AttachError(w, indexDeletionRequestResult.UnwrapErr())
+		w.WriteHeader(400)
+		w.Write([]byte("invalid request body"))
+		return
+	}
+
+	err = t.tableIndexDeleter.DeleteIndex(
+		projectId,
+		tableName,
+		indexDeletionRequestResult.Unwrap().Name,
+	)
+
+	if err != nil {
+		middleware.AttachError(w, err)
+
+		if _, ok := err.(errs.TableNotFoundError); ok {
+			w.WriteHeader(404)
+			w.Write([]byte("table not found"))
+			return
+		}
+
+		if _, ok := err.(errs.IndexNotFoundError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte("index not found"))
+			return
+		}
+
+		if err, ok := err.(errs.InvalidTableIndexError); ok {
+			w.WriteHeader(400)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.WriteHeader(500)
+		w.Write([]byte("unexpected error deleting index"))
+		return
+	}
+
+	w.WriteHeader(200)
+}
@@ -24,6 +24,8 @@ type tableManager interface {
 		projectId model.ProjectId,
 		name model.TableName,
 		schema model.TableSchema,
+		indexes model.TableIndexes,
+		link bool,
 	) error
 	GetTableSchema(projectId model.ProjectId, name model.TableName) result.R[model.TableSchema]
 	GetTableSchemas(projectId model.ProjectId) result.R[model.TableSchemas]
@@ -40,16 +42,70 @@ type tableManager interface {
 		tableName model.TableName,
 		name model.FieldName,
 	) error
+	MigrateTimeFields(
+		projectId model.ProjectId,
+		tableName model.TableName,
+	) result.R[[]model.FieldName]
+	GetTableIndexes(
+		projectId model.ProjectId,
+		tableName model.TableName,
+	) result.R[model.TableIndexes]
+	AddIndex(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		name model.IndexName,
+		index model.TableIndex,
+	) error
+	DeleteIndex(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		name model.IndexName,
+	) error
+	RenameField(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		name model.FieldName,
+		newName model.FieldName,
+	) error
+	RenameTable(
+		projectId model.ProjectId,
+		name model.TableName,
+		newName model.TableName,
+	) error
+	AlterField(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		fieldAlterationRequest model.FieldAlterationRequest,
+	) result.R[model.FieldAlteration]
 }
 
 type entityManager interface {
-	GetEntity(projectId model.ProjectId, tableName model.TableName, id model.EntityId) result.R[model.Entity]
+	GetEntity(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		id model.EntityId,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
+	) result.R[model.Entity]
 	GetEntities(
 		projectId model.ProjectId,
 		tableName model.TableName,
 		entityFilter model.EntityFilter,
 		entityOrders model.EntityOrders,
 		paginationParams model.PaginationParams,
+		countType model.EntityCountType,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
+	) result.R[model.GetEntitiesResponse]
+	QueryEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		filterExpression model.EntityFilterExpression,
+		entityOrders model.EntityOrders,
+		paginationParams model.PaginationParams,
+		countType model.EntityCountType,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
 	) result.R[model.GetEntitiesResponse]
 	CreateEntityWithId(
 		projectId model.ProjectId,
@@ -67,6 +123,18 @@ type entityManager interface {
 		tableName model.TableName,
 		entities model.Entities,
 	) error
+	UpsertEntity(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		upsertKey model.UpsertKey,
+		entity model.Entity,
+	) result.R[model.UpsertedEntity]
+	UpsertEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		upsertKey model.UpsertKey,
+		entities model.Entities,
+	) result.R[[]model.UpsertedEntity]
 	UpdateEntity(
 		projectId model.ProjectId,
 		tableName model.TableName,
@@ -83,6 +151,54 @@ type entityManager interface {
 		tableName model.TableName,
 		entityFilter model.EntityFilter,
 	) result.R[uint]
+	AggregateEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		entityAggregationQuery model.EntityAggregationQuery,
+	) result.R[model.EntityAggregationResponse]
+	GetFieldValues(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		fieldName model.FieldName,
+		fieldValuesQuery model.FieldValuesQuery,
+	) result.R[model.GetFieldValuesResponse]
+	GetRelatedEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		id model.EntityId,
+		relatedTableName model.TableName,
+		via optional.O[model.FieldName],
+		entityFilter model.EntityFilter,
+		entityOrders model.EntityOrders,
+		paginationParams model.PaginationParams,
+		countType model.EntityCountType,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
+	) result.R[model.GetEntitiesResponse]
+	GetLinkedEntities(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		id model.EntityId,
+		linkTableName model.TableName,
+		paginationParams model.PaginationParams,
+		countType model.EntityCountType,
+		fieldProjection optional.O[model.FieldProjection],
+		entityExpansion model.EntityExpansion,
+	) result.R[model.GetEntitiesResponse]
+	CreateLink(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		id model.EntityId,
+		linkTableName model.TableName,
+		otherId model.EntityId,
+	) result.R[bool]
+	DeleteLink(
+		projectId model.ProjectId,
+		tableName model.TableName,
+		id model.EntityId,
+		linkTableName model.TableName,
+		otherId model.EntityId,
+	) error
 }
 
 type rateLimitManager interface {
@@ -107,6 +223,13 @@ func createHandler(
 		tableManager,
 		tableManager,
 		tableManager,
+		tableManager,
+		tableManager,
+		tableManager,
+		tableManager,
+		tableManager,
+		tableManager,
+		tableManager,
 	)
 	entityHandler := handler.NewEntityHandler(
 		entityManager,
@@ -114,6 +237,8 @@ func createHandler(
 		entityManager,
 		entityManager,
 		entityManager,
+		entityManager,
+		entityManager,
 	)
 	rateLimitHandler := handler.NewRateLimitHandler(rateLimitManager, rateLimitManager)
 
@@ -151,6 +276,11 @@ func createHandler(
 		tableHandler.PutTable,
 	).Methods("PUT")
 
+	adminTableRouter.HandleFunc(
+		"/migrateTimeFields",
+		tableHandler.MigrateTimeFields,
+	).Methods("POST")
+
 	rateLimitRouter := router.PathPrefix("/rateLimit").Subrouter()
 	rateLimitRouter.Use(projectIdMiddleware)
 	rateLimitRouter.Use(projectAuthMiddleware)
@@ -186,16 +316,56 @@ func createHandler(
 		tableHandler.AddField,
 	).Methods("POST")
 
+	tableRouter.HandleFunc(
+		"/{tableName}/renameField",
+		tableHandler.RenameField,
+	).Methods("POST")
+
+	tableRouter.HandleFunc(
+		"/{tableName}/rename",
+		tableHandler.RenameTable,
+	).Methods("POST")
+
+	tableRouter.HandleFunc(
+		"/{tableName}/alterField",
+		tableHandler.AlterField,
+	).Methods("POST")
+
 	tableRouter.HandleFunc(
 		"/{tableName}/deleteField",
 		tableHandler.DeleteField,
 	).Methods("POST")
 
+	tableRouter.HandleFunc(
+		"/{tableName}/indexes",
+		tableHandler.GetIndexes,
+	).Methods("GET")
+
+	tableRouter.HandleFunc(
+		"/{tableName}/addIndex",
+		tableHandler.AddIndex,
+	).Methods("POST")
+
+	tableRouter.HandleFunc(
+		"/{tableName}/deleteIndex",
+		tableHandler.DeleteIndex,
+	).Methods("POST")
+
 	tableRouter.HandleFunc(
 		"/{tableName}/totalEntityCount",
 		entityHandler.GetTotalEntityCount,
 	).Methods("GET")
 
+	tableRouter.HandleFunc(
+		"/{tableName}/aggregate",
+		entityHandler.GetEntityAggregation,
+	).Methods("GET")
+
+	tableRouter.HandleFunc(
+		"/{tableName}/fields/{fieldName}/values",
+		entityHandler.GetFieldValues,
+	).Methods("GET")
+
 	entityRouter := tableRouter.PathPrefix("/{tableName}/entities").Subrouter()
 
 	entityRouter.HandleFunc(
@@ -218,11 +388,22 @@ func createHandler(
 		entityHandler.GetEntities,
 	).Methods("GET")
 
+	// Registered before /{id} so that batch isn't taken as an id
+	entityRouter.HandleFunc(
+		"/batch",
+		entityHandler.UpsertEntityBatch,
+	).Methods("PUT")
+
 	entityRouter.HandleFunc(
 		"/{id}",
 		entityHandler.PutEntity,
 	).Methods("PUT")
 
+	entityRouter.HandleFunc(
+		"",
+		entityHandler.UpsertEntity,
+	).Methods("PUT")
+
 	entityRouter.HandleFunc(
 		"",
 		entityHandler.PostEntity,
@@ -233,6 +414,31 @@ func createHandler(
 		entityHandler.PostEntityBatch,
 	).Methods("POST")
 
+	entityRouter.HandleFunc(
+		"/query",
+		entityHandler.QueryEntities,
+	).Methods("POST")
+
+	entityRouter.HandleFunc(
+		"/{id}/related/{relatedTableName}",
+		entityHandler.GetRelatedEntities,
+	).Methods("GET")
+
+	entityRouter.HandleFunc(
+		"/{id}/links/{linkTableName}",
+		entityHandler.GetLinkedEntities,
+	).Methods("GET")
+
+	entityRouter.HandleFunc(
+		"/{id}/links/{linkTableName}/{otherId}",
+		entityHandler.PutLink,
+	).Methods("PUT")
+
+	entityRouter.HandleFunc(
+		"/{id}/links/{linkTableName}/{otherId}",
+		entityHandler.DeleteLink,
+	).Methods("DELETE")
+
 	return router
 }
 
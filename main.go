@@ -28,15 +28,24 @@ func main() {
 	postgresTableCreatorService := service.NewPostgresTableCreator(postgres)
 	postgresTableGetterService := service.NewPostgresTableFetcher(postgres)
 	postgresTableDeleterService := service.NewPostgresTableDeleter(postgres)
+	postgresTableRenamerService := service.NewPostgresTableRenamer(postgres)
 
 	postgresTableFieldAdderService := service.NewPostgresTableFieldAdder(postgres)
 	postgresTableFieldDeleterService := service.NewPostgresTableFieldDeleter(postgres)
+	postgresTableFieldRenamerService := service.NewPostgresTableFieldRenamer(postgres)
+	postgresTableFieldAltererService := service.NewPostgresTableFieldAlterer(postgres)
+	postgresTableTimeFieldMigratorService := service.NewPostgresTableTimeFieldMigrator(postgres)
+
+	postgresTableIndexFetcherService := service.NewPostgresTableIndexFetcher(postgres)
+	postgresTableIndexCreatorService := service.NewPostgresTableIndexCreator(postgres)
+	postgresTableIndexDeleterService := service.NewPostgresTableIndexDeleter(postgres)
 
 	postgresEntityFetcherService := service.NewPostgresEntityFetcher(postgres)
 	postgresEntityCreatorService := service.NewPostgresEntityCreator(postgres)
 	postgresEntityUpdaterService := service.NewPostgresEntityUpdater(postgres)
 	postgresEntityDeleterService := service.NewPostgresEntityDeleter(postgres)
 	postgresEntityCountService := service.NewPostgresEntityCount(postgres)
+	postgresEntityLinkerService := service.NewPostgresEntityLinker(postgres)
 
 	postgresProjectCreatorService := service.NewPostgresProjectCreator(postgres)
 	postgresProjectAuthInfoFetcherService := service.NewPostgresProjectAuthFetcher(postgres)
@@ -48,7 +57,14 @@ func main() {
 	partialEntityValidator := validation.NewPartialEntityValidator()
 	entityFilterValidator := validation.NewEntityFilterValidator()
 	entityOrderValidator := validation.NewEntityOrderValidator()
+	entityCursorValidator := validation.NewEntityCursorValidator()
+	fieldProjectionValidator := validation.NewFieldProjectionValidator()
+	entityAggregationValidator := validation.NewEntityAggregationValidator()
 	tableSchemaValidator := validation.NewTableSchemaValidator()
+	tableIndexValidator := validation.NewTableIndexValidator()
+	upsertKeyValidator := validation.NewUpsertKeyValidator()
+	entityExpansionValidator := validation.NewEntityExpansionValidator()
+	fieldAlterationValidator := validation.NewFieldAlterationValidator()
 
 	projectManager := app.NewProjectManager(&postgresProjectCreatorService, &postgresProjectAuthInfoFetcherService)
 	tableManager := app.NewTableManager(
@@ -58,6 +74,16 @@ func main() {
 		&postgresTableFieldAdderService,
 		&postgresTableFieldDeleterService,
 		&tableSchemaValidator,
+		&postgresTableTimeFieldMigratorService,
+		&entityValidator,
+		&postgresTableIndexFetcherService,
+		&postgresTableIndexCreatorService,
+		&postgresTableIndexDeleterService,
+		&tableIndexValidator,
+		&postgresTableFieldRenamerService,
+		&postgresTableRenamerService,
+		&postgresTableFieldAltererService,
+		&fieldAlterationValidator,
 	)
 	entityManager := app.NewEntityManager(
 		&postgresEntityFetcherService,
@@ -70,6 +96,14 @@ func main() {
 		&partialEntityValidator,
 		&entityFilterValidator,
 		&entityOrderValidator,
+		&entityCursorValidator,
+		&fieldProjectionValidator,
+		&postgresEntityCountService,
+		&entityAggregationValidator,
+		&upsertKeyValidator,
+		&entityExpansionValidator,
+		&postgresEntityLinkerService,
+		&tableManager,
 	)
 	rateLimitManager := app.NewRateLimitManager(
 		&redisRateLimitStoreService,
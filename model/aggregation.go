@@ -0,0 +1,195 @@
+package model
+
+import (
+	"crudly/util/optional"
+	"fmt"
+	"time"
+)
+
+type EntityAggregationType uint
+
+const (
+	EntityAggregationTypeCount EntityAggregationType = 0
+	EntityAggregationTypeSum   EntityAggregationType = 1
+	EntityAggregationTypeAvg   EntityAggregationType = 2
+	EntityAggregationTypeMin   EntityAggregationType = 3
+	EntityAggregationTypeMax   EntityAggregationType = 4
+)
+
+func (e EntityAggregationType) String() string {
+	switch e {
+	case EntityAggregationTypeCount:
+		return "count"
+	case EntityAggregationTypeSum:
+		return "sum"
+	case EntityAggregationTypeAvg:
+		return "avg"
+	case EntityAggregationTypeMin:
+		return "min"
+	case EntityAggregationTypeMax:
+		return "max"
+	}
+	panic("invalid entity aggregation type has entered the system in stringify!")
+}
+
+// An aggregate computed over each group. FieldName is None only for a count
+// of all rows
+type EntityAggregation struct {
+	Type      EntityAggregationType
+	FieldName optional.O[FieldName]
+}
+
+func (e EntityAggregation) String() string {
+	if e.FieldName.IsNone() {
+		return e.Type.String()
+	}
+
+	return fmt.Sprintf("%s(%s)", e.Type.String(), e.FieldName.Unwrap().String())
+}
+
+type EntityAggregations []EntityAggregation
+
+type HistogramUnit uint
+
+const (
+	HistogramUnitMinute HistogramUnit = 0
+	HistogramUnitHour   HistogramUnit = 1
+	HistogramUnitDay    HistogramUnit = 2
+	HistogramUnitWeek   HistogramUnit = 3
+	HistogramUnitMonth  HistogramUnit = 4
+)
+
+func (h HistogramUnit) String() string {
+	switch h {
+	case HistogramUnitMinute:
+		return "minute"
+	case HistogramUnitHour:
+		return "hour"
+	case HistogramUnitDay:
+		return "day"
+	case HistogramUnitWeek:
+		return "week"
+	case HistogramUnitMonth:
+		return "month"
+	}
+	panic("invalid histogram unit has entered the system in stringify!")
+}
+
+// Buckets a time field either by truncating to a calendar unit or into fixed
+// width intervals. Exactly one of Unit and Interval is set. Buckets are
+// aligned to the wall clock of Timezone when it's given, otherwise to UTC
+type EntityHistogram struct {
+	FieldName FieldName
+	Unit      optional.O[HistogramUnit]
+	Interval  optional.O[time.Duration]
+	Timezone  optional.O[string]
+}
+
+// Finds the tightest bounds that the top level of the filter puts on the
+// histogram's field. Bounds nested under an or/not can't be relied on so are
+// ignored
+func (h EntityHistogram) GetBounds(filterExpression EntityFilterExpression) (optional.O[time.Time], optional.O[time.Time]) {
+	lowerBound := optional.None[time.Time]()
+	upperBound := optional.None[time.Time]()
+
+	fieldExpressions := []EntityFilterExpression{filterExpression}
+
+	if filterExpression.Type == EntityFilterExpressionTypeAnd {
+		fieldExpressions = filterExpression.Operands
+	}
+
+	for _, fieldExpression := range fieldExpressions {
+		if fieldExpression.Type != EntityFilterExpressionTypeField || fieldExpression.FieldName != h.FieldName {
+			continue
+		}
+
+		comparator, ok := fieldExpression.FieldFilter.Comparator.(time.Time)
+
+		if !ok {
+			continue
+		}
+
+		switch fieldExpression.FieldFilter.Type {
+		case FieldFilterTypeGreaterThan, FieldFilterTypeGreaterThanEq:
+			if lowerBound.IsNone() || comparator.After(lowerBound.Unwrap()) {
+				lowerBound = optional.Some(comparator)
+			}
+		case FieldFilterTypeLessThan, FieldFilterTypeLessThanEq:
+			// An exclusive upper bound that lands on the start of a bucket
+			// shouldn't produce that bucket
+			if fieldExpression.FieldFilter.Type == FieldFilterTypeLessThan {
+				comparator = comparator.Add(-time.Microsecond)
+			}
+
+			if upperBound.IsNone() || comparator.Before(upperBound.Unwrap()) {
+				upperBound = optional.Some(comparator)
+			}
+		}
+	}
+
+	return lowerBound, upperBound
+}
+
+const MaxHistogramBuckets = 10000
+
+type EntityAggregationQuery struct {
+	Filter       EntityFilter
+	GroupBy      []FieldName
+	Aggregations EntityAggregations
+	Histogram    optional.O[EntityHistogram]
+}
+
+// Values holds one entry per requested aggregation, in the same order. For
+// histograms the start of each bucket is held in Group under the bucketed
+// field's name
+type EntityAggregationResult struct {
+	Group  map[FieldName]Field
+	Values []Field
+}
+
+type EntityAggregationResults []EntityAggregationResult
+
+type EntityAggregationResponse struct {
+	GroupBy      []FieldName
+	Aggregations EntityAggregations
+	Histogram    optional.O[EntityHistogram]
+	Results      EntityAggregationResults
+}
+
+type FieldValuesOrderType uint
+
+const (
+	FieldValuesOrderTypeCount FieldValuesOrderType = 0
+	FieldValuesOrderTypeValue FieldValuesOrderType = 1
+)
+
+func (f FieldValuesOrderType) String() string {
+	switch f {
+	case FieldValuesOrderTypeCount:
+		return "count"
+	case FieldValuesOrderTypeValue:
+		return "value"
+	}
+	panic("invalid field values order type has entered the system in stringify!")
+}
+
+// Ordering by count puts the most common values first, ties (and ordering by
+// value) are broken by the value ascending
+type FieldValuesQuery struct {
+	Filter           EntityFilter
+	Order            FieldValuesOrderType
+	PaginationParams PaginationParams
+}
+
+type FieldValueCount struct {
+	Value Field
+	Count uint
+}
+
+type FieldValueCounts []FieldValueCount
+
+type GetFieldValuesResponse struct {
+	Values FieldValueCounts
+	Limit  uint
+	Offset uint
+}
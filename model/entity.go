@@ -1,6 +1,8 @@
 package model
 
 import (
+	"crudly/util/optional"
+
 	"github.com/google/uuid"
 )
 
@@ -12,15 +14,136 @@ func (e EntityId) String() string {
 
 type Field any
 
+// An exact decimal number kept in its canonical text form (e.g. "12.50") so
+// that it never passes through a float
+type Decimal string
+
+func (d Decimal) String() string {
+	return string(d)
+}
+
+// A JSON object or array kept in its encoded form, so that numbers nested
+// inside it keep their exact digits
+type Json string
+
+func (j Json) String() string {
+	return string(j)
+}
+
+// A calendar date with no time or timezone, in the form 2006-01-02
+type Date string
+
+func (d Date) String() string {
+	return string(d)
+}
+
+// An ISO 8601 duration, e.g. P1DT2H. Months, days and smaller units are
+// kept apart, as a month or a day doesn't always have the same length
+type Duration string
+
+func (d Duration) String() string {
+	return string(d)
+}
+
+type ArrayUpdateType uint
+
+const (
+	ArrayUpdateTypeAppend ArrayUpdateType = 0
+	ArrayUpdateTypeRemove ArrayUpdateType = 1
+)
+
+func (a ArrayUpdateType) String() string {
+	switch a {
+	case ArrayUpdateTypeAppend:
+		return "append"
+	case ArrayUpdateTypeRemove:
+		return "remove"
+	}
+	panic("invalid array update type has entered the system in stringify!")
+}
+
+// A change to an array field made in place, so that concurrent updates to
+// the same array don't overwrite each other. Elements is a slice of the
+// array's element type
+type ArrayUpdate struct {
+	Type     ArrayUpdateType
+	Elements any
+}
+
 type Entity map[FieldName]Field
 
 type Entities []Entity
 
 type PartialEntity map[FieldName]Field
 
+// The fields that identify an existing entity when upserting. They have to
+// match a unique field or a unique index that isn't partial
+type UpsertKey []FieldName
+
+type UpsertedEntity struct {
+	Entity  Entity
+	Created bool
+}
+
+type EntityCountType uint
+
+const (
+	EntityCountTypeExact     EntityCountType = 0
+	EntityCountTypeEstimated EntityCountType = 1
+	EntityCountTypeNone      EntityCountType = 2
+)
+
+func (e EntityCountType) String() string {
+	switch e {
+	case EntityCountTypeExact:
+		return "exact"
+	case EntityCountTypeEstimated:
+		return "estimated"
+	case EntityCountTypeNone:
+		return "none"
+	}
+	panic("invalid entity count type has entered the system in stringify!")
+}
+
 type GetEntitiesResponse struct {
 	Entities   Entities
+	CountType  EntityCountType
 	TotalCount uint
 	Limit      uint
 	Offset     uint
+	Cursor     optional.O[EntityCursor]
+	NextCursor optional.O[EntityCursor]
 }
+
+type EntityQuery struct {
+	FilterExpression EntityFilterExpression
+	Orders           EntityOrders
+	PaginationParams PaginationParams
+	CountType        EntityCountType
+	FieldProjection  optional.O[FieldProjection]
+	Expansion        EntityExpansion
+}
+
+// The fields to return for each entity. The id is always returned
+type FieldProjection []FieldName
+
+// A path through reference fields whose entities are embedded in place of
+// their ids, e.g. customer.company. Every field along the path is expanded
+type ExpansionPath []FieldName
+
+type EntityExpansion []ExpansionPath
+
+// Expansion paths are kept short as every field along them takes a query
+const MaxExpansionDepth = 3
+
+// A reference field to embed the entities of, resolved against the schema of
+// the referenced table along with the fields to return for those entities
+type FieldExpansion struct {
+	FieldName       FieldName
+	TableName       TableName
+	TableSchema     TableSchema
+	FieldProjection optional.O[FieldProjection]
+	Expansions      FieldExpansions
+}
+
+type FieldExpansions []FieldExpansion
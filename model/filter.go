@@ -1,5 +1,7 @@
 package model
 
+import "sort"
+
 type FieldFilterType uint
 
 const (
@@ -8,6 +10,14 @@ const (
 	FieldFilterTypeGreaterThanEq FieldFilterType = 2
 	FieldFilterTypeLessThan      FieldFilterType = 3
 	FieldFilterTypeLessThanEq    FieldFilterType = 4
+	FieldFilterTypeNotEquals     FieldFilterType = 5
+	FieldFilterTypeIn            FieldFilterType = 6
+	FieldFilterTypeIsNull        FieldFilterType = 7
+	FieldFilterTypeIsNotNull     FieldFilterType = 8
+	FieldFilterTypeContains      FieldFilterType = 9
+	FieldFilterTypeStartsWith    FieldFilterType = 10
+	FieldFilterTypeEndsWith      FieldFilterType = 11
+	FieldFilterTypeContainsAny   FieldFilterType = 12
 )
 
 func (f FieldFilterType) String() string {
@@ -22,13 +32,139 @@ func (f FieldFilterType) String() string {
 		return "<"
 	case FieldFilterTypeLessThanEq:
 		return "<="
+	case FieldFilterTypeNotEquals:
+		return "!="
+	case FieldFilterTypeIn:
+		return "in"
+	case FieldFilterTypeIsNull:
+		return "is null"
+	case FieldFilterTypeIsNotNull:
+		return "is not null"
+	case FieldFilterTypeContains:
+		return "contains"
+	case FieldFilterTypeStartsWith:
+		return "startsWith"
+	case FieldFilterTypeEndsWith:
+		return "endsWith"
+	case FieldFilterTypeContainsAny:
+		return "containsAny"
 	}
 	panic("invalid field filter type has entered the system in stringify!")
 }
 
+// Comparator is a single value for most filter types, a list of values for
+// FieldFilterTypeIn and nil for the null checks. Path is only set when
+// filtering on a value nested inside a json field, and Length only when
+// filtering on the number of elements in an array field
 type FieldFilter struct {
 	Type       FieldFilterType
+	Path       []string
+	Length     bool
 	Comparator interface{}
 }
 
-type EntityFilter map[FieldName]FieldFilter
+// Filters on the same field are AND-ed together, allowing for range queries
+type EntityFilter map[FieldName][]FieldFilter
+
+type EntityFilterExpressionType uint
+
+const (
+	EntityFilterExpressionTypeField EntityFilterExpressionType = 0
+	EntityFilterExpressionTypeAnd   EntityFilterExpressionType = 1
+	EntityFilterExpressionTypeOr    EntityFilterExpressionType = 2
+	EntityFilterExpressionTypeNot   EntityFilterExpressionType = 3
+)
+
+func (e EntityFilterExpressionType) String() string {
+	switch e {
+	case EntityFilterExpressionTypeField:
+		return "field"
+	case EntityFilterExpressionTypeAnd:
+		return "and"
+	case EntityFilterExpressionTypeOr:
+		return "or"
+	case EntityFilterExpressionTypeNot:
+		return "not"
+	}
+	panic("invalid entity filter expression type has entered the system in stringify!")
+}
+
+// A boolean tree of field filters. Field nodes use FieldName and FieldFilter,
+// and/or nodes combine any number of Operands and not nodes negate exactly one
+type EntityFilterExpression struct {
+	Type        EntityFilterExpressionType
+	FieldName   FieldName
+	FieldFilter FieldFilter
+	Operands    []EntityFilterExpression
+}
+
+// An and node with no operands, matching every entity
+func EmptyEntityFilterExpression() EntityFilterExpression {
+	return EntityFilterExpression{
+		Type:     EntityFilterExpressionTypeAnd,
+		Operands: []EntityFilterExpression{},
+	}
+}
+
+func (e EntityFilterExpression) IsEmpty() bool {
+	return e.Type == EntityFilterExpressionTypeAnd && len(e.Operands) == 0
+}
+
+func (e EntityFilterExpression) ReferencesField(fieldName FieldName) bool {
+	if e.Type == EntityFilterExpressionTypeField {
+		return e.FieldName == fieldName
+	}
+
+	for _, operand := range e.Operands {
+		if operand.ReferencesField(fieldName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns a copy of the expression with every filter on one field moved onto
+// another
+func (e EntityFilterExpression) RenameField(fieldName FieldName, newFieldName FieldName) EntityFilterExpression {
+	if e.Type == EntityFilterExpressionTypeField && e.FieldName == fieldName {
+		e.FieldName = newFieldName
+	}
+
+	operands := make([]EntityFilterExpression, len(e.Operands))
+
+	for i, operand := range e.Operands {
+		operands[i] = operand.RenameField(fieldName, newFieldName)
+	}
+
+	e.Operands = operands
+
+	return e
+}
+
+// Lowers the flat filter into an and node over every field filter
+func (e EntityFilter) ToExpression() EntityFilterExpression {
+	expression := EmptyEntityFilterExpression()
+
+	fieldNames := make([]FieldName, 0, len(e))
+
+	for fieldName := range e {
+		fieldNames = append(fieldNames, fieldName)
+	}
+
+	sort.Slice(fieldNames, func(i, j int) bool {
+		return fieldNames[i] < fieldNames[j]
+	})
+
+	for _, fieldName := range fieldNames {
+		for _, fieldFilter := range e[fieldName] {
+			expression.Operands = append(expression.Operands, EntityFilterExpression{
+				Type:        EntityFilterExpressionTypeField,
+				FieldName:   fieldName,
+				FieldFilter: fieldFilter,
+			})
+		}
+	}
+
+	return expression
+}
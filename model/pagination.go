@@ -1,6 +1,9 @@
 package model
 
-import "fmt"
+import (
+	"crudly/util/optional"
+	"fmt"
+)
 
 type PaginationLimit uint
 
@@ -18,7 +21,19 @@ func (p PaginationOffset) String() string {
 
 const DefaultPaginationOffset PaginationOffset = PaginationOffset(0)
 
+// The value of a single order key on the last entity of a page
+type EntityCursorKey struct {
+	FieldName FieldName
+	Type      FieldOrderType
+	Value     Field
+}
+
+// Identifies a position in an ordered list of entities, with one key per
+// entity order (including the trailing id order)
+type EntityCursor []EntityCursorKey
+
 type PaginationParams struct {
 	Limit  PaginationLimit
 	Offset PaginationOffset
+	Cursor optional.O[EntityCursor]
 }
@@ -2,17 +2,28 @@ package model
 
 import (
 	"crudly/util/optional"
+	"time"
 )
 
 type FieldType uint8
 
 const (
-	FieldTypeId      FieldType = 0
-	FieldTypeInteger FieldType = 1
-	FieldTypeString  FieldType = 2
-	FieldTypeBoolean FieldType = 3
-	FieldTypeTime    FieldType = 4
-	FieldTypeEnum    FieldType = 5
+	FieldTypeId       FieldType = 0
+	FieldTypeInteger  FieldType = 1
+	FieldTypeString   FieldType = 2
+	FieldTypeBoolean  FieldType = 3
+	FieldTypeTime     FieldType = 4
+	FieldTypeEnum     FieldType = 5
+	FieldTypeFloat    FieldType = 6
+	FieldTypeDecimal  FieldType = 7
+	FieldTypeBigInt   FieldType = 8
+	FieldTypeJson     FieldType = 9
+	FieldTypeArray    FieldType = 10
+	FieldTypeDate     FieldType = 11
+	FieldTypeDuration FieldType = 12
+	// A uuid holding the id of an entity in another table of the project,
+	// kept consistent by a foreign key
+	FieldTypeReference FieldType = 13
 )
 
 func (f FieldType) String() string {
@@ -29,6 +40,22 @@ func (f FieldType) String() string {
 		return "time"
 	case FieldTypeEnum:
 		return "enum"
+	case FieldTypeFloat:
+		return "float"
+	case FieldTypeDecimal:
+		return "decimal"
+	case FieldTypeBigInt:
+		return "bigint"
+	case FieldTypeJson:
+		return "json"
+	case FieldTypeArray:
+		return "array"
+	case FieldTypeDate:
+		return "date"
+	case FieldTypeDuration:
+		return "duration"
+	case FieldTypeReference:
+		return "reference"
 	}
 	panic("invalid field type has entered the system in stringify!")
 }
@@ -38,6 +65,80 @@ type FieldDefinition struct {
 	Values     optional.O[[]string]
 	IsOptional bool
 	PrimaryKey bool
+	Unique     bool
+	// Only set for decimals. The total number of significant digits and the
+	// number of those after the decimal point
+	Precision optional.O[uint]
+	Scale     optional.O[uint]
+	// Only set for arrays. The type of every element, with Values holding the
+	// allowed values when the elements are enums
+	ElementType optional.O[FieldType]
+	// Only set for strings. Lengths are in characters and Pattern is a Go
+	// regular expression that must match somewhere in the value
+	MinLength optional.O[uint]
+	MaxLength optional.O[uint]
+	Pattern   optional.O[string]
+	Format    optional.O[StringFormat]
+	// Only set for integers, bigints, floats and decimals. Inclusive bounds,
+	// kept exact as decimals
+	Min optional.O[Decimal]
+	Max optional.O[Decimal]
+	// Only set for times. Inclusive bounds
+	NotBefore optional.O[time.Time]
+	NotAfter  optional.O[time.Time]
+	// Only set for references. The table holding the referenced entities and
+	// what happens to the referencing entities when one is deleted
+	References optional.O[TableName]
+	OnDelete   optional.O[ReferenceOnDelete]
+}
+
+type ReferenceOnDelete uint
+
+const (
+	ReferenceOnDeleteRestrict ReferenceOnDelete = 0
+	ReferenceOnDeleteCascade  ReferenceOnDelete = 1
+	ReferenceOnDeleteSetNull  ReferenceOnDelete = 2
+)
+
+func (r ReferenceOnDelete) String() string {
+	switch r {
+	case ReferenceOnDeleteRestrict:
+		return "restrict"
+	case ReferenceOnDeleteCascade:
+		return "cascade"
+	case ReferenceOnDeleteSetNull:
+		return "setNull"
+	}
+	panic("invalid reference on delete has entered the system in stringify!")
+}
+
+type StringFormat uint
+
+const (
+	StringFormatEmail    StringFormat = 0
+	StringFormatUrl      StringFormat = 1
+	StringFormatUuid     StringFormat = 2
+	StringFormatHostname StringFormat = 3
+	StringFormatIpv4     StringFormat = 4
+	StringFormatIpv6     StringFormat = 5
+)
+
+func (s StringFormat) String() string {
+	switch s {
+	case StringFormatEmail:
+		return "email"
+	case StringFormatUrl:
+		return "url"
+	case StringFormatUuid:
+		return "uuid"
+	case StringFormatHostname:
+		return "hostname"
+	case StringFormatIpv4:
+		return "ipv4"
+	case StringFormatIpv6:
+		return "ipv6"
+	}
+	panic("invalid string format has entered the system in stringify!")
 }
 
 type FieldName string
@@ -48,6 +149,12 @@ func (f FieldName) String() string {
 
 type TableSchema map[FieldName]FieldDefinition
 
+// The two references of a link table, as seen from one of the tables it links
+type LinkFields struct {
+	Own   FieldName
+	Other FieldName
+}
+
 type TableName string
 
 func (t TableName) String() string {
@@ -56,6 +163,14 @@ func (t TableName) String() string {
 
 type TableSchemas map[TableName]TableSchema
 
+// Link is set to declare a link table, whose schema has to be exactly two
+// required references
+type TableCreationRequest struct {
+	Schema  TableSchema
+	Indexes TableIndexes
+	Link    bool
+}
+
 type FieldCreationRequest struct {
 	Name         FieldName
 	Definition   FieldDefinition
@@ -65,3 +180,135 @@ type FieldCreationRequest struct {
 type FieldDeletionRequest struct {
 	Name FieldName
 }
+
+// Nulls are replaced with the backfill value when one is given. A dry run
+// only checks which entities can be converted
+type FieldAlterationRequest struct {
+	Name          FieldName
+	Definition    FieldDefinition
+	BackfillValue optional.O[any]
+	DryRun        bool
+}
+
+// A field's value in one entity, along with its text, which is what values
+// are converted from when a field's type changes. Text is only missing for
+// nulls
+type StoredFieldValue struct {
+	Id    EntityId
+	Value any
+	Text  optional.O[string]
+}
+
+type FieldConversionFailure struct {
+	Id    EntityId
+	Error string
+}
+
+// Only the first failures are reported, along with how many there were
+const MaxFieldConversionFailures = 100
+
+type FieldAlteration struct {
+	DryRun      bool
+	Applied     bool
+	EntityCount uint
+	FailedCount uint
+	Failures    []FieldConversionFailure
+}
+
+type FieldRenameRequest struct {
+	Name    FieldName
+	NewName FieldName
+}
+
+type TableRenameRequest struct {
+	NewName TableName
+}
+
+type IndexName string
+
+func (i IndexName) String() string {
+	return string(i)
+}
+
+// Indexes are btree indexes over Fields in order. Unique indexes double as
+// composite unique keys, and a Filter makes the index partial so it only
+// covers, and for unique indexes only constrains, the entities matching it.
+// Link is only ever set on the index a link table is given when it's created
+type TableIndex struct {
+	Fields []FieldName
+	Unique bool
+	Filter optional.O[EntityFilterExpression]
+	Link   bool
+}
+
+func (t TableIndex) ReferencesField(fieldName FieldName) bool {
+	for _, indexFieldName := range t.Fields {
+		if indexFieldName == fieldName {
+			return true
+		}
+	}
+
+	return t.Filter.IsSome() && t.Filter.Unwrap().ReferencesField(fieldName)
+}
+
+func (t TableIndex) RenameField(fieldName FieldName, newFieldName FieldName) TableIndex {
+	fields := make([]FieldName, len(t.Fields))
+
+	for i, indexFieldName := range t.Fields {
+		fields[i] = indexFieldName
+
+		if indexFieldName == fieldName {
+			fields[i] = newFieldName
+		}
+	}
+
+	t.Fields = fields
+
+	if t.Filter.IsSome() {
+		t.Filter = optional.Some(t.Filter.Unwrap().RenameField(fieldName, newFieldName))
+	}
+
+	return t
+}
+
+type TableIndexes map[IndexName]TableIndex
+
+// Link tables are given a unique index under this name when they're created,
+// so that each pair of entities is only linked once
+const LinkIndexName IndexName = "link"
+
+// Link tables hold many to many relations, with a row for each linked pair
+// of entities. Their link index records the two references, which stay the
+// link fields whatever else is added to the table later. The two reference
+// different tables
+func (t TableIndexes) GetLinkFields(schema TableSchema, tableName TableName) optional.O[LinkFields] {
+	linkIndex, ok := t[LinkIndexName]
+
+	if !ok || !linkIndex.Link || len(linkIndex.Fields) != 2 {
+		return optional.None[LinkFields]()
+	}
+
+	fieldNames := linkIndex.Fields
+	isFirstOwn := schema[fieldNames[0]].References == optional.Some(tableName)
+	isSecondOwn := schema[fieldNames[1]].References == optional.Some(tableName)
+
+	// Either not linked to the table, or linked to it on both sides
+	if isFirstOwn == isSecondOwn {
+		return optional.None[LinkFields]()
+	}
+
+	if isFirstOwn {
+		return optional.Some(LinkFields{Own: fieldNames[0], Other: fieldNames[1]})
+	}
+
+	return optional.Some(LinkFields{Own: fieldNames[1], Other: fieldNames[0]})
+}
+
+type IndexCreationRequest struct {
+	Name  IndexName
+	Index TableIndex
+}
+
+type IndexDeletionRequest struct {
+	Name IndexName
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"crudly/util/optional"
+	"reflect"
+	"testing"
+)
+
+func TestGetLinkFields(t *testing.T) {
+	schema := TableSchema{
+		"post": {Type: FieldTypeReference, References: optional.Some[TableName]("posts")},
+		"tag":  {Type: FieldTypeReference, References: optional.Some[TableName]("tags")},
+		// Only used by a link index over two references to the same table
+		"parentPost": {Type: FieldTypeReference, References: optional.Some[TableName]("posts")},
+	}
+
+	linkIndex := TableIndex{Fields: []FieldName{"post", "tag"}, Unique: true, Link: true}
+
+	tests := []struct {
+		name               string
+		indexes            TableIndexes
+		tableName          TableName
+		expectedLinkFields optional.O[LinkFields]
+	}{
+		{
+			name:               "own field first",
+			indexes:            TableIndexes{LinkIndexName: linkIndex},
+			tableName:          "posts",
+			expectedLinkFields: optional.Some(LinkFields{Own: "post", Other: "tag"}),
+		},
+		{
+			name:               "own field second",
+			indexes:            TableIndexes{LinkIndexName: linkIndex},
+			tableName:          "tags",
+			expectedLinkFields: optional.Some(LinkFields{Own: "tag", Other: "post"}),
+		},
+		{
+			name:               "not a linked table",
+			indexes:            TableIndexes{LinkIndexName: linkIndex},
+			tableName:          "users",
+			expectedLinkFields: optional.None[LinkFields](),
+		},
+		{
+			name: "a user index named link",
+			indexes: TableIndexes{
+				LinkIndexName: {Fields: []FieldName{"post", "tag"}, Unique: true},
+			},
+			tableName:          "posts",
+			expectedLinkFields: optional.None[LinkFields](),
+		},
+		{
+			name: "linked to the same table on both sides",
+			indexes: TableIndexes{
+				LinkIndexName: {Fields: []FieldName{"post", "parentPost"}, Unique: true, Link: true},
+			},
+			tableName:          "posts",
+			expectedLinkFields: optional.None[LinkFields](),
+		},
+		{
+			name:               "no link index",
+			indexes:            TableIndexes{},
+			tableName:          "posts",
+			expectedLinkFields: optional.None[LinkFields](),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			linkFields := test.indexes.GetLinkFields(schema, test.tableName)
+
+			if !reflect.DeepEqual(linkFields, test.expectedLinkFields) {
+				t.Errorf("unexpected link fields:\n got: %+v\nwant: %+v", linkFields, test.expectedLinkFields)
+			}
+		})
+	}
+}
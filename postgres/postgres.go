@@ -10,7 +10,10 @@ func NewPostgres(config config.Config) (*sql.DB, error) {
 	return sql.Open(
 		"postgres",
 		fmt.Sprintf(
-			"sslmode=%s dbname=%s host=%s port=%d user=%s password=%s",
+			// Pinning the session timezone to UTC keeps timestamptz output,
+			// date_trunc and timestamp casts independent of the server config,
+			// and the interval style makes intervals come out as ISO 8601
+			"sslmode=%s dbname=%s host=%s port=%d user=%s password=%s timezone=UTC intervalstyle=iso_8601",
 			config.PostgresSslMode,
 			config.PostgresDatabase,
 			config.PostgresHost,
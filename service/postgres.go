@@ -3,10 +3,16 @@ package service
 import (
 	"crudly/model"
 	"crudly/util/result"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func getPostgresTableName(projectId model.ProjectId, tableName model.TableName) string {
@@ -17,9 +23,96 @@ func getPostgresSchemaTableName(projectId model.ProjectId) string {
 	return projectId.String() + "-tables"
 }
 
-func getPostgresDatatype(fieldType model.FieldType) string {
-	switch fieldType {
-	case model.FieldTypeId:
+func getPostgresIndexTableName(projectId model.ProjectId) string {
+	return projectId.String() + "-indexes"
+}
+
+// Index names share one namespace across every table and are cut off at 63
+// bytes, so rather than being built from the table name they're hashed from it
+func getPostgresIndexName(projectId model.ProjectId, tableName model.TableName, indexName model.IndexName) string {
+	hash := sha256.Sum256([]byte(getPostgresTableName(projectId, tableName) + "\x00" + indexName.String()))
+
+	return "index-" + hex.EncodeToString(hash[:20])
+}
+
+const postgresUniqueViolationCode = "23505"
+
+const postgresForeignKeyViolationCode = "23503"
+
+const postgresUndefinedTableCode = "42P01"
+
+const postgresInvalidColumnReferenceCode = "42P10"
+
+func isPostgresError(err error, code pq.ErrorCode) bool {
+	pqErr, ok := err.(*pq.Error)
+
+	return ok && pqErr.Code == code
+}
+
+// Unique and foreign key violations only name the constraint that was
+// violated, so the fields are read from the detail instead, e.g.
+// Key (a, "b c")=(1, 2) already exists.
+func getPostgresKeyViolationFields(err error) []string {
+	pqErr, ok := err.(*pq.Error)
+
+	if !ok || !strings.HasPrefix(pqErr.Detail, "Key (") {
+		return []string{}
+	}
+
+	detail := pqErr.Detail[len("Key ("):]
+	fieldNames := []string{}
+
+	for len(detail) > 0 {
+		fieldName := strings.Builder{}
+
+		if detail[0] == '"' {
+			// Quotes inside quoted identifiers are doubled
+			detail = detail[1:]
+
+			for {
+				end := strings.IndexByte(detail, '"')
+
+				if end == -1 {
+					return []string{}
+				}
+
+				fieldName.WriteString(detail[:end])
+				detail = detail[end+1:]
+
+				if !strings.HasPrefix(detail, "\"") {
+					break
+				}
+
+				fieldName.WriteByte('"')
+				detail = detail[1:]
+			}
+		} else {
+			end := strings.IndexAny(detail, ",)")
+
+			if end == -1 {
+				return []string{}
+			}
+
+			fieldName.WriteString(detail[:end])
+			detail = detail[end:]
+		}
+
+		fieldNames = append(fieldNames, fieldName.String())
+
+		if strings.HasPrefix(detail, ", ") {
+			detail = detail[len(", "):]
+			continue
+		}
+
+		break
+	}
+
+	return fieldNames
+}
+
+func getPostgresDatatype(fieldDefinition model.FieldDefinition) string {
+	switch fieldDefinition.Type {
+	case model.FieldTypeId, model.FieldTypeReference:
 		return "uuid"
 	case model.FieldTypeBoolean:
 		return "boolean"
@@ -28,32 +121,238 @@ func getPostgresDatatype(fieldType model.FieldType) string {
 	case model.FieldTypeString:
 		return "varchar"
 	case model.FieldTypeTime:
-		return "timestamp"
+		return "timestamptz"
 	case model.FieldTypeEnum:
 		return "varchar"
+	case model.FieldTypeBigInt:
+		return "bigint"
+	case model.FieldTypeJson:
+		return "jsonb"
+	case model.FieldTypeDate:
+		return "date"
+	case model.FieldTypeDuration:
+		return "interval"
+	case model.FieldTypeArray:
+		elementDefinition := model.FieldDefinition{Type: fieldDefinition.ElementType.Unwrap()}
+
+		return getPostgresDatatype(elementDefinition) + "[]"
+	case model.FieldTypeFloat:
+		return "double precision"
+	case model.FieldTypeDecimal:
+		if fieldDefinition.Precision.IsNone() {
+			return "numeric"
+		}
+
+		if fieldDefinition.Scale.IsNone() {
+			return fmt.Sprintf("numeric(%d)", fieldDefinition.Precision.Unwrap())
+		}
+
+		return fmt.Sprintf("numeric(%d, %d)", fieldDefinition.Precision.Unwrap(), fieldDefinition.Scale.Unwrap())
+	}
+	panic(fmt.Sprintf("invalid field type has entered the system: %+v", fieldDefinition.Type))
+}
+
+// Mirrors the constraints on a field that postgres can check in the same
+// way, so that writes made directly to the database are held to them too.
+// Patterns aren't mirrored as postgres regular expressions differ from Go's,
+// and nor are the email, url and hostname formats
+func writePostgresFieldCheck(query *postgresQuery, fieldName model.FieldName, fieldDefinition model.FieldDefinition) {
+	conditions := getPostgresFieldCheckConditions(fieldName, fieldDefinition)
+
+	if len(conditions) == 0 {
+		return
+	}
+
+	query.
+		Write(" CONSTRAINT ").
+		WriteIdentifier(getPostgresFieldCheckName(fieldName)).
+		Write(" CHECK (" + strings.Join(conditions, " AND ") + ")")
+}
+
+func getPostgresFieldCheckConditions(fieldName model.FieldName, fieldDefinition model.FieldDefinition) []string {
+	conditions := []string{}
+	column := pq.QuoteIdentifier(fieldName.String())
+
+	if fieldDefinition.MinLength.IsSome() {
+		conditions = append(conditions, fmt.Sprintf("char_length(%s) >= %d", column, fieldDefinition.MinLength.Unwrap()))
+	}
+
+	if fieldDefinition.MaxLength.IsSome() {
+		conditions = append(conditions, fmt.Sprintf("char_length(%s) <= %d", column, fieldDefinition.MaxLength.Unwrap()))
+	}
+
+	if fieldDefinition.Format.IsSome() {
+		switch fieldDefinition.Format.Unwrap() {
+		case model.StringFormatUuid:
+			conditions = append(conditions, fmt.Sprintf(
+				"%s ~ '^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$'",
+				column,
+			))
+		case model.StringFormatIpv4:
+			conditions = append(conditions, fmt.Sprintf("family(CAST(%s AS inet)) = 4", column))
+		case model.StringFormatIpv6:
+			conditions = append(conditions, fmt.Sprintf("family(CAST(%s AS inet)) = 6", column))
+		}
+	}
+
+	// Bounds were checked to be plain numbers when the schema was validated
+	if fieldDefinition.Min.IsSome() {
+		conditions = append(conditions, fmt.Sprintf("%s >= %s", column, fieldDefinition.Min.Unwrap().String()))
+	}
+
+	if fieldDefinition.Max.IsSome() {
+		conditions = append(conditions, fmt.Sprintf("%s <= %s", column, fieldDefinition.Max.Unwrap().String()))
+	}
+
+	if fieldDefinition.NotBefore.IsSome() {
+		conditions = append(conditions, fmt.Sprintf(
+			"%s >= CAST(%s AS timestamptz)",
+			column,
+			pq.QuoteLiteral(fieldDefinition.NotBefore.Unwrap().UTC().Format(PostgresTimeFormat)),
+		))
+	}
+
+	if fieldDefinition.NotAfter.IsSome() {
+		conditions = append(conditions, fmt.Sprintf(
+			"%s <= CAST(%s AS timestamptz)",
+			column,
+			pq.QuoteLiteral(fieldDefinition.NotAfter.Unwrap().UTC().Format(PostgresTimeFormat)),
+		))
 	}
-	panic(fmt.Sprintf("invalid field type has entered the system: %+v", fieldType))
+
+	return conditions
+}
+
+// References are restricted from being deleted unless the definition says
+// otherwise
+func writePostgresFieldReference(
+	query *postgresQuery,
+	projectId model.ProjectId,
+	fieldDefinition model.FieldDefinition,
+) {
+	if fieldDefinition.Type != model.FieldTypeReference {
+		return
+	}
+
+	query.
+		Write(" REFERENCES ").
+		WriteIdentifier(getPostgresTableName(projectId, fieldDefinition.References.Unwrap())).
+		Write(" (id) ON DELETE ")
+
+	onDelete := model.ReferenceOnDeleteRestrict
+
+	if fieldDefinition.OnDelete.IsSome() {
+		onDelete = fieldDefinition.OnDelete.Unwrap()
+	}
+
+	switch onDelete {
+	case model.ReferenceOnDeleteRestrict:
+		query.Write("RESTRICT")
+	case model.ReferenceOnDeleteCascade:
+		query.Write("CASCADE")
+	case model.ReferenceOnDeleteSetNull:
+		query.Write("SET NULL")
+	}
+}
+
+func getPostgresFieldCheckName(fieldName model.FieldName) string {
+	return fieldName.String() + "_check"
 }
 
-const PostgresTimeFormat = "2006-01-02T15:04:05Z"
+// Times are always sent in UTC, so that columns created as timestamp rather
+// than timestamptz, which ignore the offset, still hold UTC
+const PostgresTimeFormat = "2006-01-02T15:04:05.999999Z07:00"
 
-func getPostgresFieldValue(field any) result.R[string] {
+const PostgresDateFormat = "2006-01-02"
+
+// Converts a validated field into a value that can be bound to a query
+// placeholder
+func getPostgresFieldValue(field any) result.R[any] {
 	switch v := field.(type) {
 	case uuid.UUID:
-		return result.Ok("'" + v.String() + "'")
+		return result.Ok[any](v.String())
+	case int:
+		return result.Ok[any](v)
+	case int64:
+		return result.Ok[any](v)
+	case float64:
+		return result.Ok[any](v)
+	case model.Decimal:
+		return result.Ok[any](v.String())
+	case model.Json:
+		return result.Ok[any](v.String())
+	case model.Date:
+		return result.Ok[any](v.String())
+	case model.Duration:
+		return result.Ok[any](v.String())
+	case []string:
+		return result.Ok[any](pq.Array(v))
+	case []int:
+		integers := make([]int64, len(v))
+
+		for i, integer := range v {
+			integers[i] = int64(integer)
+		}
+
+		return result.Ok[any](pq.Array(integers))
+	case string:
+		return result.Ok[any](v)
+	case bool:
+		return result.Ok[any](v)
+	case time.Time:
+		return result.Ok[any](v.UTC().Format(PostgresTimeFormat))
+	case nil:
+		return result.Ok[any](nil)
+	}
+	return result.Errf[any]("field: %+v has unsupported type: %T", field, field)
+}
+
+// Postgres doesn't allow placeholders in DDL statements, so where a value
+// has to appear in one (e.g. a column default) it is rendered as an escaped
+// literal instead
+func getPostgresFieldLiteral(field any) result.R[string] {
+	valueResult := getPostgresFieldValue(field)
+
+	if valueResult.IsErr() {
+		return result.Err[string](valueResult.UnwrapErr())
+	}
+
+	return getPostgresValueLiteral(valueResult.Unwrap())
+}
+
+// Renders a value that would otherwise be bound to a query placeholder
+func getPostgresValueLiteral(value any) result.R[string] {
+	switch v := value.(type) {
 	case int:
-		return result.Ok(fmt.Sprintf("'%d'", v))
+		return result.Ok(fmt.Sprintf("%d", v))
+	case int64:
+		return result.Ok(strconv.FormatInt(v, 10))
+	case float64:
+		return result.Ok(strconv.FormatFloat(v, 'g', -1, 64))
 	case string:
-		return result.Ok("'" + v + "'")
+		return result.Ok(pq.QuoteLiteral(v))
 	case bool:
 		if v {
-			return result.Ok("'true'")
+			return result.Ok("true")
 		}
-		return result.Ok("'false'")
-	case time.Time:
-		return result.Ok("'" + v.Format(PostgresTimeFormat) + "'")
+		return result.Ok("false")
 	case nil:
 		return result.Ok("null")
+	case driver.Valuer:
+		// Arrays are bound as valuers that encode to postgres' text form
+		arrayValue, err := v.Value()
+
+		if err != nil {
+			return result.Errf[string]("error encoding value: %+v: %w", value, err)
+		}
+
+		arrayText, ok := arrayValue.(string)
+
+		if !ok {
+			return result.Errf[string]("value: %+v has unsupported encoding: %T", value, arrayValue)
+		}
+
+		return result.Ok(pq.QuoteLiteral(arrayText))
 	}
-	return result.Err[string](fmt.Errorf("field: %+v has unsupported type: ", field))
+	return result.Errf[string]("value: %+v has unsupported type: %T", value, value)
 }
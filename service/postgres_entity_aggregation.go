@@ -0,0 +1,637 @@
+package service
+
+import (
+	"crudly/errs"
+	"crudly/model"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func (p *postgresEntityCount) FetchEntityAggregation(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	tableSchema model.TableSchema,
+	filterExpression model.EntityFilterExpression,
+	groupBy []model.FieldName,
+	entityAggregations model.EntityAggregations,
+	entityHistogram optional.O[model.EntityHistogram],
+) result.R[model.EntityAggregationResults] {
+	var query *postgresQuery
+
+	if entityHistogram.IsSome() {
+		query = getPostgresEntityHistogramQuery(
+			projectId,
+			tableName,
+			filterExpression,
+			groupBy,
+			entityAggregations,
+			entityHistogram.Unwrap(),
+		)
+	} else {
+		query = getPostgresEntityAggregationQuery(
+			projectId,
+			tableName,
+			filterExpression,
+			groupBy,
+			entityAggregations,
+		)
+	}
+
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
+
+	if err != nil {
+		return result.Errf[model.EntityAggregationResults]("error querying postgres: %w", err)
+	}
+
+	defer rows.Close()
+
+	// Histogram buckets are selected before everything else
+	groupFields := groupBy
+
+	if entityHistogram.IsSome() {
+		groupFields = append([]model.FieldName{entityHistogram.Unwrap().FieldName}, groupBy...)
+	}
+
+	entityAggregationResults := model.EntityAggregationResults{}
+
+	// Rows come ordered by bucket, so a new bucket starts whenever it changes
+	bucketCount := 0
+	lastBucket := sql.NullString{}
+
+	for rows.Next() {
+		columns := make([]any, len(groupFields)+len(entityAggregations))
+
+		for i := range columns {
+			columns[i] = new(sql.NullString)
+		}
+
+		err := rows.Scan(columns...)
+
+		if err != nil {
+			return result.Errf[model.EntityAggregationResults]("error scanning postgres rows: %w", err)
+		}
+
+		entityAggregationResult := model.EntityAggregationResult{
+			Group:  map[model.FieldName]model.Field{},
+			Values: []model.Field{},
+		}
+
+		if entityHistogram.IsSome() {
+			bucket := *(columns[0].(*sql.NullString))
+
+			if bucketCount == 0 || bucket != lastBucket {
+				bucketCount++
+				lastBucket = bucket
+			}
+
+			if bucketCount > model.MaxHistogramBuckets {
+				return result.Err[model.EntityAggregationResults](errs.NewInvalidEntityAggregationError(fmt.Errorf(
+					"histogram spans more than %d buckets, narrow it with a filter on \"%s\" or use a wider interval",
+					model.MaxHistogramBuckets,
+					entityHistogram.Unwrap().FieldName,
+				)))
+			}
+		}
+
+		for i, fieldName := range groupFields {
+			entityAggregationResult.Group[fieldName] = parsePostgresNullString(
+				*(columns[i].(*sql.NullString)),
+				tableSchema[fieldName],
+			)
+		}
+
+		for i, entityAggregation := range entityAggregations {
+			valueResult := parsePostgresAggregationValue(
+				*(columns[len(groupFields)+i].(*sql.NullString)),
+				entityAggregation,
+				tableSchema,
+			)
+
+			if valueResult.IsErr() {
+				return result.Err[model.EntityAggregationResults](valueResult.UnwrapErr())
+			}
+
+			entityAggregationResult.Values = append(entityAggregationResult.Values, valueResult.Unwrap())
+		}
+
+		entityAggregationResults = append(entityAggregationResults, entityAggregationResult)
+	}
+
+	return result.Ok(entityAggregationResults)
+}
+
+func parsePostgresNullString(nullStr sql.NullString, fieldDefinition model.FieldDefinition) any {
+	if !nullStr.Valid {
+		return nil
+	}
+
+	return parsePostgresFieldString(nullStr.String, fieldDefinition)
+}
+
+// Aggregates over no rows (other than counts) are null, which is kept as nil
+func parsePostgresAggregationValue(
+	nullStr sql.NullString,
+	entityAggregation model.EntityAggregation,
+	tableSchema model.TableSchema,
+) result.R[any] {
+	if !nullStr.Valid {
+		return result.Ok[any](nil)
+	}
+
+	switch entityAggregation.Type {
+	case model.EntityAggregationTypeCount:
+		integer, err := strconv.Atoi(nullStr.String)
+
+		if err != nil {
+			return result.Errf[any]("error parsing %s: %w", entityAggregation.String(), err)
+		}
+
+		return result.Ok[any](integer)
+	case model.EntityAggregationTypeSum:
+		fieldDefinition := tableSchema[entityAggregation.FieldName.Unwrap()]
+
+		// Sums of bigints can overflow 64 bits so are kept exact as decimals
+		if fieldDefinition.Type == model.FieldTypeBigInt {
+			return result.Ok[any](model.Decimal(nullStr.String))
+		}
+
+		return result.Ok(parsePostgresFieldString(nullStr.String, fieldDefinition))
+	case model.EntityAggregationTypeAvg:
+		// Averages of decimals stay exact and averages of durations are
+		// durations, everything else is a float
+		switch tableSchema[entityAggregation.FieldName.Unwrap()].Type {
+		case model.FieldTypeDecimal:
+			return result.Ok[any](model.Decimal(nullStr.String))
+		case model.FieldTypeDuration:
+			return result.Ok[any](model.Duration(nullStr.String))
+		}
+
+		float, err := strconv.ParseFloat(nullStr.String, 64)
+
+		if err != nil {
+			return result.Errf[any]("error parsing %s: %w", entityAggregation.String(), err)
+		}
+
+		return result.Ok[any](float)
+	case model.EntityAggregationTypeMin, model.EntityAggregationTypeMax:
+		fieldDefinition := tableSchema[entityAggregation.FieldName.Unwrap()]
+
+		return result.Ok(parsePostgresFieldString(nullStr.String, fieldDefinition))
+	}
+	panic(fmt.Sprintf("invalid entity aggregation type has entered the system: %+v", entityAggregation.Type))
+}
+
+func getPostgresEntityAggregationQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	filterExpression model.EntityFilterExpression,
+	groupBy []model.FieldName,
+	entityAggregations model.EntityAggregations,
+) *postgresQuery {
+	query := newPostgresQuery().Write("SELECT ")
+
+	for i, fieldName := range groupBy {
+		if i > 0 {
+			query.Write(", ")
+		}
+
+		query.WriteIdentifier(fieldName.String())
+	}
+
+	for i, entityAggregation := range entityAggregations {
+		if i > 0 || len(groupBy) > 0 {
+			query.Write(", ")
+		}
+
+		writePostgresAggregation(query, entityAggregation)
+	}
+
+	query.
+		Write(" FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName))
+
+	writePostgresFilter(query, filterExpression)
+
+	if len(groupBy) == 0 {
+		return query
+	}
+
+	query.Write(" GROUP BY ")
+	writePostgresColumnPositions(query, 1, len(groupBy))
+
+	query.Write(" ORDER BY ")
+	writePostgresColumnPositions(query, 1, len(groupBy))
+
+	return query
+}
+
+// Builds the buckets to report separately from the data so that buckets
+// without any rows in them (for every group) are still returned. The bucket
+// range comes from any bounds the filter puts on the histogram field, falling
+// back to the earliest and latest matching rows.
+//
+// WITH "range" AS (SELECT MIN(f) AS "start", MAX(f) AS "end" FROM t WHERE ...),
+// "buckets" AS (SELECT generate_series(...) AS "bucket" FROM "range"),
+// "groups" AS (SELECT DISTINCT g AS "g0" FROM t WHERE ...),
+// "data" AS (SELECT <bucket of f> AS "bucket", g AS "g0", COUNT(*) AS "a0" ... GROUP BY 1, 2)
+// SELECT ... FROM "buckets" CROSS JOIN "groups" LEFT JOIN "data" ON ...
+func getPostgresEntityHistogramQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	filterExpression model.EntityFilterExpression,
+	groupBy []model.FieldName,
+	entityAggregations model.EntityAggregations,
+	entityHistogram model.EntityHistogram,
+) *postgresQuery {
+	postgresTableName := getPostgresTableName(projectId, tableName)
+	fieldName := entityHistogram.FieldName.String()
+	lowerBound, upperBound := entityHistogram.GetBounds(filterExpression)
+
+	query := newPostgresQuery().Write(`WITH "range" AS (SELECT MIN(`)
+
+	writePostgresUtcTimestamp(query, fieldName)
+
+	query.Write(`) AS "start", MAX(`)
+
+	writePostgresUtcTimestamp(query, fieldName)
+
+	query.Write(`) AS "end" FROM `).WriteIdentifier(postgresTableName)
+
+	writePostgresFilter(query, filterExpression)
+
+	query.Write(`), "buckets" AS (SELECT generate_series(`)
+
+	writePostgresHistogramBucket(query, entityHistogram, func() {
+		if lowerBound.IsSome() {
+			query.Write("CAST(").WriteArg(getPostgresFieldValue(lowerBound.Unwrap()).Unwrap()).Write(" AS timestamp)")
+		} else {
+			query.Write(`"range"."start"`)
+		}
+	})
+
+	query.Write(", ")
+
+	writePostgresHistogramBucket(query, entityHistogram, func() {
+		if upperBound.IsSome() {
+			query.Write("CAST(").WriteArg(getPostgresFieldValue(upperBound.Unwrap()).Unwrap()).Write(" AS timestamp)")
+		} else {
+			query.Write(`"range"."end"`)
+		}
+	})
+
+	query.Write(", ")
+
+	writePostgresHistogramStep(query, entityHistogram)
+
+	// Validation can only count the buckets when the filter bounds both ends
+	// of the range. Otherwise one bucket past the maximum is generated, so
+	// that a histogram with too many is found when reading the results
+	query.
+		Write(`) AS "bucket" FROM "range" LIMIT `).
+		WriteArg(model.MaxHistogramBuckets + 1).
+		Write(")")
+
+	if len(groupBy) > 0 {
+		query.Write(`, "groups" AS (SELECT DISTINCT `)
+
+		writePostgresHistogramGroupColumns(query, groupBy)
+
+		query.Write(" FROM ").WriteIdentifier(postgresTableName)
+
+		writePostgresFilter(query, filterExpression)
+
+		query.Write(")")
+	}
+
+	query.Write(`, "data" AS (SELECT `)
+
+	writePostgresHistogramBucket(query, entityHistogram, func() {
+		writePostgresUtcTimestamp(query, fieldName)
+	})
+
+	query.Write(` AS "bucket"`)
+
+	if len(groupBy) > 0 {
+		query.Write(", ")
+		writePostgresHistogramGroupColumns(query, groupBy)
+	}
+
+	for i, entityAggregation := range entityAggregations {
+		query.Write(", ")
+		writePostgresAggregation(query, entityAggregation)
+		query.Write(" AS ").WriteIdentifier(fmt.Sprintf("a%d", i))
+	}
+
+	query.Write(" FROM ").WriteIdentifier(postgresTableName)
+
+	writePostgresFilter(query, filterExpression)
+
+	query.Write(" GROUP BY ")
+	writePostgresColumnPositions(query, 1, len(groupBy)+1)
+	query.Write(") SELECT ")
+
+	writePostgresHistogramUtc(query, entityHistogram, func() {
+		query.Write(`"buckets"."bucket"`)
+	})
+
+	for i := range groupBy {
+		query.Write(`, "groups".`).WriteIdentifier(fmt.Sprintf("g%d", i))
+	}
+
+	for i, entityAggregation := range entityAggregations {
+		query.Write(", ")
+
+		// Empty buckets have no data row, their counts should be 0 rather
+		// than null
+		if entityAggregation.Type == model.EntityAggregationTypeCount {
+			query.Write(`COALESCE("data".`).WriteIdentifier(fmt.Sprintf("a%d", i)).Write(", 0)")
+		} else {
+			query.Write(`"data".`).WriteIdentifier(fmt.Sprintf("a%d", i))
+		}
+	}
+
+	query.Write(` FROM "buckets"`)
+
+	if len(groupBy) > 0 {
+		query.Write(` CROSS JOIN "groups"`)
+	}
+
+	query.Write(` LEFT JOIN "data" ON "data"."bucket" = "buckets"."bucket"`)
+
+	for i := range groupBy {
+		groupColumn := fmt.Sprintf("g%d", i)
+
+		query.
+			Write(` AND "data".`).
+			WriteIdentifier(groupColumn).
+			Write(` IS NOT DISTINCT FROM "groups".`).
+			WriteIdentifier(groupColumn)
+	}
+
+	query.Write(` ORDER BY "buckets"."bucket"`)
+
+	for i := range groupBy {
+		query.Write(`, "groups".`).WriteIdentifier(fmt.Sprintf("g%d", i))
+	}
+
+	return query
+}
+
+func writePostgresHistogramGroupColumns(query *postgresQuery, groupBy []model.FieldName) {
+	for i, fieldName := range groupBy {
+		if i > 0 {
+			query.Write(", ")
+		}
+
+		query.
+			WriteIdentifier(fieldName.String()).
+			Write(" AS ").
+			WriteIdentifier(fmt.Sprintf("g%d", i))
+	}
+}
+
+// Histograms work on timestamps holding the UTC wall clock. Connections use
+// UTC as their timezone, so this is the same cast whether the column is a
+// timestamptz or a timestamp created before timestamptz was used
+func writePostgresUtcTimestamp(query *postgresQuery, fieldName string) {
+	query.Write("CAST(").WriteIdentifier(fieldName).Write(" AS timestamp)")
+}
+
+// Writes the start of the bucket that the timestamp written by writeValue
+// falls into. Buckets are worked out on the wall clock of the histogram's
+// timezone, so the result is a timestamp in that timezone
+func writePostgresHistogramBucket(query *postgresQuery, entityHistogram model.EntityHistogram, writeValue func()) {
+	writeLocalValue := func() {
+		if entityHistogram.Timezone.IsNone() {
+			writeValue()
+			return
+		}
+
+		query.Write("(")
+		writeValue()
+		query.
+			Write(" AT TIME ZONE 'UTC' AT TIME ZONE ").
+			WriteArg(entityHistogram.Timezone.Unwrap()).
+			Write(")")
+	}
+
+	if entityHistogram.Unit.IsSome() {
+		query.Write("date_trunc(").Write(getPostgresHistogramUnit(entityHistogram.Unit.Unwrap())).Write(", ")
+		writeLocalValue()
+		query.Write(")")
+		return
+	}
+
+	seconds := int(entityHistogram.Interval.Unwrap() / time.Second)
+
+	query.Write("(TIMESTAMP 'epoch' + make_interval(secs => FLOOR(EXTRACT(EPOCH FROM ")
+	writeLocalValue()
+	query.
+		Write(") / ").
+		WriteArg(seconds).
+		Write(") * ").
+		WriteArg(seconds).
+		Write("))")
+}
+
+// Converts a bucket written by writeValue from the histogram's timezone back
+// to UTC
+func writePostgresHistogramUtc(query *postgresQuery, entityHistogram model.EntityHistogram, writeValue func()) {
+	if entityHistogram.Timezone.IsNone() {
+		writeValue()
+		return
+	}
+
+	query.Write("(")
+	writeValue()
+	query.
+		Write(" AT TIME ZONE ").
+		WriteArg(entityHistogram.Timezone.Unwrap()).
+		Write(" AT TIME ZONE 'UTC')")
+}
+
+func writePostgresHistogramStep(query *postgresQuery, entityHistogram model.EntityHistogram) {
+	if entityHistogram.Unit.IsSome() {
+		query.Write("INTERVAL ").Write(getPostgresHistogramUnitInterval(entityHistogram.Unit.Unwrap()))
+		return
+	}
+
+	query.
+		Write("make_interval(secs => ").
+		WriteArg(int(entityHistogram.Interval.Unwrap() / time.Second)).
+		Write(")")
+}
+
+func getPostgresHistogramUnit(histogramUnit model.HistogramUnit) string {
+	switch histogramUnit {
+	case model.HistogramUnitMinute:
+		return "'minute'"
+	case model.HistogramUnitHour:
+		return "'hour'"
+	case model.HistogramUnitDay:
+		return "'day'"
+	case model.HistogramUnitWeek:
+		return "'week'"
+	case model.HistogramUnitMonth:
+		return "'month'"
+	}
+	panic(fmt.Sprintf("invalid histogram unit has entered the system: %+v", histogramUnit))
+}
+
+func getPostgresHistogramUnitInterval(histogramUnit model.HistogramUnit) string {
+	switch histogramUnit {
+	case model.HistogramUnitMinute:
+		return "'1 minute'"
+	case model.HistogramUnitHour:
+		return "'1 hour'"
+	case model.HistogramUnitDay:
+		return "'1 day'"
+	case model.HistogramUnitWeek:
+		return "'1 week'"
+	case model.HistogramUnitMonth:
+		return "'1 month'"
+	}
+	panic(fmt.Sprintf("invalid histogram unit has entered the system: %+v", histogramUnit))
+}
+
+func writePostgresColumnPositions(query *postgresQuery, from int, to int) {
+	for i := from; i <= to; i++ {
+		if i > from {
+			query.Write(", ")
+		}
+
+		query.Write(strconv.Itoa(i))
+	}
+}
+
+func writePostgresAggregation(query *postgresQuery, entityAggregation model.EntityAggregation) {
+	query.Write(getPostgresAggregateFunction(entityAggregation.Type)).Write("(")
+
+	if entityAggregation.FieldName.IsNone() {
+		query.Write("*")
+	} else {
+		query.WriteIdentifier(entityAggregation.FieldName.Unwrap().String())
+	}
+
+	query.Write(")")
+}
+
+func getPostgresAggregateFunction(entityAggregationType model.EntityAggregationType) string {
+	switch entityAggregationType {
+	case model.EntityAggregationTypeCount:
+		return "COUNT"
+	case model.EntityAggregationTypeSum:
+		return "SUM"
+	case model.EntityAggregationTypeAvg:
+		return "AVG"
+	case model.EntityAggregationTypeMin:
+		return "MIN"
+	case model.EntityAggregationTypeMax:
+		return "MAX"
+	}
+	panic(fmt.Sprintf("invalid entity aggregation type has entered the system: %+v", entityAggregationType))
+}
+
+func (p *postgresEntityCount) FetchFieldValueCounts(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	fieldName model.FieldName,
+	fieldDefinition model.FieldDefinition,
+	filterExpression model.EntityFilterExpression,
+	order model.FieldValuesOrderType,
+	paginationParams model.PaginationParams,
+) result.R[model.FieldValueCounts] {
+	query := getPostgresFieldValueCountsQuery(
+		projectId,
+		tableName,
+		fieldName,
+		fieldDefinition,
+		filterExpression,
+		order,
+		paginationParams,
+	)
+
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
+
+	if err != nil {
+		return result.Errf[model.FieldValueCounts]("error querying postgres: %w", err)
+	}
+
+	defer rows.Close()
+
+	fieldValueCounts := model.FieldValueCounts{}
+
+	for rows.Next() {
+		value := sql.NullString{}
+		count := uint(0)
+
+		err := rows.Scan(&value, &count)
+
+		if err != nil {
+			return result.Errf[model.FieldValueCounts]("error scanning postgres rows: %w", err)
+		}
+
+		fieldValueCounts = append(fieldValueCounts, model.FieldValueCount{
+			Value: parsePostgresNullString(value, fieldDefinition),
+			Count: count,
+		})
+	}
+
+	return result.Ok(fieldValueCounts)
+}
+
+// Declared enum values that don't appear in any matching row are included
+// with a count of 0
+//
+// WITH "counts" AS (SELECT f AS "value", COUNT(*) AS "count" FROM t WHERE ... GROUP BY 1)
+// SELECT "value", "count" FROM "counts"
+// UNION ALL SELECT "declared"."value", 0 FROM unnest(...) AS "declared"("value") WHERE NOT EXISTS (...)
+// ORDER BY ... LIMIT ... OFFSET ...
+func getPostgresFieldValueCountsQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	fieldName model.FieldName,
+	fieldDefinition model.FieldDefinition,
+	filterExpression model.EntityFilterExpression,
+	order model.FieldValuesOrderType,
+	paginationParams model.PaginationParams,
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write(`WITH "counts" AS (SELECT `).
+		WriteIdentifier(fieldName.String()).
+		Write(` AS "value", COUNT(*) AS "count" FROM `).
+		WriteIdentifier(getPostgresTableName(projectId, tableName))
+
+	writePostgresFilter(query, filterExpression)
+
+	query.Write(` GROUP BY 1) SELECT "value", "count" FROM "counts"`)
+
+	if fieldDefinition.Type == model.FieldTypeEnum && fieldDefinition.Values.IsSome() {
+		query.
+			Write(` UNION ALL SELECT "declared"."value", 0 FROM unnest(CAST(`).
+			WriteArg(pq.Array(fieldDefinition.Values.Unwrap())).
+			Write(` AS varchar[])) AS "declared"("value") WHERE NOT EXISTS (`).
+			Write(`SELECT 1 FROM "counts" WHERE "counts"."value" = "declared"."value")`)
+	}
+
+	switch order {
+	case model.FieldValuesOrderTypeCount:
+		query.Write(` ORDER BY "count" DESC, "value" ASC`)
+	case model.FieldValuesOrderTypeValue:
+		query.Write(` ORDER BY "value" ASC`)
+	default:
+		panic(fmt.Sprintf("invalid field values order type has entered the system: %+v", order))
+	}
+
+	return query.
+		Write(" LIMIT ").
+		WriteArg(uint(paginationParams.Limit)).
+		Write(" OFFSET ").
+		WriteArg(uint(paginationParams.Offset))
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"testing"
+	"time"
+)
+
+func TestGetPostgresEntityHistogramQuery(t *testing.T) {
+	const table = `"6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-events"`
+
+	tests := []struct {
+		name               string
+		filterExpression   model.EntityFilterExpression
+		groupBy            []model.FieldName
+		entityAggregations model.EntityAggregations
+		entityHistogram    model.EntityHistogram
+		expectedSql        string
+		expectedArgs       []any
+	}{
+		{
+			name:             "fixed interval over the range of the data",
+			filterExpression: model.EmptyEntityFilterExpression(),
+			groupBy:          []model.FieldName{},
+			entityAggregations: model.EntityAggregations{
+				{Type: model.EntityAggregationTypeCount, FieldName: optional.None[model.FieldName]()},
+			},
+			entityHistogram: model.EntityHistogram{
+				FieldName: "at",
+				Unit:      optional.None[model.HistogramUnit](),
+				Interval:  optional.Some(15 * time.Minute),
+				Timezone:  optional.None[string](),
+			},
+			expectedSql: `WITH "range" AS (SELECT MIN(CAST("at" AS timestamp)) AS "start", MAX(CAST("at" AS timestamp)) AS "end"` +
+				` FROM ` + table + `),` +
+				` "buckets" AS (SELECT generate_series(` +
+				`(TIMESTAMP 'epoch' + make_interval(secs => FLOOR(EXTRACT(EPOCH FROM "range"."start") / $1) * $2)),` +
+				` (TIMESTAMP 'epoch' + make_interval(secs => FLOOR(EXTRACT(EPOCH FROM "range"."end") / $3) * $4)),` +
+				` make_interval(secs => $5)) AS "bucket" FROM "range" LIMIT $6),` +
+				` "data" AS (SELECT (TIMESTAMP 'epoch' + make_interval(secs => FLOOR(EXTRACT(EPOCH FROM CAST("at" AS timestamp)) / $7) * $8))` +
+				` AS "bucket", COUNT(*) AS "a0" FROM ` + table + ` GROUP BY 1)` +
+				` SELECT "buckets"."bucket", COALESCE("data"."a0", 0)` +
+				` FROM "buckets" LEFT JOIN "data" ON "data"."bucket" = "buckets"."bucket"` +
+				` ORDER BY "buckets"."bucket"`,
+			expectedArgs: []any{900, 900, 900, 900, 900, model.MaxHistogramBuckets + 1, 900, 900},
+		},
+		{
+			name: "calendar unit in a timezone, bounded by the filter and grouped",
+			filterExpression: model.EntityFilter{
+				"at": {
+					{Type: model.FieldFilterTypeGreaterThanEq, Comparator: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+					{Type: model.FieldFilterTypeLessThan, Comparator: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			}.ToExpression(),
+			groupBy: []model.FieldName{`ki"nd`},
+			entityAggregations: model.EntityAggregations{
+				{Type: model.EntityAggregationTypeCount, FieldName: optional.None[model.FieldName]()},
+				{Type: model.EntityAggregationTypeMax, FieldName: optional.Some[model.FieldName]("n")},
+			},
+			entityHistogram: model.EntityHistogram{
+				FieldName: "at",
+				Unit:      optional.Some(model.HistogramUnitDay),
+				Interval:  optional.None[time.Duration](),
+				Timezone:  optional.Some(testInjection),
+			},
+			expectedSql: `WITH "range" AS (SELECT MIN(CAST("at" AS timestamp)) AS "start", MAX(CAST("at" AS timestamp)) AS "end"` +
+				` FROM ` + table + ` WHERE ("at" >= $1 AND "at" < $2)),` +
+				` "buckets" AS (SELECT generate_series(` +
+				`date_trunc('day', (CAST($3 AS timestamp) AT TIME ZONE 'UTC' AT TIME ZONE $4)),` +
+				` date_trunc('day', (CAST($5 AS timestamp) AT TIME ZONE 'UTC' AT TIME ZONE $6)),` +
+				` INTERVAL '1 day') AS "bucket" FROM "range" LIMIT $7),` +
+				` "groups" AS (SELECT DISTINCT "ki""nd" AS "g0" FROM ` + table + ` WHERE ("at" >= $8 AND "at" < $9)),` +
+				` "data" AS (SELECT date_trunc('day', (CAST("at" AS timestamp) AT TIME ZONE 'UTC' AT TIME ZONE $10)) AS "bucket",` +
+				` "ki""nd" AS "g0", COUNT(*) AS "a0", MAX("n") AS "a1"` +
+				` FROM ` + table + ` WHERE ("at" >= $11 AND "at" < $12) GROUP BY 1, 2)` +
+				` SELECT ("buckets"."bucket" AT TIME ZONE $13 AT TIME ZONE 'UTC'), "groups"."g0", COALESCE("data"."a0", 0), "data"."a1"` +
+				` FROM "buckets" CROSS JOIN "groups"` +
+				` LEFT JOIN "data" ON "data"."bucket" = "buckets"."bucket" AND "data"."g0" IS NOT DISTINCT FROM "groups"."g0"` +
+				` ORDER BY "buckets"."bucket", "groups"."g0"`,
+			expectedArgs: []any{
+				"2023-03-01T00:00:00Z",
+				"2023-04-01T00:00:00Z",
+				"2023-03-01T00:00:00Z",
+				testInjection,
+				// The exclusive upper bound is pulled back so it doesn't start
+				// a bucket of its own
+				"2023-03-31T23:59:59.999999Z",
+				testInjection,
+				model.MaxHistogramBuckets + 1,
+				"2023-03-01T00:00:00Z",
+				"2023-04-01T00:00:00Z",
+				testInjection,
+				"2023-03-01T00:00:00Z",
+				"2023-04-01T00:00:00Z",
+				testInjection,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := getPostgresEntityHistogramQuery(
+				testProjectId,
+				"events",
+				test.filterExpression,
+				test.groupBy,
+				test.entityAggregations,
+				test.entityHistogram,
+			)
+
+			assertPostgresQuery(t, query, test.expectedSql, test.expectedArgs)
+		})
+	}
+}
@@ -5,7 +5,8 @@ import (
 	"crudly/model"
 	"crudly/util/result"
 	"database/sql"
-	"fmt"
+	"encoding/json"
+	"math"
 )
 
 type postgresEntityCount struct {
@@ -21,11 +22,11 @@ func NewPostgresEntityCount(postgres *sql.DB) postgresEntityCount {
 func (p *postgresEntityCount) FetchTotalEntityCount(
 	projectId model.ProjectId,
 	tableName model.TableName,
-	entityFilter model.EntityFilter,
+	filterExpression model.EntityFilterExpression,
 ) result.R[uint] {
-	query := getPostgresRowCountQuery(projectId, tableName, entityFilter)
+	query := getPostgresRowCountQuery(projectId, tableName, filterExpression)
 
-	rows, err := p.postgres.Query(query)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
 	if err != nil {
 		return result.Errf[uint]("error querying postgres: %w", err)
@@ -44,18 +45,74 @@ func (p *postgresEntityCount) FetchTotalEntityCount(
 	return result.Ok(totalCount)
 }
 
-func getPostgresRowCountQuery(
+// Uses the query planner's row estimate rather than counting, which avoids a
+// full scan of large tables at the cost of accuracy
+func (p *postgresEntityCount) FetchEstimatedEntityCount(
 	projectId model.ProjectId,
 	tableName model.TableName,
-	entityFilter model.EntityFilter,
-) string {
-	query := fmt.Sprintf("SELECT COUNT(*) FROM \"%s\"", getPostgresTableName(projectId, tableName))
+	filterExpression model.EntityFilterExpression,
+) result.R[uint] {
+	query := getPostgresEstimatedRowCountQuery(projectId, tableName, filterExpression)
 
-	filtersString := getPostgresFilterString(entityFilter)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
-	if filtersString != nil {
-		query += " WHERE " + *filtersString
+	if err != nil {
+		return result.Errf[uint]("error querying postgres: %w", err)
 	}
 
+	defer rows.Close()
+
+	if !rows.Next() {
+		return result.Err[uint](errs.TableNotFoundError{})
+	}
+
+	planBytes := []byte{}
+
+	err = rows.Scan(&planBytes)
+
+	if err != nil {
+		return result.Errf[uint]("error scanning postgres query plan: %w", err)
+	}
+
+	plans := []struct {
+		Plan struct {
+			PlanRows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}{}
+
+	err = json.Unmarshal(planBytes, &plans)
+
+	if err != nil || len(plans) == 0 {
+		return result.Errf[uint]("error parsing postgres query plan: %s", string(planBytes))
+	}
+
+	return result.Ok(uint(math.Max(0, math.Round(plans[0].Plan.PlanRows))))
+}
+
+func getPostgresEstimatedRowCountQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	filterExpression model.EntityFilterExpression,
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("EXPLAIN (FORMAT JSON) SELECT 1 FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName))
+
+	writePostgresFilter(query, filterExpression)
+
+	return query
+}
+
+func getPostgresRowCountQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	filterExpression model.EntityFilterExpression,
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("SELECT COUNT(*) FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName))
+
+	writePostgresFilter(query, filterExpression)
+
 	return query
 }
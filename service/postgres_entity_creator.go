@@ -5,10 +5,9 @@ import (
 	"crudly/errs"
 	"crudly/model"
 	"crudly/util"
+	"crudly/util/result"
 	"database/sql"
 	"fmt"
-
-	"github.com/lib/pq"
 )
 
 type postgresEntityCreator struct {
@@ -34,13 +33,15 @@ func (p *postgresEntityCreator) CreateEntity(
 		entity,
 	)
 
-	rows, err := p.postgres.Query(query)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == "23505" {
-				return errs.EntityAlreadyExistsError{}
-			}
+		if isPostgresError(err, postgresUniqueViolationCode) {
+			return getPostgresEntityUniqueViolationError(err)
+		}
+
+		if isPostgresError(err, postgresForeignKeyViolationCode) {
+			return errs.NewReferencedEntityNotFoundError(getPostgresKeyViolationFields(err))
 		}
 
 		return fmt.Errorf("error querying postgres: %w", err)
@@ -72,9 +73,17 @@ func (p *postgresEntityCreator) CreateEntities(
 			entity,
 		)
 
-		_, err := tx.Query(query)
+		_, err := tx.Exec(query.String(), query.Args()...)
 
 		if err != nil {
+			if isPostgresError(err, postgresUniqueViolationCode) {
+				return getPostgresEntityUniqueViolationError(err)
+			}
+
+			if isPostgresError(err, postgresForeignKeyViolationCode) {
+				return errs.NewReferencedEntityNotFoundError(getPostgresKeyViolationFields(err))
+			}
+
 			return fmt.Errorf("error querying postgres: %w", err)
 		}
 	}
@@ -88,21 +97,204 @@ func (p *postgresEntityCreator) CreateEntities(
 	return nil
 }
 
+func (p *postgresEntityCreator) UpsertEntity(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	tableSchema model.TableSchema,
+	upsertKey model.UpsertKey,
+	id model.EntityId,
+	entity model.Entity,
+) result.R[model.UpsertedEntity] {
+	query := getPostgresUpsertEntityQuery(
+		projectId,
+		tableName,
+		tableSchema,
+		upsertKey,
+		id,
+		entity,
+	)
+
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
+
+	if err != nil {
+		return result.Err[model.UpsertedEntity](getPostgresUpsertError(err))
+	}
+
+	defer rows.Close()
+
+	return parseUpsertedEntityFromSqlRow(rows, tableSchema)
+}
+
+func (p *postgresEntityCreator) UpsertEntities(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	tableSchema model.TableSchema,
+	upsertKey model.UpsertKey,
+	ids []model.EntityId,
+	entities model.Entities,
+) result.R[[]model.UpsertedEntity] {
+	tx, err := p.postgres.BeginTx(context.Background(), nil)
+
+	if err != nil {
+		return result.Errf[[]model.UpsertedEntity]("error opening postgres transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	upsertedEntities := []model.UpsertedEntity{}
+
+	// One statement per entity, as a single statement can't update the same
+	// entity twice when the batch repeats a key
+	for index, entity := range entities {
+		query := getPostgresUpsertEntityQuery(
+			projectId,
+			tableName,
+			tableSchema,
+			upsertKey,
+			ids[index],
+			entity,
+		)
+
+		rows, err := tx.Query(query.String(), query.Args()...)
+
+		if err != nil {
+			return result.Err[[]model.UpsertedEntity](getPostgresUpsertError(err))
+		}
+
+		upsertedEntityResult := parseUpsertedEntityFromSqlRow(rows, tableSchema)
+
+		rows.Close()
+
+		if upsertedEntityResult.IsErr() {
+			return result.Err[[]model.UpsertedEntity](upsertedEntityResult.UnwrapErr())
+		}
+
+		upsertedEntities = append(upsertedEntities, upsertedEntityResult.Unwrap())
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return result.Errf[[]model.UpsertedEntity]("error commiting postgres transaction: %w", err)
+	}
+
+	return result.Ok(upsertedEntities)
+}
+
+func parseUpsertedEntityFromSqlRow(rows *sql.Rows, tableSchema model.TableSchema) result.R[model.UpsertedEntity] {
+	if !rows.Next() {
+		return result.Errf[model.UpsertedEntity]("upsert returned no rows: %v", rows.Err())
+	}
+
+	created := false
+
+	entityResult := parseEntityFromSqlRowAfter(rows, tableSchema, &created)
+
+	if entityResult.IsErr() {
+		return result.Err[model.UpsertedEntity](entityResult.UnwrapErr())
+	}
+
+	return result.Ok(model.UpsertedEntity{
+		Entity:  entityResult.Unwrap(),
+		Created: created,
+	})
+}
+
+func getPostgresUpsertError(err error) error {
+	if isPostgresError(err, postgresUniqueViolationCode) {
+		return errs.NewUniqueViolationError(getPostgresKeyViolationFields(err))
+	}
+
+	if isPostgresError(err, postgresForeignKeyViolationCode) {
+		return errs.NewReferencedEntityNotFoundError(getPostgresKeyViolationFields(err))
+	}
+
+	if isPostgresError(err, postgresInvalidColumnReferenceCode) {
+		return errs.NewInvalidUpsertKeyError(fmt.Errorf("no unique field or unique index without a filter covers exactly these fields"))
+	}
+
+	return fmt.Errorf("error querying postgres: %w", err)
+}
+
+// Upserting replaces the whole entity, so optional fields that are left out
+// are cleared. The inserted row's defaults for them are null, which lets every
+// field be set from it. A row that was just inserted has no xmax
+func getPostgresUpsertEntityQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	tableSchema model.TableSchema,
+	upsertKey model.UpsertKey,
+	id model.EntityId,
+	entity model.Entity,
+) *postgresQuery {
+	query := getPostgresCreateEntityQuery(projectId, tableName, id, entity).Write(" ON CONFLICT (")
+
+	for i, fieldName := range upsertKey {
+		if i > 0 {
+			query.Write(", ")
+		}
+
+		query.WriteIdentifier(fieldName.String())
+	}
+
+	query.Write(") DO UPDATE SET ")
+
+	updatedFieldNames := []model.FieldName{}
+
+	for _, fieldName := range util.GetSortedMapKeys(tableSchema) {
+		if !util.Contains(upsertKey, fieldName) {
+			updatedFieldNames = append(updatedFieldNames, fieldName)
+		}
+	}
+
+	// Something has to be set for the conflicting row to be returned
+	if len(updatedFieldNames) == 0 {
+		updatedFieldNames = upsertKey[:1]
+	}
+
+	for i, fieldName := range updatedFieldNames {
+		if i > 0 {
+			query.Write(", ")
+		}
+
+		query.
+			WriteIdentifier(fieldName.String()).
+			Write(" = EXCLUDED.").
+			WriteIdentifier(fieldName.String())
+	}
+
+	return query.Write(" RETURNING (xmax = 0), *")
+}
+
+// A clash on the primary key means the id was already taken, anything else is
+// a clash on one of the table's unique fields or indexes
+func getPostgresEntityUniqueViolationError(err error) error {
+	fieldNames := getPostgresKeyViolationFields(err)
+
+	if len(fieldNames) == 1 && fieldNames[0] == "id" {
+		return errs.EntityAlreadyExistsError{}
+	}
+
+	return errs.NewUniqueViolationError(fieldNames)
+}
+
 func getPostgresCreateEntityQuery(
 	projectId model.ProjectId,
 	tableName model.TableName,
 	id model.EntityId,
 	entity model.Entity,
-) string {
-	query := "INSERT INTO \"" + getPostgresTableName(projectId, tableName) + "\"("
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("INSERT INTO ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write("(")
 
-	keys := util.GetMapKeys(entity)
+	keys := util.GetSortedMapKeys(entity)
 
 	for _, k := range keys {
-		query = query + "\"" + k.String() + "\","
+		query.WriteIdentifier(k.String()).Write(",")
 	}
 
-	query += "id) VALUES ("
+	query.Write("id) VALUES (")
 
 	for _, k := range keys {
 		postgresFieldValueResult := getPostgresFieldValue(entity[k])
@@ -111,9 +303,10 @@ func getPostgresCreateEntityQuery(
 			panic(fmt.Sprintf("error parsing field: %s: %s", k, postgresFieldValueResult.UnwrapErr().Error()))
 		}
 
-		query += postgresFieldValueResult.Unwrap() + ","
+		query.WriteArg(postgresFieldValueResult.Unwrap()).Write(",")
 	}
 
-	query += "'" + id.String() + "')"
+	query.WriteArg(id.String()).Write(")")
+
 	return query
 }
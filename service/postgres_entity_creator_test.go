@@ -0,0 +1,107 @@
+package service
+
+import (
+	"crudly/model"
+	"testing"
+	"time"
+)
+
+func TestGetPostgresCreateEntityQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		tableName    model.TableName
+		entity       model.Entity
+		expectedSql  string
+		expectedArgs []any
+	}{
+		{
+			name:         "no fields",
+			tableName:    "users",
+			entity:       model.Entity{},
+			expectedSql:  `INSERT INTO "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-users"(id) VALUES ($1)`,
+			expectedArgs: []any{"0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"},
+		},
+		{
+			name:      "fields are written in name order",
+			tableName: `my"table`,
+			entity: model.Entity{
+				`we"ird`:  testInjection,
+				"age":     30,
+				"created": time.Date(2023, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600)),
+			},
+			expectedSql: `INSERT INTO "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-my""table"` +
+				`("age","created","we""ird",id) VALUES ($1,$2,$3,$4)`,
+			expectedArgs: []any{30, "2023-01-02T02:04:05Z", testInjection, "0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := getPostgresCreateEntityQuery(testProjectId, test.tableName, testEntityId, test.entity)
+
+			assertPostgresQuery(t, query, test.expectedSql, test.expectedArgs)
+		})
+	}
+}
+
+func TestGetPostgresUpsertEntityQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		tableSchema  model.TableSchema
+		upsertKey    model.UpsertKey
+		entity       model.Entity
+		expectedSql  string
+		expectedArgs []any
+	}{
+		{
+			name: "every other field is replaced",
+			tableSchema: model.TableSchema{
+				"email":  {Type: model.FieldTypeString, Unique: true},
+				"name":   {Type: model.FieldTypeString, IsOptional: true},
+				`we"ird`: {Type: model.FieldTypeString, IsOptional: true},
+			},
+			upsertKey: model.UpsertKey{"email"},
+			entity: model.Entity{
+				"email":  testInjection,
+				`we"ird`: "a",
+			},
+			expectedSql: `INSERT INTO "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-users"` +
+				`("email","we""ird",id) VALUES ($1,$2,$3)` +
+				` ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "we""ird" = EXCLUDED."we""ird"` +
+				` RETURNING (xmax = 0), *`,
+			expectedArgs: []any{testInjection, "a", "0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"},
+		},
+		{
+			name: "composite key covering every field",
+			tableSchema: model.TableSchema{
+				"a": {Type: model.FieldTypeInteger},
+				"b": {Type: model.FieldTypeInteger},
+			},
+			upsertKey: model.UpsertKey{"b", "a"},
+			entity: model.Entity{
+				"a": 1,
+				"b": 2,
+			},
+			expectedSql: `INSERT INTO "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-users"` +
+				`("a","b",id) VALUES ($1,$2,$3)` +
+				` ON CONFLICT ("b", "a") DO UPDATE SET "b" = EXCLUDED."b"` +
+				` RETURNING (xmax = 0), *`,
+			expectedArgs: []any{1, 2, "0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := getPostgresUpsertEntityQuery(
+				testProjectId,
+				"users",
+				test.tableSchema,
+				test.upsertKey,
+				testEntityId,
+				test.entity,
+			)
+
+			assertPostgresQuery(t, query, test.expectedSql, test.expectedArgs)
+		})
+	}
+}
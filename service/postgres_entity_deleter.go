@@ -28,9 +28,15 @@ func (p *postgresEntityDeleter) DeleteEntity(
 		id,
 	)
 
-	res, err := p.postgres.Exec(query)
+	res, err := p.postgres.Exec(query.String(), query.Args()...)
 
 	if err != nil {
+		// Entities referencing this one through a restricted reference block
+		// it from being deleted
+		if isPostgresError(err, postgresForeignKeyViolationCode) {
+			return errs.EntityReferencedError{}
+		}
+
 		return fmt.Errorf("error querying postgres: %w", err)
 	}
 
@@ -51,10 +57,10 @@ func getPostgresDeleteEntityQuery(
 	projectId model.ProjectId,
 	tableName model.TableName,
 	id model.EntityId,
-) string {
-	return fmt.Sprintf(
-		"DELETE FROM \"%s\" WHERE id = '%s'",
-		getPostgresTableName(projectId, tableName),
-		id.String(),
-	)
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("DELETE FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" WHERE id = ").
+		WriteArg(id.String())
 }
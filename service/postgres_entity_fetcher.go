@@ -3,7 +3,7 @@ package service
 import (
 	"crudly/errs"
 	"crudly/model"
-	"crudly/util"
+	"crudly/util/optional"
 	"crudly/util/result"
 	"database/sql"
 	"fmt"
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type postgresEntityFetcher struct {
@@ -29,14 +30,16 @@ func (p *postgresEntityFetcher) FetchEntity(
 	tableName model.TableName,
 	tableSchema model.TableSchema,
 	id model.EntityId,
+	fieldProjection optional.O[model.FieldProjection],
 ) result.R[model.Entity] {
 	query := getPostgresEntityQuery(
 		projectId,
 		tableName,
 		id,
+		fieldProjection,
 	)
 
-	rows, err := p.postgres.Query(query)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
 	if err != nil {
 		return result.Err[model.Entity](fmt.Errorf("error querying postgres: %w", err))
@@ -55,19 +58,21 @@ func (p *postgresEntityFetcher) FetchEntities(
 	projectId model.ProjectId,
 	tableName model.TableName,
 	tableSchema model.TableSchema,
-	entityFilter model.EntityFilter,
+	filterExpression model.EntityFilterExpression,
 	entityOrders model.EntityOrders,
 	paginationParams model.PaginationParams,
+	fieldProjection optional.O[model.FieldProjection],
 ) result.R[model.Entities] {
 	query := getPostgresEntitiesQuery(
 		projectId,
 		tableName,
-		entityFilter,
+		filterExpression,
 		entityOrders,
 		paginationParams,
+		fieldProjection,
 	)
 
-	rows, err := p.postgres.Query(query)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
 	if err != nil {
 		return result.Err[model.Entities](fmt.Errorf("error querying postgres: %w", err))
@@ -97,17 +102,122 @@ func (p *postgresEntityFetcher) FetchEntities(
 	return result.Ok(entities)
 }
 
+// Embeds the referenced entities of each expanded field in place of their
+// ids. Each expanded field takes a single query for all of the entities
+func (p *postgresEntityFetcher) ExpandEntities(
+	projectId model.ProjectId,
+	entities model.Entities,
+	fieldExpansions model.FieldExpansions,
+) error {
+	for _, fieldExpansion := range fieldExpansions {
+		ids := []string{}
+		seenIds := map[uuid.UUID]bool{}
+
+		for _, entity := range entities {
+			// Optional references that aren't set have no value
+			id, ok := entity[fieldExpansion.FieldName].(uuid.UUID)
+
+			if !ok || seenIds[id] {
+				continue
+			}
+
+			seenIds[id] = true
+			ids = append(ids, id.String())
+		}
+
+		if len(ids) == 0 {
+			continue
+		}
+
+		referencedEntitiesResult := p.fetchEntitiesById(projectId, fieldExpansion, ids)
+
+		if referencedEntitiesResult.IsErr() {
+			return referencedEntitiesResult.UnwrapErr()
+		}
+
+		referencedEntities := referencedEntitiesResult.Unwrap()
+
+		err := p.ExpandEntities(projectId, referencedEntities, fieldExpansion.Expansions)
+
+		if err != nil {
+			return err
+		}
+
+		referencedEntitiesById := map[uuid.UUID]model.Entity{}
+
+		for _, referencedEntity := range referencedEntities {
+			referencedEntitiesById[referencedEntity["id"].(uuid.UUID)] = referencedEntity
+		}
+
+		for _, entity := range entities {
+			id, ok := entity[fieldExpansion.FieldName].(uuid.UUID)
+
+			if !ok {
+				continue
+			}
+
+			if referencedEntity, ok := referencedEntitiesById[id]; ok {
+				entity[fieldExpansion.FieldName] = referencedEntity
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *postgresEntityFetcher) fetchEntitiesById(
+	projectId model.ProjectId,
+	fieldExpansion model.FieldExpansion,
+	ids []string,
+) result.R[model.Entities] {
+	query := getPostgresEntitiesByIdQuery(
+		projectId,
+		fieldExpansion.TableName,
+		ids,
+		fieldExpansion.FieldProjection,
+	)
+
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
+
+	if err != nil {
+		return result.Errf[model.Entities]("error querying postgres: %w", err)
+	}
+
+	defer rows.Close()
+
+	entities := model.Entities{}
+
+	for rows.Next() {
+		entityResult := parseEntityFromSqlRow(rows, fieldExpansion.TableSchema)
+
+		if entityResult.IsErr() {
+			return result.Errf[model.Entities]("error parsing entity: %w", entityResult.UnwrapErr())
+		}
+
+		entities = append(entities, entityResult.Unwrap())
+	}
+
+	return result.Ok(entities)
+}
+
 func parseEntityFromSqlRow(rows *sql.Rows, tableSchema model.TableSchema) result.R[model.Entity] {
+	return parseEntityFromSqlRowAfter(rows, tableSchema)
+}
+
+// Parses an entity from a row whose entity columns come after the given
+// leading columns, which are scanned into the given destinations instead
+func parseEntityFromSqlRowAfter(rows *sql.Rows, tableSchema model.TableSchema, leading ...any) result.R[model.Entity] {
 	entity := model.Entity{}
 
 	columnTypes, _ := rows.ColumnTypes()
+	columnTypes = columnTypes[len(leading):]
 	columns := make([]any, len(columnTypes))
 
 	for i := range columns {
 		columns[i] = new(sql.NullString)
 	}
 
-	err := rows.Scan(columns...)
+	err := rows.Scan(append(leading, columns...)...)
 
 	for i, column := range columns {
 		nullStr := *(column.(*sql.NullString))
@@ -123,7 +233,7 @@ func parseEntityFromSqlRow(rows *sql.Rows, tableSchema model.TableSchema) result
 		fieldName := model.FieldName(columnType.Name())
 
 		if fieldName.String() == "id" {
-			entity[fieldName] = parsePostgresFieldString(str, model.FieldTypeId)
+			entity[fieldName] = parsePostgresFieldString(str, model.FieldDefinition{Type: model.FieldTypeId})
 			continue
 		}
 
@@ -133,7 +243,7 @@ func parseEntityFromSqlRow(rows *sql.Rows, tableSchema model.TableSchema) result
 			return result.Errf[model.Entity]("field: %s is not in table schema", fieldName)
 		}
 
-		entity[fieldName] = parsePostgresFieldString(str, fieldDefinition.Type)
+		entity[fieldName] = parsePostgresFieldString(str, fieldDefinition)
 	}
 
 	if err != nil {
@@ -143,9 +253,9 @@ func parseEntityFromSqlRow(rows *sql.Rows, tableSchema model.TableSchema) result
 	return result.Ok(entity)
 }
 
-func parsePostgresFieldString(str string, fieldType model.FieldType) any {
-	switch fieldType {
-	case model.FieldTypeId:
+func parsePostgresFieldString(str string, fieldDefinition model.FieldDefinition) any {
+	switch fieldDefinition.Type {
+	case model.FieldTypeId, model.FieldTypeReference:
 		uuid, err := uuid.Parse(str)
 
 		if err != nil {
@@ -179,80 +289,418 @@ func parsePostgresFieldString(str string, fieldType model.FieldType) any {
 			panic(fmt.Sprintf("unexpected time format: %s", str))
 		}
 
-		return time
+		return time.UTC()
 	case model.FieldTypeEnum:
 		return str
+	case model.FieldTypeBigInt:
+		bigInt, err := strconv.ParseInt(str, 10, 64)
+
+		if err != nil {
+			panic("couldn't parse bigint in sql response")
+		}
+
+		return bigInt
+	case model.FieldTypeFloat:
+		float, err := strconv.ParseFloat(str, 64)
+
+		if err != nil {
+			panic("couldn't parse float in sql response")
+		}
+
+		return float
+	case model.FieldTypeDecimal:
+		return model.Decimal(str)
+	case model.FieldTypeJson:
+		return model.Json(str)
+	case model.FieldTypeDate:
+		// Dates are scanned as midnight UTC
+		date, err := time.Parse(PostgresTimeFormat, str)
+
+		if err != nil {
+			panic(fmt.Sprintf("unexpected date format: %s", str))
+		}
+
+		return model.Date(date.Format(PostgresDateFormat))
+	case model.FieldTypeDuration:
+		// Already ISO 8601, as connections set intervalstyle to iso_8601
+		return model.Duration(str)
+	case model.FieldTypeArray:
+		if fieldDefinition.ElementType.Unwrap() == model.FieldTypeInteger {
+			integers := pq.Int64Array{}
+
+			err := integers.Scan([]byte(str))
+
+			if err != nil {
+				panic(fmt.Sprintf("couldn't parse integer array in sql response: %s", str))
+			}
+
+			array := make([]int, len(integers))
+
+			for i, integer := range integers {
+				array[i] = int(integer)
+			}
+
+			return array
+		}
+
+		strs := pq.StringArray{}
+
+		err := strs.Scan([]byte(str))
+
+		if err != nil {
+			panic(fmt.Sprintf("couldn't parse string array in sql response: %s", str))
+		}
+
+		return []string(strs)
 	}
 
 	return nil
 }
 
-func getPostgresEntityQuery(projectId model.ProjectId, tableName model.TableName, id model.EntityId) string {
-	return "SELECT * FROM \"" + getPostgresTableName(projectId, tableName) + "\" WHERE id = '" + id.String() + "'"
+func getPostgresEntityQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	id model.EntityId,
+	fieldProjection optional.O[model.FieldProjection],
+) *postgresQuery {
+	query := newPostgresQuery().Write("SELECT ")
+
+	writePostgresSelectColumns(query, fieldProjection)
+
+	return query.
+		Write(" FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" WHERE id = ").
+		WriteArg(id.String())
+}
+
+// The ids are bound as a single array rather than as a list of arguments, so
+// that any number of them can be fetched at once
+func getPostgresEntitiesByIdQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	ids []string,
+	fieldProjection optional.O[model.FieldProjection],
+) *postgresQuery {
+	query := newPostgresQuery().Write("SELECT ")
+
+	writePostgresSelectColumns(query, fieldProjection)
+
+	return query.
+		Write(" FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" WHERE id = ANY(CAST(").
+		WriteArg(pq.Array(ids)).
+		Write(" AS uuid[]))")
+}
+
+func writePostgresSelectColumns(query *postgresQuery, fieldProjection optional.O[model.FieldProjection]) {
+	if fieldProjection.IsNone() {
+		query.Write("*")
+		return
+	}
+
+	query.WriteIdentifier("id")
+
+	for _, fieldName := range fieldProjection.Unwrap() {
+		if fieldName == "id" {
+			continue
+		}
+
+		query.Write(",").WriteIdentifier(fieldName.String())
+	}
+}
+
+func writePostgresFilter(query *postgresQuery, filterExpression model.EntityFilterExpression) {
+	if filterExpression.IsEmpty() {
+		return
+	}
+
+	query.Write(" WHERE ")
+	writePostgresFilterExpression(query, filterExpression)
+}
+
+func writePostgresFilterExpression(query *postgresQuery, filterExpression model.EntityFilterExpression) {
+	switch filterExpression.Type {
+	case model.EntityFilterExpressionTypeField:
+		writePostgresFieldFilter(query, filterExpression.FieldName, filterExpression.FieldFilter)
+	case model.EntityFilterExpressionTypeAnd:
+		writePostgresFilterOperands(query, filterExpression.Operands, " AND ", "TRUE")
+	case model.EntityFilterExpressionTypeOr:
+		writePostgresFilterOperands(query, filterExpression.Operands, " OR ", "FALSE")
+	case model.EntityFilterExpressionTypeNot:
+		query.Write("NOT (")
+		writePostgresFilterExpression(query, filterExpression.Operands[0])
+		query.Write(")")
+	default:
+		panic(fmt.Sprintf("invalid entity filter expression type has entered the system: %v", filterExpression.Type))
+	}
 }
 
-func getPostgresFilterString(entityFilter model.EntityFilter) *string {
-	if len(entityFilter) == 0 {
-		return nil
+func writePostgresFilterOperands(
+	query *postgresQuery,
+	operands []model.EntityFilterExpression,
+	separator string,
+	identity string,
+) {
+	if len(operands) == 0 {
+		query.Write(identity)
+		return
+	}
+
+	query.Write("(")
+
+	for i, operand := range operands {
+		if i > 0 {
+			query.Write(separator)
+		}
+
+		writePostgresFilterExpression(query, operand)
+	}
+
+	query.Write(")")
+}
+
+func writePostgresFieldFilter(query *postgresQuery, fieldName model.FieldName, fieldFilter model.FieldFilter) {
+	if len(fieldFilter.Path) > 0 {
+		writePostgresJsonPathFilter(query, fieldName, fieldFilter)
+		return
+	}
+
+	if fieldFilter.Length {
+		// A null array is counted as having no elements
+		query.Write("COALESCE(cardinality(").WriteIdentifier(fieldName.String()).Write("), 0)")
+	} else {
+		query.WriteIdentifier(fieldName.String())
+	}
+
+	switch fieldFilter.Type {
+	case model.FieldFilterTypeIsNull:
+		query.Write(" IS NULL")
+	case model.FieldFilterTypeIsNotNull:
+		query.Write(" IS NOT NULL")
+	case model.FieldFilterTypeIn:
+		query.Write(" IN (")
+
+		for i, comparator := range fieldFilter.Comparator.([]any) {
+			if i > 0 {
+				query.Write(",")
+			}
+
+			query.WriteArg(getPostgresFieldValue(comparator).Unwrap())
+		}
+
+		query.Write(")")
+	case model.FieldFilterTypeContains:
+		// On json and array fields contains checks that the comparator is a
+		// subset of the stored value rather than a substring of it
+		switch comparator := fieldFilter.Comparator.(type) {
+		case model.Json:
+			query.Write(" @> CAST(").WriteArg(comparator.String()).Write(" AS jsonb)")
+		case []string, []int:
+			query.Write(" @> ").WriteArg(getPostgresFieldValue(comparator).Unwrap())
+		default:
+			query.Write(" ILIKE ").WriteArg("%" + escapePostgresLikePattern(comparator.(string)) + "%")
+		}
+	case model.FieldFilterTypeContainsAny:
+		query.Write(" && ").WriteArg(getPostgresFieldValue(fieldFilter.Comparator).Unwrap())
+	case model.FieldFilterTypeStartsWith:
+		query.Write(" ILIKE ").WriteArg(escapePostgresLikePattern(fieldFilter.Comparator.(string)) + "%")
+	case model.FieldFilterTypeEndsWith:
+		query.Write(" ILIKE ").WriteArg("%" + escapePostgresLikePattern(fieldFilter.Comparator.(string)))
+	default:
+		query.
+			Write(" " + getPostgresComparator(fieldFilter.Type) + " ").
+			WriteArg(getPostgresFieldValue(fieldFilter.Comparator).Unwrap())
 	}
+}
 
-	filters := ""
+func writePostgresJsonPathFilter(query *postgresQuery, fieldName model.FieldName, fieldFilter model.FieldFilter) {
+	switch fieldFilter.Type {
+	case model.FieldFilterTypeIsNull:
+		writePostgresJsonPath(query, fieldName, fieldFilter.Path, "#>>")
+		query.Write(" IS NULL")
+	case model.FieldFilterTypeIsNotNull:
+		writePostgresJsonPath(query, fieldName, fieldFilter.Path, "#>>")
+		query.Write(" IS NOT NULL")
+	case model.FieldFilterTypeIn:
+		writePostgresJsonPath(query, fieldName, fieldFilter.Path, "#>>")
+		query.Write(" IN (")
+
+		for i, comparator := range fieldFilter.Comparator.([]any) {
+			if i > 0 {
+				query.Write(",")
+			}
+
+			query.WriteArg(getPostgresFieldValue(comparator).Unwrap())
+		}
 
-	for k, v := range entityFilter {
-		filters += fmt.Sprintf(
-			"\"%s\" %s %s AND ",
-			k,
-			getPostgresComparator(v.Type),
-			getPostgresFieldValue(v.Comparator).Unwrap(),
-		)
+		query.Write(")")
+	case model.FieldFilterTypeContains:
+		writePostgresJsonPath(query, fieldName, fieldFilter.Path, "#>")
+		query.Write(" @> CAST(").WriteArg(fieldFilter.Comparator.(model.Json).String()).Write(" AS jsonb)")
+	case model.FieldFilterTypeEquals, model.FieldFilterTypeNotEquals:
+		writePostgresJsonPath(query, fieldName, fieldFilter.Path, "#>>")
+		query.
+			Write(" " + getPostgresComparator(fieldFilter.Type) + " ").
+			WriteArg(getPostgresFieldValue(fieldFilter.Comparator).Unwrap())
+	default:
+		// Values that aren't numbers become null rather than failing the cast,
+		// so they never match
+		query.Write("CASE WHEN jsonb_typeof(")
+		writePostgresJsonPath(query, fieldName, fieldFilter.Path, "#>")
+		query.Write(") = 'number' THEN CAST(")
+		writePostgresJsonPath(query, fieldName, fieldFilter.Path, "#>>")
+		query.
+			Write(" AS numeric) END " + getPostgresComparator(fieldFilter.Type) + " CAST(").
+			WriteArg(getPostgresFieldValue(fieldFilter.Comparator).Unwrap()).
+			Write(" AS numeric)")
 	}
+}
 
-	return util.Ptr(strings.TrimSuffix(filters, " AND "))
+// Writes the value at path within a json field, using #> to get it as jsonb
+// or #>> to get it as text
+func writePostgresJsonPath(query *postgresQuery, fieldName model.FieldName, path []string, operator string) {
+	query.
+		Write("(").
+		WriteIdentifier(fieldName.String()).
+		Write(" " + operator + " CAST(").
+		WriteArg(pq.Array(path)).
+		Write(" AS text[]))")
 }
 
-func getPostgresOrderString(orders model.EntityOrders) *string {
+// Escapes the LIKE wildcards so the comparator is matched literally
+func escapePostgresLikePattern(str string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		"%", "\\%",
+		"_", "\\_",
+	).Replace(str)
+}
+
+func writePostgresOrder(query *postgresQuery, orders model.EntityOrders) {
 	if len(orders) == 0 {
-		return nil
+		return
+	}
+
+	query.Write(" ORDER BY ")
+
+	for i, entityOrder := range orders {
+		if i > 0 {
+			query.Write(",")
+		}
+
+		query.
+			WriteIdentifier(entityOrder.FieldName.String()).
+			Write(" " + getPostgresOrder(entityOrder.Type))
+	}
+}
+
+// Matches every entity that comes after the cursor, i.e. those where the
+// first n-1 order keys are equal to the cursor's and the nth sorts after it.
+// Postgres puts nulls last when ascending and first when descending, which
+// is accounted for here so optional fields can be ordered on
+func writePostgresCursor(query *postgresQuery, entityCursor model.EntityCursor) {
+	query.Write("(")
+
+	for i, cursorKey := range entityCursor {
+		if i > 0 {
+			query.Write(" OR ")
+		}
+
+		query.Write("(")
+
+		for _, previousKey := range entityCursor[:i] {
+			writePostgresCursorKeyEquals(query, previousKey)
+			query.Write(" AND ")
+		}
+
+		writePostgresCursorKeyAfter(query, cursorKey)
+
+		query.Write(")")
 	}
 
-	ordersString := ""
+	query.Write(")")
+}
+
+func writePostgresCursorKeyEquals(query *postgresQuery, cursorKey model.EntityCursorKey) {
+	query.WriteIdentifier(cursorKey.FieldName.String())
 
-	for _, entityOrder := range orders {
-		ordersString += fmt.Sprintf(
-			"\"%s\" %s,",
-			entityOrder.FieldName.String(),
-			getPostgresOrder(entityOrder.Type),
-		)
+	if cursorKey.Value == nil {
+		query.Write(" IS NULL")
+		return
 	}
 
-	return util.Ptr(strings.TrimSuffix(ordersString, ","))
+	query.Write(" = ").WriteArg(getPostgresFieldValue(cursorKey.Value).Unwrap())
+}
+
+func writePostgresCursorKeyAfter(query *postgresQuery, cursorKey model.EntityCursorKey) {
+	switch cursorKey.Type {
+	case model.FieldOrderTypeAscending:
+		if cursorKey.Value == nil {
+			query.Write("FALSE")
+			return
+		}
+
+		query.
+			Write("(").
+			WriteIdentifier(cursorKey.FieldName.String()).
+			Write(" > ").
+			WriteArg(getPostgresFieldValue(cursorKey.Value).Unwrap()).
+			Write(" OR ").
+			WriteIdentifier(cursorKey.FieldName.String()).
+			Write(" IS NULL)")
+	case model.FieldOrderTypeDescending:
+		if cursorKey.Value == nil {
+			query.WriteIdentifier(cursorKey.FieldName.String()).Write(" IS NOT NULL")
+			return
+		}
+
+		query.
+			WriteIdentifier(cursorKey.FieldName.String()).
+			Write(" < ").
+			WriteArg(getPostgresFieldValue(cursorKey.Value).Unwrap())
+	default:
+		panic(fmt.Sprintf("invalid field order type has entered the system: %v", cursorKey.Type))
+	}
 }
 
 func getPostgresEntitiesQuery(
 	projectId model.ProjectId,
 	tableName model.TableName,
-	entityFilter model.EntityFilter,
+	filterExpression model.EntityFilterExpression,
 	entityOrders model.EntityOrders,
 	paginationParams model.PaginationParams,
-) string {
-	query := fmt.Sprintf("SELECT * FROM \"%s\"", getPostgresTableName(projectId, tableName))
+	fieldProjection optional.O[model.FieldProjection],
+) *postgresQuery {
+	query := newPostgresQuery().Write("SELECT ")
 
-	filtersString := getPostgresFilterString(entityFilter)
+	writePostgresSelectColumns(query, fieldProjection)
 
-	if filtersString != nil {
-		query += " WHERE " + *filtersString
-	}
+	query.
+		Write(" FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName))
 
-	ordersString := getPostgresOrderString(entityOrders)
+	if paginationParams.Cursor.IsSome() {
+		query.Write(" WHERE ")
+
+		if !filterExpression.IsEmpty() {
+			writePostgresFilterExpression(query, filterExpression)
+			query.Write(" AND ")
+		}
 
-	if ordersString != nil {
-		query += " ORDER BY " + *ordersString
+		writePostgresCursor(query, paginationParams.Cursor.Unwrap())
+	} else {
+		writePostgresFilter(query, filterExpression)
 	}
 
-	query += " LIMIT " + paginationParams.Limit.String() +
-		" OFFSET " + paginationParams.Offset.String()
+	writePostgresOrder(query, entityOrders)
 
-	fmt.Println(query)
+	query.
+		Write(" LIMIT ").
+		WriteArg(uint(paginationParams.Limit)).
+		Write(" OFFSET ").
+		WriteArg(uint(paginationParams.Offset))
 
 	return query
 }
@@ -269,6 +717,9 @@ func getPostgresComparator(fieldFilterType model.FieldFilterType) string {
 		return "<"
 	case model.FieldFilterTypeLessThanEq:
 		return "<="
+	case model.FieldFilterTypeNotEquals:
+		// Unlike <>, this also matches rows where the field is null
+		return "IS DISTINCT FROM"
 	}
 	panic(fmt.Sprintf("invalid field filter type has entered the system: %v", fieldFilterType))
 }
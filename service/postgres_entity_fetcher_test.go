@@ -0,0 +1,193 @@
+package service
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"testing"
+)
+
+func TestGetPostgresEntitiesQuery(t *testing.T) {
+	tests := []struct {
+		name             string
+		tableName        model.TableName
+		filterExpression model.EntityFilterExpression
+		entityOrders     model.EntityOrders
+		paginationParams model.PaginationParams
+		fieldProjection  optional.O[model.FieldProjection]
+		expectedSql      string
+		expectedArgs     []any
+	}{
+		{
+			name:             "no filter",
+			tableName:        "users",
+			filterExpression: model.EmptyEntityFilterExpression(),
+			entityOrders:     model.EntityOrders{},
+			paginationParams: model.PaginationParams{Limit: 20, Offset: 0},
+			fieldProjection:  optional.None[model.FieldProjection](),
+			expectedSql:      `SELECT * FROM "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-users" LIMIT $1 OFFSET $2`,
+			expectedArgs:     []any{uint(20), uint(0)},
+		},
+		{
+			name:      "quoted identifiers and injected comparator",
+			tableName: `my"table`,
+			filterExpression: model.EntityFilter{
+				`we"ird`: {{Type: model.FieldFilterTypeEquals, Comparator: testInjection}},
+			}.ToExpression(),
+			entityOrders: model.EntityOrders{
+				{Type: model.FieldOrderTypeDescending, FieldName: `we"ird`},
+			},
+			paginationParams: model.PaginationParams{Limit: 5, Offset: 10},
+			fieldProjection:  optional.Some(model.FieldProjection{`we"ird`, "id"}),
+			expectedSql: `SELECT "id","we""ird" FROM "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-my""table"` +
+				` WHERE ("we""ird" = $1) ORDER BY "we""ird" DESC LIMIT $2 OFFSET $3`,
+			expectedArgs: []any{testInjection, uint(5), uint(10)},
+		},
+		{
+			name:      "boolean tree with in and string matching",
+			tableName: "users",
+			filterExpression: model.EntityFilterExpression{
+				Type: model.EntityFilterExpressionTypeOr,
+				Operands: []model.EntityFilterExpression{
+					{
+						Type:        model.EntityFilterExpressionTypeField,
+						FieldName:   "name",
+						FieldFilter: model.FieldFilter{Type: model.FieldFilterTypeIn, Comparator: []any{"a", testInjection}},
+					},
+					{
+						Type: model.EntityFilterExpressionTypeNot,
+						Operands: []model.EntityFilterExpression{{
+							Type:        model.EntityFilterExpressionTypeField,
+							FieldName:   "name",
+							FieldFilter: model.FieldFilter{Type: model.FieldFilterTypeStartsWith, Comparator: "50%_"},
+						}},
+					},
+					{
+						Type:        model.EntityFilterExpressionTypeField,
+						FieldName:   "age",
+						FieldFilter: model.FieldFilter{Type: model.FieldFilterTypeIsNull},
+					},
+				},
+			},
+			entityOrders:     model.EntityOrders{},
+			paginationParams: model.PaginationParams{Limit: 20, Offset: 0},
+			fieldProjection:  optional.None[model.FieldProjection](),
+			expectedSql: `SELECT * FROM "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-users"` +
+				` WHERE ("name" IN ($1,$2) OR NOT ("name" ILIKE $3) OR "age" IS NULL) LIMIT $4 OFFSET $5`,
+			expectedArgs: []any{"a", testInjection, `50\%\_%`, uint(20), uint(0)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := getPostgresEntitiesQuery(
+				testProjectId,
+				test.tableName,
+				test.filterExpression,
+				test.entityOrders,
+				test.paginationParams,
+				test.fieldProjection,
+			)
+
+			assertPostgresQuery(t, query, test.expectedSql, test.expectedArgs)
+		})
+	}
+}
+
+func TestWritePostgresCursor(t *testing.T) {
+	const id = "0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"
+
+	tests := []struct {
+		name         string
+		entityCursor model.EntityCursor
+		expectedSql  string
+		expectedArgs []any
+	}{
+		{
+			name: "ascending, nulls last",
+			entityCursor: model.EntityCursor{
+				{FieldName: "age", Type: model.FieldOrderTypeAscending, Value: 30},
+				{FieldName: "id", Type: model.FieldOrderTypeAscending, Value: id},
+			},
+			expectedSql: `((("age" > $1 OR "age" IS NULL))` +
+				` OR ("age" = $2 AND ("id" > $3 OR "id" IS NULL)))`,
+			expectedArgs: []any{30, 30, id},
+		},
+		{
+			name: "ascending from a null, only later nulls follow",
+			entityCursor: model.EntityCursor{
+				{FieldName: "age", Type: model.FieldOrderTypeAscending, Value: nil},
+				{FieldName: "id", Type: model.FieldOrderTypeAscending, Value: id},
+			},
+			expectedSql:  `((FALSE) OR ("age" IS NULL AND ("id" > $1 OR "id" IS NULL)))`,
+			expectedArgs: []any{id},
+		},
+		{
+			name: "descending, nulls first",
+			entityCursor: model.EntityCursor{
+				{FieldName: "age", Type: model.FieldOrderTypeDescending, Value: 30},
+				{FieldName: "id", Type: model.FieldOrderTypeAscending, Value: id},
+			},
+			expectedSql:  `(("age" < $1) OR ("age" = $2 AND ("id" > $3 OR "id" IS NULL)))`,
+			expectedArgs: []any{30, 30, id},
+		},
+		{
+			name: "descending from a null, every value follows",
+			entityCursor: model.EntityCursor{
+				{FieldName: "age", Type: model.FieldOrderTypeDescending, Value: nil},
+				{FieldName: "id", Type: model.FieldOrderTypeDescending, Value: id},
+			},
+			expectedSql:  `(("age" IS NOT NULL) OR ("age" IS NULL AND "id" < $1))`,
+			expectedArgs: []any{id},
+		},
+		{
+			name: "several keys with quoted names",
+			entityCursor: model.EntityCursor{
+				{FieldName: `we"ird`, Type: model.FieldOrderTypeDescending, Value: testInjection},
+				{FieldName: "age", Type: model.FieldOrderTypeAscending, Value: nil},
+				{FieldName: "id", Type: model.FieldOrderTypeAscending, Value: id},
+			},
+			expectedSql: `(("we""ird" < $1)` +
+				` OR ("we""ird" = $2 AND FALSE)` +
+				` OR ("we""ird" = $3 AND "age" IS NULL AND ("id" > $4 OR "id" IS NULL)))`,
+			expectedArgs: []any{testInjection, testInjection, testInjection, id},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := newPostgresQuery()
+
+			writePostgresCursor(query, test.entityCursor)
+
+			assertPostgresQuery(t, query, test.expectedSql, test.expectedArgs)
+		})
+	}
+}
+
+func TestGetPostgresEntitiesQueryWithCursor(t *testing.T) {
+	query := getPostgresEntitiesQuery(
+		testProjectId,
+		"users",
+		model.EntityFilter{
+			"age": {{Type: model.FieldFilterTypeGreaterThan, Comparator: 18}},
+		}.ToExpression(),
+		model.EntityOrders{
+			{Type: model.FieldOrderTypeAscending, FieldName: "id"},
+		},
+		model.PaginationParams{
+			Limit: 21,
+			Cursor: optional.Some(model.EntityCursor{
+				{FieldName: "id", Type: model.FieldOrderTypeAscending, Value: "0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"},
+			}),
+		},
+		optional.None[model.FieldProjection](),
+	)
+
+	assertPostgresQuery(
+		t,
+		query,
+		`SELECT * FROM "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-users"`+
+			` WHERE ("age" > $1) AND ((("id" > $2 OR "id" IS NULL))) ORDER BY "id" ASC LIMIT $3 OFFSET $4`,
+		[]any{18, "0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d", uint(21), uint(0)},
+	)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"crudly/errs"
+	"crudly/model"
+	"crudly/util/result"
+	"database/sql"
+	"fmt"
+)
+
+type postgresEntityLinker struct {
+	postgres *sql.DB
+}
+
+func NewPostgresEntityLinker(postgres *sql.DB) postgresEntityLinker {
+	return postgresEntityLinker{
+		postgres,
+	}
+}
+
+// Returns whether the link was created, as linking entities that are already
+// linked does nothing
+func (p *postgresEntityLinker) CreateLink(
+	projectId model.ProjectId,
+	linkTableName model.TableName,
+	linkFields model.LinkFields,
+	id model.EntityId,
+	ownId model.EntityId,
+	otherId model.EntityId,
+) result.R[bool] {
+	query := getPostgresLinkCreationQuery(projectId, linkTableName, linkFields, id, ownId, otherId)
+
+	res, err := p.postgres.Exec(query.String(), query.Args()...)
+
+	if err != nil {
+		if isPostgresError(err, postgresForeignKeyViolationCode) {
+			return result.Err[bool](errs.NewReferencedEntityNotFoundError(getPostgresKeyViolationFields(err)))
+		}
+
+		return result.Errf[bool]("error querying postgres: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+
+	if err != nil {
+		return result.Errf[bool]("error determining affected postgres rows: %w", err)
+	}
+
+	return result.Ok(count > 0)
+}
+
+func (p *postgresEntityLinker) DeleteLink(
+	projectId model.ProjectId,
+	linkTableName model.TableName,
+	linkFields model.LinkFields,
+	ownId model.EntityId,
+	otherId model.EntityId,
+) error {
+	query := getPostgresLinkDeletionQuery(projectId, linkTableName, linkFields, ownId, otherId)
+
+	res, err := p.postgres.Exec(query.String(), query.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+
+	if err != nil {
+		return fmt.Errorf("error determining affected postgres rows: %w", err)
+	}
+
+	if count == 0 {
+		return errs.LinkNotFoundError{}
+	}
+
+	return nil
+}
+
+// The existence check covers links that already exist, and the conflict
+// clause covers concurrent links, which the link index rejects
+func getPostgresLinkCreationQuery(
+	projectId model.ProjectId,
+	linkTableName model.TableName,
+	linkFields model.LinkFields,
+	id model.EntityId,
+	ownId model.EntityId,
+	otherId model.EntityId,
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("INSERT INTO ").
+		WriteIdentifier(getPostgresTableName(projectId, linkTableName)).
+		Write(" (").
+		WriteIdentifier("id").
+		Write(", ").
+		WriteIdentifier(linkFields.Own.String()).
+		Write(", ").
+		WriteIdentifier(linkFields.Other.String()).
+		Write(") SELECT CAST(").
+		WriteArg(id.String()).
+		Write(" AS uuid), CAST(").
+		WriteArg(ownId.String()).
+		Write(" AS uuid), CAST(").
+		WriteArg(otherId.String()).
+		Write(" AS uuid) WHERE NOT EXISTS (SELECT 1 FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, linkTableName)).
+		Write(" WHERE ")
+
+	writePostgresLinkCondition(query, linkFields, ownId, otherId)
+
+	return query.Write(") ON CONFLICT DO NOTHING")
+}
+
+func getPostgresLinkDeletionQuery(
+	projectId model.ProjectId,
+	linkTableName model.TableName,
+	linkFields model.LinkFields,
+	ownId model.EntityId,
+	otherId model.EntityId,
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("DELETE FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, linkTableName)).
+		Write(" WHERE ")
+
+	writePostgresLinkCondition(query, linkFields, ownId, otherId)
+
+	return query
+}
+
+func writePostgresLinkCondition(
+	query *postgresQuery,
+	linkFields model.LinkFields,
+	ownId model.EntityId,
+	otherId model.EntityId,
+) {
+	query.
+		WriteIdentifier(linkFields.Own.String()).
+		Write(" = ").
+		WriteArg(ownId.String()).
+		Write(" AND ").
+		WriteIdentifier(linkFields.Other.String()).
+		Write(" = ").
+		WriteArg(otherId.String())
+}
@@ -3,10 +3,10 @@ package service
 import (
 	"crudly/errs"
 	"crudly/model"
+	"crudly/util"
 	"crudly/util/result"
 	"database/sql"
 	"fmt"
-	"strings"
 )
 
 type postgresEntityUpdater struct {
@@ -33,9 +33,17 @@ func (p *postgresEntityUpdater) UpdateEntity(
 		partialEntity,
 	)
 
-	rows, err := p.postgres.Query(query)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
 	if err != nil {
+		if isPostgresError(err, postgresUniqueViolationCode) {
+			return result.Err[model.Entity](errs.NewUniqueViolationError(getPostgresKeyViolationFields(err)))
+		}
+
+		if isPostgresError(err, postgresForeignKeyViolationCode) {
+			return result.Err[model.Entity](errs.NewReferencedEntityNotFoundError(getPostgresKeyViolationFields(err)))
+		}
+
 		return result.Errf[model.Entity]("error querying postgres: %w", err)
 	}
 
@@ -53,25 +61,78 @@ func getPostgresEntityUpdateQuery(
 	tableName model.TableName,
 	id model.EntityId,
 	partialEntity model.PartialEntity,
-) string {
-	setQuery := ""
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("UPDATE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" SET ")
+
+	for i, k := range util.GetSortedMapKeys(partialEntity) {
+		if i > 0 {
+			query.Write(",")
+		}
+
+		v := partialEntity[k]
+
+		query.WriteIdentifier(k.String()).Write(" = ")
+
+		if arrayUpdate, ok := v.(model.ArrayUpdate); ok {
+			writePostgresArrayUpdate(query, k, arrayUpdate)
+			continue
+		}
 
-	for k, v := range partialEntity {
 		valResult := getPostgresFieldValue(v)
 
 		if valResult.IsErr() {
 			panic(fmt.Sprintf("error parsing field: %s: %s", k, valResult.UnwrapErr().Error()))
 		}
 
-		setQuery += fmt.Sprintf("\"%s\" = %s,", k, valResult.Unwrap())
+		query.WriteArg(valResult.Unwrap())
 	}
 
-	setQuery = strings.TrimSuffix(setQuery, ",")
+	return query.
+		Write(" WHERE id = ").
+		WriteArg(id.String()).
+		Write(" RETURNING *")
+}
 
-	return fmt.Sprintf(
-		"UPDATE \"%s\" SET %s WHERE id = '%s' RETURNING *",
-		getPostgresTableName(projectId, tableName),
-		setQuery,
-		id.String(),
-	)
+// Array updates are made relative to the stored array, so they can't lose
+// elements written by a concurrent update
+func writePostgresArrayUpdate(query *postgresQuery, fieldName model.FieldName, arrayUpdate model.ArrayUpdate) {
+	switch arrayUpdate.Type {
+	case model.ArrayUpdateTypeAppend:
+		query.
+			Write("array_cat(").
+			WriteIdentifier(fieldName.String()).
+			Write(", ").
+			WriteArg(getPostgresFieldValue(arrayUpdate.Elements).Unwrap()).
+			Write(")")
+	case model.ArrayUpdateTypeRemove:
+		// array_remove drops every occurrence of a single element, so it's
+		// nested once per element to remove
+		elements := []any{}
+
+		switch v := arrayUpdate.Elements.(type) {
+		case []string:
+			for _, element := range v {
+				elements = append(elements, element)
+			}
+		case []int:
+			for _, element := range v {
+				elements = append(elements, element)
+			}
+		}
+
+		for range elements {
+			query.Write("array_remove(")
+		}
+
+		query.WriteIdentifier(fieldName.String())
+
+		for _, element := range elements {
+			query.Write(", ").WriteArg(element).Write(")")
+		}
+	default:
+		panic(fmt.Sprintf("invalid array update type has entered the system: %+v", arrayUpdate.Type))
+	}
 }
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"crudly/model"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestGetPostgresEntityUpdateQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		tableName     model.TableName
+		partialEntity model.PartialEntity
+		expectedSql   string
+		expectedArgs  []any
+	}{
+		{
+			name:      "fields are set in name order",
+			tableName: `my"table`,
+			partialEntity: model.PartialEntity{
+				`we"ird`: testInjection,
+				"age":    nil,
+			},
+			expectedSql: `UPDATE "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-my""table"` +
+				` SET "age" = $1,"we""ird" = $2 WHERE id = $3 RETURNING *`,
+			expectedArgs: []any{nil, testInjection, "0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"},
+		},
+		{
+			name:      "array updates",
+			tableName: "users",
+			partialEntity: model.PartialEntity{
+				"scores": model.ArrayUpdate{Type: model.ArrayUpdateTypeAppend, Elements: []int{1}},
+				"tags":   model.ArrayUpdate{Type: model.ArrayUpdateTypeRemove, Elements: []string{"a", testInjection}},
+			},
+			expectedSql: `UPDATE "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-users"` +
+				` SET "scores" = array_cat("scores", $1),"tags" = array_remove(array_remove("tags", $2), $3)` +
+				` WHERE id = $4 RETURNING *`,
+			expectedArgs: []any{pq.Array([]int64{1}), "a", testInjection, "0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := getPostgresEntityUpdateQuery(testProjectId, test.tableName, testEntityId, test.partialEntity)
+
+			assertPostgresQuery(t, query, test.expectedSql, test.expectedArgs)
+		})
+	}
+}
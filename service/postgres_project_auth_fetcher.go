@@ -21,7 +21,7 @@ func NewPostgresProjectAuthFetcher(postgres *sql.DB) postgresProjectAuthFetcher
 func (p *postgresProjectAuthFetcher) FetchProjectAuthInfo(id model.ProjectId) result.R[model.ProjectAuthInfo] {
 	query := getPostgresFetchProjectAuthQuery(id)
 
-	rows, err := p.postgres.Query(query)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
 	if err != nil {
 		return result.Err[model.ProjectAuthInfo](fmt.Errorf("error querying postgres: %w", err))
@@ -44,6 +44,8 @@ func (p *postgresProjectAuthFetcher) FetchProjectAuthInfo(id model.ProjectId) re
 	})
 }
 
-func getPostgresFetchProjectAuthQuery(id model.ProjectId) string {
-	return "SELECT salt, saltedhash FROM projects WHERE id = '" + id.String() + "'"
+func getPostgresFetchProjectAuthQuery(id model.ProjectId) *postgresQuery {
+	return newPostgresQuery().
+		Write("SELECT salt, saltedhash FROM projects WHERE id = ").
+		WriteArg(id.String())
 }
@@ -22,7 +22,7 @@ func (p *postgresProjectCreator) CreateProject(
 ) error {
 	projectCreationQuery := getPostgresProjectCreationQuery(id, authInfo.Salt, authInfo.SaltedHash)
 
-	_, err := p.postgres.Exec(projectCreationQuery)
+	_, err := p.postgres.Exec(projectCreationQuery.String(), projectCreationQuery.Args()...)
 
 	if err != nil {
 		return fmt.Errorf("error executing postgres query: %w", err)
@@ -30,7 +30,15 @@ func (p *postgresProjectCreator) CreateProject(
 
 	schemaTableCreationQuery := getPostgresSchemaTableCreationQuery(id)
 
-	_, err = p.postgres.Exec(schemaTableCreationQuery)
+	_, err = p.postgres.Exec(schemaTableCreationQuery.String(), schemaTableCreationQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error executing postgres query: %w", err)
+	}
+
+	indexTableCreationQuery := getPostgresIndexTableCreationQuery(id)
+
+	_, err = p.postgres.Exec(indexTableCreationQuery.String(), indexTableCreationQuery.Args()...)
 
 	if err != nil {
 		return fmt.Errorf("error executing postgres query: %w", err)
@@ -39,10 +47,20 @@ func (p *postgresProjectCreator) CreateProject(
 	return nil
 }
 
-func getPostgresSchemaTableCreationQuery(id model.ProjectId) string {
-	return "CREATE TABLE \"" + getPostgresSchemaTableName(id) + "\"(name varchar, schema varchar)"
+func getPostgresSchemaTableCreationQuery(id model.ProjectId) *postgresQuery {
+	return newPostgresQuery().
+		Write("CREATE TABLE ").
+		WriteIdentifier(getPostgresSchemaTableName(id)).
+		Write("(name varchar, schema varchar)")
 }
 
-func getPostgresProjectCreationQuery(id model.ProjectId, salt string, saltedHash string) string {
-	return "INSERT INTO projects(id, salt, saltedhash) VALUES ('" + id.String() + "','" + salt + "','" + saltedHash + "')"
+func getPostgresProjectCreationQuery(id model.ProjectId, salt string, saltedHash string) *postgresQuery {
+	return newPostgresQuery().
+		Write("INSERT INTO projects(id, salt, saltedhash) VALUES (").
+		WriteArg(id.String()).
+		Write(",").
+		WriteArg(salt).
+		Write(",").
+		WriteArg(saltedHash).
+		Write(")")
 }
@@ -0,0 +1,14 @@
+package service
+
+import "testing"
+
+func TestGetPostgresProjectCreationQuery(t *testing.T) {
+	query := getPostgresProjectCreationQuery(testProjectId, testInjection, `hash"`)
+
+	assertPostgresQuery(
+		t,
+		query,
+		`INSERT INTO projects(id, salt, saltedhash) VALUES ($1,$2,$3)`,
+		[]any{"6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c", testInjection, `hash"`},
+	)
+}
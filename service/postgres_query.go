@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// postgresQuery accumulates a SQL statement together with the positional
+// arguments referenced by its $n placeholders. Identifiers are always
+// quoted and values are always passed as arguments, so nothing user supplied
+// is ever concatenated into the SQL itself.
+type postgresQuery struct {
+	builder    strings.Builder
+	args       []any
+	inlineArgs bool
+}
+
+func newPostgresQuery() *postgresQuery {
+	return &postgresQuery{}
+}
+
+// newPostgresLiteralQuery is for statements where postgres doesn't accept
+// placeholders, such as the filter of a partial index. Values given to
+// WriteArg are written as escaped literals instead.
+func newPostgresLiteralQuery() *postgresQuery {
+	return &postgresQuery{
+		inlineArgs: true,
+	}
+}
+
+// Write appends raw SQL. It must only ever be given trusted, static strings.
+func (q *postgresQuery) Write(sql string) *postgresQuery {
+	q.builder.WriteString(sql)
+	return q
+}
+
+func (q *postgresQuery) WriteIdentifier(name string) *postgresQuery {
+	q.builder.WriteString(pq.QuoteIdentifier(name))
+	return q
+}
+
+// WriteArg appends a $n placeholder and records the value it refers to.
+func (q *postgresQuery) WriteArg(value any) *postgresQuery {
+	if q.inlineArgs {
+		literalResult := getPostgresValueLiteral(value)
+
+		if literalResult.IsErr() {
+			panic(fmt.Sprintf("error writing query literal: %s", literalResult.UnwrapErr()))
+		}
+
+		q.builder.WriteString(literalResult.Unwrap())
+		return q
+	}
+
+	q.args = append(q.args, value)
+	q.builder.WriteString(fmt.Sprintf("$%d", len(q.args)))
+	return q
+}
+
+func (q *postgresQuery) String() string {
+	return q.builder.String()
+}
+
+func (q *postgresQuery) Args() []any {
+	return q.args
+}
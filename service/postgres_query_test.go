@@ -0,0 +1,63 @@
+package service
+
+import (
+	"crudly/model"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var testProjectId = model.ProjectId(uuid.MustParse("6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c"))
+
+var testEntityId = model.EntityId(uuid.MustParse("0b0e3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"))
+
+// Used as both an identifier and a value, to check neither can break out of
+// its quoting
+const testInjection = `x' OR '1'='1`
+
+func assertPostgresQuery(t *testing.T, query *postgresQuery, expectedSql string, expectedArgs []any) {
+	t.Helper()
+
+	if query.String() != expectedSql {
+		t.Errorf("unexpected sql:\n got: %s\nwant: %s", query.String(), expectedSql)
+	}
+
+	if !reflect.DeepEqual(query.Args(), expectedArgs) {
+		t.Errorf("unexpected args:\n got: %#v\nwant: %#v", query.Args(), expectedArgs)
+	}
+}
+
+func TestPostgresQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        *postgresQuery
+		expectedSql  string
+		expectedArgs []any
+	}{
+		{
+			name:         "identifiers are quoted",
+			query:        newPostgresQuery().Write("SELECT ").WriteIdentifier(`a"b`).Write(",").WriteIdentifier(testInjection),
+			expectedSql:  `SELECT "a""b","x' OR '1'='1"`,
+			expectedArgs: nil,
+		},
+		{
+			name:         "args are numbered in order",
+			query:        newPostgresQuery().WriteArg(1).Write(",").WriteArg(testInjection).Write(",").WriteArg(nil),
+			expectedSql:  `$1,$2,$3`,
+			expectedArgs: []any{1, testInjection, nil},
+		},
+		{
+			name:         "literal queries escape args inline",
+			query:        newPostgresLiteralQuery().WriteArg(testInjection).Write(",").WriteArg(int64(2)).Write(",").WriteArg(true),
+			expectedSql:  `'x'' OR ''1''=''1',2,true`,
+			expectedArgs: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assertPostgresQuery(t, test.query, test.expectedSql, test.expectedArgs)
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"crudly/model"
 	"crudly/util/result"
 	"database/sql"
-	"fmt"
 )
 
 type postgresRateLimitStore struct {
@@ -21,7 +20,7 @@ func NewPostgresRateLimitStore(postgres *sql.DB) postgresRateLimitStore {
 func (p *postgresRateLimitStore) SetRateLimit(projectId model.ProjectId, rateLimit uint) error {
 	query := getPostgresUpdateRateLimitQuery(projectId, rateLimit)
 
-	_, err := p.postgres.Exec(query)
+	_, err := p.postgres.Exec(query.String(), query.Args()...)
 
 	if err != nil {
 		return err
@@ -33,7 +32,7 @@ func (p *postgresRateLimitStore) SetRateLimit(projectId model.ProjectId, rateLim
 func (p *postgresRateLimitStore) GetRateLimit(projectId model.ProjectId) result.R[uint] {
 	query := getPostgresRateLimitQuery(projectId)
 
-	rows, err := p.postgres.Query(query)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
 	if err != nil {
 		return result.Errf[uint]("error querying postgres: %w", err)
@@ -52,17 +51,17 @@ func (p *postgresRateLimitStore) GetRateLimit(projectId model.ProjectId) result.
 	return result.Ok(rateLimit)
 }
 
-func getPostgresUpdateRateLimitQuery(projectId model.ProjectId, rateLimit uint) string {
-	return fmt.Sprintf(
-		`INSERT INTO rateLimit (projectId, rateLimit)
-			VALUES ('%s', %d)
-			ON CONFLICT (projectId)
-			DO UPDATE SET rateLimit = EXCLUDED.rateLimit;`,
-		projectId.String(),
-		rateLimit,
-	)
+func getPostgresUpdateRateLimitQuery(projectId model.ProjectId, rateLimit uint) *postgresQuery {
+	return newPostgresQuery().
+		Write("INSERT INTO rateLimit (projectId, rateLimit) VALUES (").
+		WriteArg(projectId.String()).
+		Write(", ").
+		WriteArg(rateLimit).
+		Write(") ON CONFLICT (projectId) DO UPDATE SET rateLimit = EXCLUDED.rateLimit")
 }
 
-func getPostgresRateLimitQuery(projectId model.ProjectId) string {
-	return fmt.Sprintf("SELECT rateLimit FROM rateLimit WHERE projectId = '%s'", projectId.String())
+func getPostgresRateLimitQuery(projectId model.ProjectId) *postgresQuery {
+	return newPostgresQuery().
+		Write("SELECT rateLimit FROM rateLimit WHERE projectId = ").
+		WriteArg(projectId.String())
 }
@@ -22,6 +22,7 @@ func (p *postgresTableCreator) CreateTable(
 	projectId model.ProjectId,
 	name model.TableName,
 	schema model.TableSchema,
+	indexes model.TableIndexes,
 ) error {
 	tx, err := p.postgres.BeginTx(context.Background(), nil)
 
@@ -36,7 +37,7 @@ func (p *postgresTableCreator) CreateTable(
 		schema,
 	)
 
-	_, err = tx.Exec(tableCreationQuery)
+	_, err = tx.Exec(tableCreationQuery.String(), tableCreationQuery.Args()...)
 
 	if err != nil {
 		return fmt.Errorf("error creating postgres table: %w", err)
@@ -48,12 +49,18 @@ func (p *postgresTableCreator) CreateTable(
 		schema,
 	)
 
-	_, err = tx.Exec(schemaCreationQuery)
+	_, err = tx.Exec(schemaCreationQuery.String(), schemaCreationQuery.Args()...)
 
 	if err != nil {
 		return fmt.Errorf("error creating postgres table: %w", err)
 	}
 
+	err = createPostgresIndexes(tx, projectId, name, indexes)
+
+	if err != nil {
+		return err
+	}
+
 	err = tx.Commit()
 
 	if err != nil {
@@ -67,13 +74,15 @@ func getPostgresTableSchemaCreationQuery(
 	projectId model.ProjectId,
 	name model.TableName,
 	schema model.TableSchema,
-) string {
-	return fmt.Sprintf(
-		"INSERT INTO \"%s\"(%s, %s) VALUES ('%s', '%s')",
-		getPostgresSchemaTableName(projectId),
-		"name", "schema",
-		name, getSchemaJson(schema),
-	)
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("INSERT INTO ").
+		WriteIdentifier(getPostgresSchemaTableName(projectId)).
+		Write("(name, schema) VALUES (").
+		WriteArg(name.String()).
+		Write(", ").
+		WriteArg(getSchemaJson(schema)).
+		Write(")")
 }
 
 func getSchemaJson(schema model.TableSchema) string {
@@ -90,32 +99,45 @@ func getPostgresTableCreationQuery(
 	projectId model.ProjectId,
 	name model.TableName,
 	schema model.TableSchema,
-) string {
-	query := "CREATE TABLE \"" + getPostgresTableName(projectId, name) + "\"("
-	query += getPostgresFieldQuery("id", model.FieldDefinition{
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("CREATE TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, name)).
+		Write("(")
+
+	writePostgresField(query, projectId, "id", model.FieldDefinition{
 		Type:       model.FieldTypeId,
 		PrimaryKey: true,
 	})
+
 	for k, v := range schema {
-		query += "," + getPostgresFieldQuery(k, v)
+		query.Write(",")
+		writePostgresField(query, projectId, k, v)
 	}
-	query = query + ")"
 
-	return query
+	return query.Write(")")
 }
 
-func getPostgresFieldQuery(key model.FieldName, fieldDefinition model.FieldDefinition) string {
-	fieldQuery := fmt.Sprintf(
-		"\"%s\" %s",
-		key.String(),
-		getPostgresDatatype(fieldDefinition.Type),
-	)
+func writePostgresField(
+	query *postgresQuery,
+	projectId model.ProjectId,
+	key model.FieldName,
+	fieldDefinition model.FieldDefinition,
+) {
+	query.
+		WriteIdentifier(key.String()).
+		Write(" " + getPostgresDatatype(fieldDefinition))
 
 	if fieldDefinition.PrimaryKey {
-		fieldQuery += " PRIMARY KEY"
+		query.Write(" PRIMARY KEY")
 	} else if !fieldDefinition.IsOptional {
-		fieldQuery += " NOT NULL"
+		query.Write(" NOT NULL")
+	}
+
+	if fieldDefinition.Unique {
+		query.Write(" UNIQUE")
 	}
 
-	return fieldQuery
+	writePostgresFieldReference(query, projectId, fieldDefinition)
+	writePostgresFieldCheck(query, key, fieldDefinition)
 }
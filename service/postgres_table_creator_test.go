@@ -0,0 +1,47 @@
+package service
+
+import (
+	"crudly/model"
+	"crudly/util/optional"
+	"testing"
+)
+
+func TestGetPostgresTableSchemaCreationQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		tableName    model.TableName
+		schema       model.TableSchema
+		expectedSql  string
+		expectedArgs []any
+	}{
+		{
+			name:         "empty schema",
+			tableName:    "users",
+			schema:       model.TableSchema{},
+			expectedSql:  `INSERT INTO "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-tables"(name, schema) VALUES ($1, $2)`,
+			expectedArgs: []any{"users", `{}`},
+		},
+		{
+			name:      "names and values are bound",
+			tableName: testInjection,
+			schema: model.TableSchema{
+				`we"ird`: {Type: model.FieldTypeEnum, Values: optional.Some([]string{testInjection})},
+			},
+			expectedSql: `INSERT INTO "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-tables"(name, schema) VALUES ($1, $2)`,
+			expectedArgs: []any{
+				testInjection,
+				`{"we\"ird":{"Type":5,"Values":["x' OR '1'='1"],"IsOptional":false,"PrimaryKey":false,"Unique":false,` +
+					`"Precision":null,"Scale":null,"ElementType":null,"MinLength":null,"MaxLength":null,"Pattern":null,` +
+					`"Format":null,"Min":null,"Max":null,"NotBefore":null,"NotAfter":null,"References":null,"OnDelete":null}}`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := getPostgresTableSchemaCreationQuery(testProjectId, test.tableName, test.schema)
+
+			assertPostgresQuery(t, query, test.expectedSql, test.expectedArgs)
+		})
+	}
+}
@@ -26,7 +26,24 @@ func (p *postgresTableDeleter) DeleteTable(projectId model.ProjectId, name model
 	defer tx.Rollback()
 	deleteSchemaQuery := getPostgresSchemaDeletionQuery(projectId, name)
 
-	_, err = tx.Exec(deleteSchemaQuery)
+	_, err = tx.Exec(deleteSchemaQuery.String(), deleteSchemaQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	// Dropping the table drops its indexes, but not the rows recording them
+	indexTableCreationQuery := getPostgresIndexTableCreationQuery(projectId)
+
+	_, err = tx.Exec(indexTableCreationQuery.String(), indexTableCreationQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	indexRowDeletionQuery := getPostgresIndexRowDeletionQuery(projectId, name)
+
+	_, err = tx.Exec(indexRowDeletionQuery.String(), indexRowDeletionQuery.Args()...)
 
 	if err != nil {
 		return fmt.Errorf("error querying postgres: %w", err)
@@ -34,7 +51,7 @@ func (p *postgresTableDeleter) DeleteTable(projectId model.ProjectId, name model
 
 	deleteTableQuery := getPostgresDeleteTableQuery(projectId, name)
 
-	_, err = tx.Exec(deleteTableQuery)
+	_, err = tx.Exec(deleteTableQuery.String(), deleteTableQuery.Args()...)
 
 	if err != nil {
 		return fmt.Errorf("error querying postgres: %w", err)
@@ -49,17 +66,16 @@ func (p *postgresTableDeleter) DeleteTable(projectId model.ProjectId, name model
 	return nil
 }
 
-func getPostgresDeleteTableQuery(projectId model.ProjectId, name model.TableName) string {
-	return fmt.Sprintf(
-		"DROP TABLE \"%s\"",
-		getPostgresTableName(projectId, name),
-	)
+func getPostgresDeleteTableQuery(projectId model.ProjectId, name model.TableName) *postgresQuery {
+	return newPostgresQuery().
+		Write("DROP TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, name))
 }
 
-func getPostgresSchemaDeletionQuery(projectId model.ProjectId, name model.TableName) string {
-	return fmt.Sprintf(
-		"DELETE FROM \"%s\" WHERE name = '%s'",
-		getPostgresSchemaTableName(projectId),
-		name.String(),
-	)
+func getPostgresSchemaDeletionQuery(projectId model.ProjectId, name model.TableName) *postgresQuery {
+	return newPostgresQuery().
+		Write("DELETE FROM ").
+		WriteIdentifier(getPostgresSchemaTableName(projectId)).
+		Write(" WHERE name = ").
+		WriteArg(name.String())
 }
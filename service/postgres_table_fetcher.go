@@ -23,11 +23,9 @@ func (p *postgresTableFetcher) FetchTableSchema(
 	projectId model.ProjectId,
 	name model.TableName,
 ) result.R[model.TableSchema] {
-	query := "SELECT schema " +
-		"FROM \"" + getPostgresSchemaTableName(projectId) + "\" " +
-		"WHERE name = '" + name.String() + "'"
+	query := getPostgresTableSchemaQuery(projectId, name)
 
-	rows, err := p.postgres.Query(query)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
 	if err != nil {
 		return result.Err[model.TableSchema](fmt.Errorf("error querying postgres: %w", err))
@@ -54,12 +52,44 @@ func (p *postgresTableFetcher) FetchTableSchema(
 	return result.Ok(schema)
 }
 
+// Locks the schema's row until the transaction ends, for when it's about to
+// be written back
+func fetchPostgresTableSchemaForUpdate(
+	tx *sql.Tx,
+	projectId model.ProjectId,
+	name model.TableName,
+) result.R[model.TableSchema] {
+	query := getPostgresTableSchemaForUpdateQuery(projectId, name)
+
+	schemaBytes := []byte{}
+
+	err := tx.QueryRow(query.String(), query.Args()...).Scan(&schemaBytes)
+
+	if err == sql.ErrNoRows {
+		return result.Err[model.TableSchema](errs.TableNotFoundError{})
+	}
+
+	if err != nil {
+		return result.Errf[model.TableSchema]("error querying postgres: %w", err)
+	}
+
+	schema := model.TableSchema{}
+
+	err = json.Unmarshal(schemaBytes, &schema)
+
+	if err != nil {
+		panic("error unmarshalling table schema")
+	}
+
+	return result.Ok(schema)
+}
+
 func (p *postgresTableFetcher) FetchTableSchemas(
 	projectId model.ProjectId,
 ) result.R[model.TableSchemas] {
-	query := fmt.Sprintf("SELECT name, schema FROM \"%s\"", getPostgresSchemaTableName(projectId))
+	query := getPostgresTableSchemasQuery(projectId)
 
-	rows, err := p.postgres.Query(query)
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
 
 	if err != nil {
 		return result.Err[model.TableSchemas](fmt.Errorf("error querying postgres: %w", err))
@@ -88,3 +118,21 @@ func (p *postgresTableFetcher) FetchTableSchemas(
 
 	return result.Ok(res)
 }
+
+func getPostgresTableSchemaQuery(projectId model.ProjectId, name model.TableName) *postgresQuery {
+	return newPostgresQuery().
+		Write("SELECT schema FROM ").
+		WriteIdentifier(getPostgresSchemaTableName(projectId)).
+		Write(" WHERE name = ").
+		WriteArg(name.String())
+}
+
+func getPostgresTableSchemaForUpdateQuery(projectId model.ProjectId, name model.TableName) *postgresQuery {
+	return getPostgresTableSchemaQuery(projectId, name).Write(" FOR UPDATE")
+}
+
+func getPostgresTableSchemasQuery(projectId model.ProjectId) *postgresQuery {
+	return newPostgresQuery().
+		Write("SELECT name, schema FROM ").
+		WriteIdentifier(getPostgresSchemaTableName(projectId))
+}
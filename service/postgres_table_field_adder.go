@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"crudly/errs"
 	"crudly/model"
 	"crudly/util"
 	"crudly/util/optional"
@@ -28,7 +29,7 @@ func (p *postgresTableFieldAdder) AddTableField(
 	definition model.FieldDefinition,
 	defaultValue optional.O[any],
 ) error {
-	tableQuery := ""
+	var tableQuery *postgresQuery
 
 	if definition.IsOptional {
 		tableQuery = getPostgresAddTableOptionalFieldQuery(
@@ -69,13 +70,24 @@ func (p *postgresTableFieldAdder) AddTableField(
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec(tableQuery)
+	_, err = tx.Exec(tableQuery.String(), tableQuery.Args()...)
 
 	if err != nil {
+		// Every existing entity gets the same default, so a unique field can
+		// only be added with a default while the table has at most one entity
+		if isPostgresError(err, postgresUniqueViolationCode) {
+			return errs.NewUniqueViolationError(getPostgresKeyViolationFields(err))
+		}
+
+		// The default has to be the id of an existing entity for references
+		if isPostgresError(err, postgresForeignKeyViolationCode) {
+			return errs.NewReferencedEntityNotFoundError(getPostgresKeyViolationFields(err))
+		}
+
 		return fmt.Errorf("unexpected error querying postgres: %w", err)
 	}
 
-	_, err = tx.Exec(schemaUpdateQuery)
+	_, err = tx.Exec(schemaUpdateQuery.String(), schemaUpdateQuery.Args()...)
 
 	if err != nil {
 		return fmt.Errorf("unexpected error querying postgres: %w", err)
@@ -95,13 +107,22 @@ func getPostgresAddTableOptionalFieldQuery(
 	tableName model.TableName,
 	name model.FieldName,
 	definition model.FieldDefinition,
-) string {
-	return fmt.Sprintf(
-		"ALTER TABLE \"%s\" ADD COLUMN \"%s\" %s",
-		getPostgresTableName(projectId, tableName),
-		name,
-		getPostgresDatatype(definition.Type),
-	)
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" ADD COLUMN ").
+		WriteIdentifier(name.String()).
+		Write(" " + getPostgresDatatype(definition))
+
+	if definition.Unique {
+		query.Write(" UNIQUE")
+	}
+
+	writePostgresFieldReference(query, projectId, definition)
+	writePostgresFieldCheck(query, name, definition)
+
+	return query
 }
 
 func getPostgresAddTableNonOptionalFieldQuery(
@@ -110,33 +131,43 @@ func getPostgresAddTableNonOptionalFieldQuery(
 	name model.FieldName,
 	definition model.FieldDefinition,
 	defaultValue any,
-) result.R[string] {
-	postgresFieldValueResult := getPostgresFieldValue(defaultValue)
+) result.R[*postgresQuery] {
+	postgresFieldLiteralResult := getPostgresFieldLiteral(defaultValue)
 
-	if postgresFieldValueResult.IsErr() {
-		return result.Errf[string]("couldnt get postgres field value for default value: %s", postgresFieldValueResult.UnwrapErr())
+	if postgresFieldLiteralResult.IsErr() {
+		return result.Errf[*postgresQuery]("couldnt get postgres field value for default value: %s", postgresFieldLiteralResult.UnwrapErr())
 	}
 
-	return result.Ok(
-		fmt.Sprintf(
-			"ALTER TABLE \"%s\" ADD COLUMN \"%s\" %s DEFAULT %s NOT NULL",
-			getPostgresTableName(projectId, tableName),
-			name,
-			getPostgresDatatype(definition.Type),
-			postgresFieldValueResult.Unwrap(),
-		),
-	)
+	// The default is an escaped literal rather than an argument as postgres
+	// doesn't accept placeholders in DDL
+	query := newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" ADD COLUMN ").
+		WriteIdentifier(name.String()).
+		Write(" " + getPostgresDatatype(definition)).
+		Write(" DEFAULT " + postgresFieldLiteralResult.Unwrap() + " NOT NULL")
+
+	if definition.Unique {
+		query.Write(" UNIQUE")
+	}
+
+	writePostgresFieldReference(query, projectId, definition)
+	writePostgresFieldCheck(query, name, definition)
+
+	return result.Ok(query)
 }
 
 func getPostgresTableSchemaUpdateQuery(
 	projectId model.ProjectId,
 	tableName model.TableName,
 	schema model.TableSchema,
-) string {
-	return fmt.Sprintf(
-		"UPDATE \"%s\" SET schema = '%s' WHERE name = '%s'",
-		getPostgresSchemaTableName(projectId),
-		getSchemaJson(schema),
-		tableName,
-	)
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("UPDATE ").
+		WriteIdentifier(getPostgresSchemaTableName(projectId)).
+		Write(" SET schema = ").
+		WriteArg(getSchemaJson(schema)).
+		Write(" WHERE name = ").
+		WriteArg(tableName.String())
 }
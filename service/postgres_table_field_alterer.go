@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"crudly/errs"
+	"crudly/model"
+	"crudly/util"
+	"crudly/util/optional"
+	"crudly/util/result"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type postgresTableFieldAlterer struct {
+	postgres *sql.DB
+}
+
+func NewPostgresTableFieldAlterer(postgres *sql.DB) postgresTableFieldAlterer {
+	return postgresTableFieldAlterer{
+		postgres,
+	}
+}
+
+// Each stored value is checked with checkStoredFieldValue before the field is
+// altered, in the same transaction. Unless it's a dry run the table is locked
+// against writes first, so no value can be written in between that isn't
+// checked. Values are streamed rather than read into memory, with only the
+// first failures kept
+func (p *postgresTableFieldAlterer) AlterField(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	existingSchema model.TableSchema,
+	name model.FieldName,
+	newDefinition model.FieldDefinition,
+	backfillValue optional.O[any],
+	dryRun bool,
+	checkStoredFieldValue func(storedFieldValue model.StoredFieldValue) error,
+) result.R[model.FieldAlteration] {
+	tx, err := p.postgres.BeginTx(context.Background(), nil)
+
+	if err != nil {
+		return result.Errf[model.FieldAlteration]("error opening postgres transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if !dryRun {
+		lockQuery := getPostgresTableWriteLockQuery(projectId, tableName)
+
+		_, err = tx.Exec(lockQuery.String(), lockQuery.Args()...)
+
+		if err != nil {
+			return result.Errf[model.FieldAlteration]("error locking postgres table: %w", err)
+		}
+
+		// The schema is written back whole, so it's read again with its row
+		// locked, keeping any fields added or renamed since it was first read
+		lockedSchemaResult := fetchPostgresTableSchemaForUpdate(tx, projectId, tableName)
+
+		if lockedSchemaResult.IsErr() {
+			return result.Err[model.FieldAlteration](lockedSchemaResult.UnwrapErr())
+		}
+
+		lockedSchema := lockedSchemaResult.Unwrap()
+		lockedDefinition, ok := lockedSchema[name]
+
+		if !ok {
+			return result.Err[model.FieldAlteration](errs.FieldNotFoundError{})
+		}
+
+		if !reflect.DeepEqual(lockedDefinition, existingSchema[name]) {
+			return result.Err[model.FieldAlteration](errs.NewInvalidFieldAlterationError(
+				fmt.Errorf("field: \"%s\" was changed while it was being altered", name),
+			))
+		}
+
+		existingSchema = lockedSchema
+	}
+
+	fieldAlterationResult := checkPostgresStoredFieldValues(
+		tx,
+		projectId,
+		tableName,
+		name,
+		existingSchema[name],
+		checkStoredFieldValue,
+	)
+
+	if fieldAlterationResult.IsErr() {
+		return fieldAlterationResult
+	}
+
+	fieldAlteration := fieldAlterationResult.Unwrap()
+	fieldAlteration.DryRun = dryRun
+
+	if dryRun || fieldAlteration.FailedCount > 0 {
+		return result.Ok(fieldAlteration)
+	}
+
+	queries := []*postgresQuery{
+		getPostgresFieldCheckDeletionQuery(projectId, tableName, name),
+	}
+
+	if getPostgresDatatype(existingSchema[name]) != getPostgresDatatype(newDefinition) {
+		// Defaults from when a field was added aren't needed once it exists,
+		// and may not be convertible to the new type
+		queries = append(
+			queries,
+			getPostgresFieldDefaultDeletionQuery(projectId, tableName, name),
+			getPostgresFieldTypeAlterationQuery(projectId, tableName, name, existingSchema[name], newDefinition),
+		)
+	}
+
+	if backfillValue.IsSome() {
+		backfillQueryResult := getPostgresFieldBackfillQuery(projectId, tableName, name, backfillValue.Unwrap())
+
+		if backfillQueryResult.IsErr() {
+			return result.Err[model.FieldAlteration](backfillQueryResult.UnwrapErr())
+		}
+
+		queries = append(queries, backfillQueryResult.Unwrap())
+	}
+
+	queries = append(queries, getPostgresFieldNullabilityQuery(projectId, tableName, name, newDefinition))
+
+	if len(getPostgresFieldCheckConditions(name, newDefinition)) > 0 {
+		queries = append(queries, getPostgresFieldCheckCreationQuery(projectId, tableName, name, newDefinition))
+	}
+
+	newSchema := util.CopyMap(existingSchema)
+	newSchema[name] = newDefinition
+
+	queries = append(queries, getPostgresTableSchemaUpdateQuery(projectId, tableName, newSchema))
+
+	for _, query := range queries {
+		_, err = tx.Exec(query.String(), query.Args()...)
+
+		if err != nil {
+			if isPostgresConversionError(err) {
+				return result.Err[model.FieldAlteration](errs.NewFieldConversionError(err))
+			}
+
+			return result.Errf[model.FieldAlteration]("error querying postgres: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return result.Errf[model.FieldAlteration]("error commiting postgres transaction: %w", err)
+	}
+
+	fieldAlteration.Applied = true
+
+	return result.Ok(fieldAlteration)
+}
+
+// The text of each value is what postgres casts it to as a string, which is
+// also what values are converted from when the field's type changes
+func checkPostgresStoredFieldValues(
+	tx *sql.Tx,
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+	definition model.FieldDefinition,
+	checkStoredFieldValue func(storedFieldValue model.StoredFieldValue) error,
+) result.R[model.FieldAlteration] {
+	query := getPostgresStoredFieldValuesQuery(projectId, tableName, name)
+
+	rows, err := tx.Query(query.String(), query.Args()...)
+
+	if err != nil {
+		return result.Errf[model.FieldAlteration]("error querying postgres: %w", err)
+	}
+
+	// The rows have to be closed before anything else is run in the transaction
+	defer rows.Close()
+
+	fieldAlteration := model.FieldAlteration{
+		Failures: []model.FieldConversionFailure{},
+	}
+
+	for rows.Next() {
+		text := sql.NullString{}
+
+		entityResult := parseEntityFromSqlRowAfter(rows, model.TableSchema{name: definition}, &text)
+
+		if entityResult.IsErr() {
+			return result.Err[model.FieldAlteration](entityResult.UnwrapErr())
+		}
+
+		entity := entityResult.Unwrap()
+
+		storedFieldValue := model.StoredFieldValue{
+			Id:    model.EntityId(entity["id"].(uuid.UUID)),
+			Value: entity[name],
+			Text:  optional.None[string](),
+		}
+
+		if text.Valid {
+			storedFieldValue.Text = optional.Some(text.String)
+		}
+
+		fieldAlteration.EntityCount++
+
+		err = checkStoredFieldValue(storedFieldValue)
+
+		if err == nil {
+			continue
+		}
+
+		fieldAlteration.FailedCount++
+
+		if len(fieldAlteration.Failures) < model.MaxFieldConversionFailures {
+			fieldAlteration.Failures = append(fieldAlteration.Failures, model.FieldConversionFailure{
+				Id:    storedFieldValue.Id,
+				Error: err.Error(),
+			})
+		}
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return result.Errf[model.FieldAlteration]("error reading postgres rows: %w", err)
+	}
+
+	return result.Ok(fieldAlteration)
+}
+
+// Data exceptions, e.g. text that can't be cast, and integrity violations,
+// e.g. nulls left in a field made required
+func isPostgresConversionError(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+
+	return ok && (pqErr.Code.Class() == "22" || pqErr.Code.Class() == "23")
+}
+
+// Reads are still allowed while the table is locked, and the lock is
+// strengthened by the alteration itself
+func getPostgresTableWriteLockQuery(projectId model.ProjectId, tableName model.TableName) *postgresQuery {
+	return newPostgresQuery().
+		Write("LOCK TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" IN SHARE ROW EXCLUSIVE MODE")
+}
+
+func getPostgresStoredFieldValuesQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("SELECT CAST(").
+		WriteIdentifier(name.String()).
+		Write(" AS varchar), ").
+		WriteIdentifier("id").
+		Write(", ").
+		WriteIdentifier(name.String()).
+		Write(" FROM ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" ORDER BY ").
+		WriteIdentifier("id")
+}
+
+func getPostgresFieldCheckDeletionQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" DROP CONSTRAINT IF EXISTS ").
+		WriteIdentifier(getPostgresFieldCheckName(name))
+}
+
+func getPostgresFieldCheckCreationQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+	definition model.FieldDefinition,
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" ADD")
+
+	writePostgresFieldCheck(query, name, definition)
+
+	return query
+}
+
+func getPostgresFieldDefaultDeletionQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" ALTER COLUMN ").
+		WriteIdentifier(name.String()).
+		Write(" DROP DEFAULT")
+}
+
+// Strings are read as numerics before being cast to integers, so that they
+// can be written with a fractional part or exponent as they can be in entities
+func getPostgresFieldTypeAlterationQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+	definition model.FieldDefinition,
+	newDefinition model.FieldDefinition,
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" ALTER COLUMN ").
+		WriteIdentifier(name.String()).
+		Write(" TYPE " + getPostgresDatatype(newDefinition) + " USING CAST(")
+
+	isStringDefinition := definition.Type == model.FieldTypeString || definition.Type == model.FieldTypeEnum
+	isIntegerDefinition := newDefinition.Type == model.FieldTypeInteger || newDefinition.Type == model.FieldTypeBigInt
+
+	if isStringDefinition && isIntegerDefinition {
+		return query.
+			Write("CAST(").
+			WriteIdentifier(name.String()).
+			Write(" AS numeric) AS " + getPostgresDatatype(newDefinition) + ")")
+	}
+
+	return query.
+		WriteIdentifier(name.String()).
+		Write(" AS " + getPostgresDatatype(newDefinition) + ")")
+}
+
+func getPostgresFieldBackfillQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+	backfillValue any,
+) result.R[*postgresQuery] {
+	postgresFieldValueResult := getPostgresFieldValue(backfillValue)
+
+	if postgresFieldValueResult.IsErr() {
+		return result.Errf[*postgresQuery]("couldnt get postgres field value for backfill value: %w", postgresFieldValueResult.UnwrapErr())
+	}
+
+	return result.Ok(newPostgresQuery().
+		Write("UPDATE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" SET ").
+		WriteIdentifier(name.String()).
+		Write(" = ").
+		WriteArg(postgresFieldValueResult.Unwrap()).
+		Write(" WHERE ").
+		WriteIdentifier(name.String()).
+		Write(" IS NULL"))
+}
+
+func getPostgresFieldNullabilityQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+	definition model.FieldDefinition,
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" ALTER COLUMN ").
+		WriteIdentifier(name.String())
+
+	if definition.IsOptional {
+		return query.Write(" DROP NOT NULL")
+	}
+
+	return query.Write(" SET NOT NULL")
+}
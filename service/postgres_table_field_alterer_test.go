@@ -0,0 +1,36 @@
+package service
+
+import "testing"
+
+func TestGetPostgresTableWriteLockQuery(t *testing.T) {
+	query := getPostgresTableWriteLockQuery(testProjectId, `posts"`)
+
+	assertPostgresQuery(
+		t,
+		query,
+		`LOCK TABLE "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-posts""" IN SHARE ROW EXCLUSIVE MODE`,
+		nil,
+	)
+}
+
+func TestGetPostgresStoredFieldValuesQuery(t *testing.T) {
+	query := getPostgresStoredFieldValuesQuery(testProjectId, "posts", `title"`)
+
+	assertPostgresQuery(
+		t,
+		query,
+		`SELECT CAST("title""" AS varchar), "id", "title""" FROM "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-table-posts" ORDER BY "id"`,
+		nil,
+	)
+}
+
+func TestGetPostgresTableSchemaForUpdateQuery(t *testing.T) {
+	query := getPostgresTableSchemaForUpdateQuery(testProjectId, testInjection)
+
+	assertPostgresQuery(
+		t,
+		query,
+		`SELECT schema FROM "6f1c1a2e-2d8a-4f2b-9b2a-3c7e4f5a6b7c-tables" WHERE name = $1 FOR UPDATE`,
+		[]any{testInjection},
+	)
+}
@@ -23,6 +23,7 @@ func (p *postgresTableFieldDeleter) DeleteField(
 	tableName model.TableName,
 	existingSchema model.TableSchema,
 	fieldName model.FieldName,
+	indexNames []model.IndexName,
 ) error {
 	deleteFieldQuery := getPostgresTableFieldDeleteQuery(
 		projectId,
@@ -37,7 +38,15 @@ func (p *postgresTableFieldDeleter) DeleteField(
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec(deleteFieldQuery)
+	for _, indexName := range indexNames {
+		err = deletePostgresIndex(tx, projectId, tableName, indexName)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(deleteFieldQuery.String(), deleteFieldQuery.Args()...)
 
 	if err != nil {
 		return fmt.Errorf("error querying postgres: %w", err)
@@ -52,7 +61,7 @@ func (p *postgresTableFieldDeleter) DeleteField(
 		newSchema,
 	)
 
-	_, err = tx.Exec(schemaUpdateQuery)
+	_, err = tx.Exec(schemaUpdateQuery.String(), schemaUpdateQuery.Args()...)
 
 	if err != nil {
 		return fmt.Errorf("error querying postgres: %w", err)
@@ -71,10 +80,10 @@ func getPostgresTableFieldDeleteQuery(
 	projectId model.ProjectId,
 	tableName model.TableName,
 	fieldName model.FieldName,
-) string {
-	return fmt.Sprintf(
-		"ALTER TABLE \"%s\" DROP COLUMN \"%s\"",
-		getPostgresTableName(projectId, tableName),
-		fieldName,
-	)
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" DROP COLUMN ").
+		WriteIdentifier(fieldName.String())
 }
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"crudly/model"
+	"crudly/util"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type postgresTableFieldRenamer struct {
+	postgres *sql.DB
+}
+
+func NewPostgresTableFieldRenamer(postgres *sql.DB) postgresTableFieldRenamer {
+	return postgresTableFieldRenamer{
+		postgres,
+	}
+}
+
+// Postgres carries indexes and constraints over to the renamed column, but
+// the field's check constraint is named after it and the rows recording the
+// table's indexes refer to it by name
+func (p *postgresTableFieldRenamer) RenameField(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	existingSchema model.TableSchema,
+	existingIndexes model.TableIndexes,
+	name model.FieldName,
+	newName model.FieldName,
+) error {
+	tx, err := p.postgres.BeginTx(context.Background(), nil)
+
+	if err != nil {
+		return fmt.Errorf("error opening postgres transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	renameFieldQuery := getPostgresTableFieldRenameQuery(projectId, tableName, name, newName)
+
+	_, err = tx.Exec(renameFieldQuery.String(), renameFieldQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	// Checks are only created for fields with constraints postgres can
+	// mirror, and not at all for fields added before they were supported
+	checkExistsQuery := getPostgresConstraintExistsQuery(projectId, tableName, getPostgresFieldCheckName(name))
+
+	checkExists := false
+
+	err = tx.QueryRow(checkExistsQuery.String(), checkExistsQuery.Args()...).Scan(&checkExists)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	if checkExists {
+		renameCheckQuery := getPostgresTableConstraintRenameQuery(
+			projectId,
+			tableName,
+			getPostgresFieldCheckName(name),
+			getPostgresFieldCheckName(newName),
+		)
+
+		_, err = tx.Exec(renameCheckQuery.String(), renameCheckQuery.Args()...)
+
+		if err != nil {
+			return fmt.Errorf("error querying postgres: %w", err)
+		}
+	}
+
+	for indexName, index := range existingIndexes {
+		if !index.ReferencesField(name) {
+			continue
+		}
+
+		indexUpdateQuery := getPostgresIndexRowUpdateQuery(
+			projectId,
+			tableName,
+			indexName,
+			index.RenameField(name, newName),
+		)
+
+		_, err = tx.Exec(indexUpdateQuery.String(), indexUpdateQuery.Args()...)
+
+		if err != nil {
+			return fmt.Errorf("error querying postgres: %w", err)
+		}
+	}
+
+	newSchema := util.CopyMap(existingSchema)
+	newSchema[newName] = newSchema[name]
+	delete(newSchema, name)
+
+	schemaUpdateQuery := getPostgresTableSchemaUpdateQuery(
+		projectId,
+		tableName,
+		newSchema,
+	)
+
+	_, err = tx.Exec(schemaUpdateQuery.String(), schemaUpdateQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return fmt.Errorf("error commiting postgres transaction: %w", err)
+	}
+
+	return nil
+}
+
+func getPostgresTableFieldRenameQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.FieldName,
+	newName model.FieldName,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" RENAME COLUMN ").
+		WriteIdentifier(name.String()).
+		Write(" TO ").
+		WriteIdentifier(newName.String())
+}
+
+func getPostgresConstraintExistsQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	constraintName string,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("SELECT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = CAST(").
+		WriteArg(pq.QuoteIdentifier(getPostgresTableName(projectId, tableName))).
+		Write(" AS regclass) AND conname = ").
+		WriteArg(constraintName).
+		Write(")")
+}
+
+func getPostgresTableConstraintRenameQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	constraintName string,
+	newConstraintName string,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" RENAME CONSTRAINT ").
+		WriteIdentifier(constraintName).
+		Write(" TO ").
+		WriteIdentifier(newConstraintName)
+}
+
+func getPostgresIndexRowUpdateQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.IndexName,
+	index model.TableIndex,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("UPDATE ").
+		WriteIdentifier(getPostgresIndexTableName(projectId)).
+		Write(" SET index = ").
+		WriteArg(getIndexJson(index)).
+		Write(" WHERE tablename = ").
+		WriteArg(tableName.String()).
+		Write(" AND name = ").
+		WriteArg(name.String())
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"crudly/errs"
+	"crudly/model"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+type postgresTableIndexCreator struct {
+	postgres *sql.DB
+}
+
+func NewPostgresTableIndexCreator(postgres *sql.DB) postgresTableIndexCreator {
+	return postgresTableIndexCreator{
+		postgres,
+	}
+}
+
+func (p *postgresTableIndexCreator) CreateIndex(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.IndexName,
+	index model.TableIndex,
+) error {
+	tx, err := p.postgres.BeginTx(context.Background(), nil)
+
+	if err != nil {
+		return fmt.Errorf("error opening postgres transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = createPostgresIndexes(tx, projectId, tableName, model.TableIndexes{name: index})
+
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return fmt.Errorf("error commiting postgres transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Creates the indexes along with the rows recording them, which also live
+// in a table per project
+func createPostgresIndexes(
+	tx *sql.Tx,
+	projectId model.ProjectId,
+	tableName model.TableName,
+	indexes model.TableIndexes,
+) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	// Projects created before indexes were supported don't have the table yet
+	indexTableCreationQuery := getPostgresIndexTableCreationQuery(projectId)
+
+	_, err := tx.Exec(indexTableCreationQuery.String(), indexTableCreationQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error creating postgres index table: %w", err)
+	}
+
+	for name, index := range indexes {
+		indexCreationQuery := getPostgresIndexCreationQuery(projectId, tableName, name, index)
+
+		_, err = tx.Exec(indexCreationQuery.String(), indexCreationQuery.Args()...)
+
+		if err != nil {
+			if isPostgresError(err, postgresUniqueViolationCode) {
+				return errs.NewUniqueViolationError(getPostgresKeyViolationFields(err))
+			}
+
+			return fmt.Errorf("error creating postgres index: %w", err)
+		}
+
+		indexInsertionQuery := getPostgresIndexInsertionQuery(projectId, tableName, name, index)
+
+		_, err = tx.Exec(indexInsertionQuery.String(), indexInsertionQuery.Args()...)
+
+		if err != nil {
+			return fmt.Errorf("error querying postgres: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func getPostgresIndexTableCreationQuery(projectId model.ProjectId) *postgresQuery {
+	return newPostgresQuery().
+		Write("CREATE TABLE IF NOT EXISTS ").
+		WriteIdentifier(getPostgresIndexTableName(projectId)).
+		Write("(tablename varchar, name varchar, index varchar)")
+}
+
+func getPostgresIndexCreationQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.IndexName,
+	index model.TableIndex,
+) *postgresQuery {
+	query := newPostgresLiteralQuery().Write("CREATE ")
+
+	if index.Unique {
+		query.Write("UNIQUE ")
+	}
+
+	query.
+		Write("INDEX ").
+		WriteIdentifier(getPostgresIndexName(projectId, tableName, name)).
+		Write(" ON ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName)).
+		Write(" (")
+
+	for i, fieldName := range index.Fields {
+		if i > 0 {
+			query.Write(", ")
+		}
+
+		query.WriteIdentifier(fieldName.String())
+	}
+
+	query.Write(")")
+
+	if index.Filter.IsSome() {
+		query.Write(" WHERE ")
+		writePostgresFilterExpression(query, index.Filter.Unwrap())
+	}
+
+	return query
+}
+
+func getPostgresIndexInsertionQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.IndexName,
+	index model.TableIndex,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("INSERT INTO ").
+		WriteIdentifier(getPostgresIndexTableName(projectId)).
+		Write("(tablename, name, index) VALUES (").
+		WriteArg(tableName.String()).
+		Write(", ").
+		WriteArg(name.String()).
+		Write(", ").
+		WriteArg(getIndexJson(index)).
+		Write(")")
+}
+
+func getIndexJson(index model.TableIndex) string {
+	json, err := json.Marshal(index)
+
+	if err != nil {
+		panic("error marshalling table index json")
+	}
+
+	return string(json)
+}
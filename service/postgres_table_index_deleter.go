@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"crudly/model"
+	"database/sql"
+	"fmt"
+)
+
+type postgresTableIndexDeleter struct {
+	postgres *sql.DB
+}
+
+func NewPostgresTableIndexDeleter(postgres *sql.DB) postgresTableIndexDeleter {
+	return postgresTableIndexDeleter{
+		postgres,
+	}
+}
+
+func (p *postgresTableIndexDeleter) DeleteIndex(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.IndexName,
+) error {
+	tx, err := p.postgres.BeginTx(context.Background(), nil)
+
+	if err != nil {
+		return fmt.Errorf("error opening postgres transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = deletePostgresIndex(tx, projectId, tableName, name)
+
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return fmt.Errorf("error commiting postgres transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Drops the index along with the row recording it
+func deletePostgresIndex(
+	tx *sql.Tx,
+	projectId model.ProjectId,
+	tableName model.TableName,
+	name model.IndexName,
+) error {
+	// The index is already gone if a field it used was deleted
+	indexDeletionQuery := newPostgresQuery().
+		Write("DROP INDEX IF EXISTS ").
+		WriteIdentifier(getPostgresIndexName(projectId, tableName, name))
+
+	_, err := tx.Exec(indexDeletionQuery.String(), indexDeletionQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	indexRowDeletionQuery := getPostgresIndexRowDeletionQuery(projectId, tableName).
+		Write(" AND name = ").
+		WriteArg(name.String())
+
+	_, err = tx.Exec(indexRowDeletionQuery.String(), indexRowDeletionQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	return nil
+}
+
+func getPostgresIndexRowDeletionQuery(projectId model.ProjectId, tableName model.TableName) *postgresQuery {
+	return newPostgresQuery().
+		Write("DELETE FROM ").
+		WriteIdentifier(getPostgresIndexTableName(projectId)).
+		Write(" WHERE tablename = ").
+		WriteArg(tableName.String())
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"crudly/model"
+	"crudly/util/result"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+type postgresTableIndexFetcher struct {
+	postgres *sql.DB
+}
+
+func NewPostgresTableIndexFetcher(postgres *sql.DB) postgresTableIndexFetcher {
+	return postgresTableIndexFetcher{
+		postgres,
+	}
+}
+
+func (p *postgresTableIndexFetcher) FetchTableIndexes(
+	projectId model.ProjectId,
+	tableName model.TableName,
+) result.R[model.TableIndexes] {
+	query := newPostgresQuery().
+		Write("SELECT name, index FROM ").
+		WriteIdentifier(getPostgresIndexTableName(projectId)).
+		Write(" WHERE tablename = ").
+		WriteArg(tableName.String())
+
+	rows, err := p.postgres.Query(query.String(), query.Args()...)
+
+	if err != nil {
+		// Only created once the project's first index is
+		if isPostgresError(err, postgresUndefinedTableCode) {
+			return result.Ok(model.TableIndexes{})
+		}
+
+		return result.Errf[model.TableIndexes]("error querying postgres: %w", err)
+	}
+
+	defer rows.Close()
+
+	indexes := model.TableIndexes{}
+
+	for rows.Next() {
+		name := ""
+		indexBytes := []byte{}
+
+		err = rows.Scan(&name, &indexBytes)
+
+		if err != nil {
+			return result.Errf[model.TableIndexes]("error scanning postgres row: %w", err)
+		}
+
+		index := model.TableIndex{}
+
+		err = json.Unmarshal(indexBytes, &index)
+
+		if err != nil {
+			panic(fmt.Sprintf("error unmarshalling table index: %s", err))
+		}
+
+		indexes[model.IndexName(name)] = index
+	}
+
+	return result.Ok(indexes)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crudly/model"
+	"database/sql"
+	"fmt"
+)
+
+type postgresTableRenamer struct {
+	postgres *sql.DB
+}
+
+func NewPostgresTableRenamer(postgres *sql.DB) postgresTableRenamer {
+	return postgresTableRenamer{
+		postgres,
+	}
+}
+
+// Foreign keys follow the renamed table, but index names are hashed from the
+// table name and so have to be renamed along with it. The schemas given are
+// those referencing the table, already updated to reference the new name, and
+// are keyed by the names the tables had before the rename
+func (p *postgresTableRenamer) RenameTable(
+	projectId model.ProjectId,
+	name model.TableName,
+	newName model.TableName,
+	existingIndexes model.TableIndexes,
+	referencingSchemas model.TableSchemas,
+) error {
+	tx, err := p.postgres.BeginTx(context.Background(), nil)
+
+	if err != nil {
+		return fmt.Errorf("error opening postgres transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	renameTableQuery := getPostgresTableRenameQuery(projectId, name, newName)
+
+	_, err = tx.Exec(renameTableQuery.String(), renameTableQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	for indexName := range existingIndexes {
+		renameIndexQuery := getPostgresIndexRenameQuery(projectId, name, newName, indexName)
+
+		_, err = tx.Exec(renameIndexQuery.String(), renameIndexQuery.Args()...)
+
+		if err != nil {
+			return fmt.Errorf("error querying postgres: %w", err)
+		}
+	}
+
+	// The rows recording indexes only exist once the project has had one
+	if len(existingIndexes) > 0 {
+		indexRowRenameQuery := getPostgresIndexRowRenameQuery(projectId, name, newName)
+
+		_, err = tx.Exec(indexRowRenameQuery.String(), indexRowRenameQuery.Args()...)
+
+		if err != nil {
+			return fmt.Errorf("error querying postgres: %w", err)
+		}
+	}
+
+	for tableName, schema := range referencingSchemas {
+		schemaUpdateQuery := getPostgresTableSchemaUpdateQuery(projectId, tableName, schema)
+
+		_, err = tx.Exec(schemaUpdateQuery.String(), schemaUpdateQuery.Args()...)
+
+		if err != nil {
+			return fmt.Errorf("error querying postgres: %w", err)
+		}
+	}
+
+	schemaRenameQuery := getPostgresTableSchemaRenameQuery(projectId, name, newName)
+
+	_, err = tx.Exec(schemaRenameQuery.String(), schemaRenameQuery.Args()...)
+
+	if err != nil {
+		return fmt.Errorf("error querying postgres: %w", err)
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return fmt.Errorf("error commiting postgres transaction: %w", err)
+	}
+
+	return nil
+}
+
+func getPostgresTableRenameQuery(
+	projectId model.ProjectId,
+	name model.TableName,
+	newName model.TableName,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, name)).
+		Write(" RENAME TO ").
+		WriteIdentifier(getPostgresTableName(projectId, newName))
+}
+
+func getPostgresIndexRenameQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	newTableName model.TableName,
+	indexName model.IndexName,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("ALTER INDEX ").
+		WriteIdentifier(getPostgresIndexName(projectId, tableName, indexName)).
+		Write(" RENAME TO ").
+		WriteIdentifier(getPostgresIndexName(projectId, newTableName, indexName))
+}
+
+func getPostgresIndexRowRenameQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	newTableName model.TableName,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("UPDATE ").
+		WriteIdentifier(getPostgresIndexTableName(projectId)).
+		Write(" SET tablename = ").
+		WriteArg(newTableName.String()).
+		Write(" WHERE tablename = ").
+		WriteArg(tableName.String())
+}
+
+func getPostgresTableSchemaRenameQuery(
+	projectId model.ProjectId,
+	name model.TableName,
+	newName model.TableName,
+) *postgresQuery {
+	return newPostgresQuery().
+		Write("UPDATE ").
+		WriteIdentifier(getPostgresSchemaTableName(projectId)).
+		Write(" SET name = ").
+		WriteArg(newName.String()).
+		Write(" WHERE name = ").
+		WriteArg(name.String())
+}
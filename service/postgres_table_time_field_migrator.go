@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"crudly/model"
+	"crudly/util/result"
+	"database/sql"
+	"sort"
+)
+
+type postgresTableTimeFieldMigrator struct {
+	postgres *sql.DB
+}
+
+func NewPostgresTableTimeFieldMigrator(postgres *sql.DB) postgresTableTimeFieldMigrator {
+	return postgresTableTimeFieldMigrator{
+		postgres,
+	}
+}
+
+// Time fields used to be stored as timestamp columns holding UTC. This
+// converts any that still are to timestamptz, keeping the same instants, and
+// returns the fields that were converted
+func (p *postgresTableTimeFieldMigrator) MigrateTimeFields(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	tableSchema model.TableSchema,
+) result.R[[]model.FieldName] {
+	tx, err := p.postgres.BeginTx(context.Background(), nil)
+
+	if err != nil {
+		return result.Errf[[]model.FieldName]("error opening postgres transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	legacyColumnsQuery := getPostgresLegacyTimeColumnsQuery(projectId, tableName)
+
+	rows, err := tx.Query(legacyColumnsQuery.String(), legacyColumnsQuery.Args()...)
+
+	if err != nil {
+		return result.Errf[[]model.FieldName]("error querying postgres: %w", err)
+	}
+
+	fieldNames := []model.FieldName{}
+
+	for rows.Next() {
+		var columnName string
+
+		err = rows.Scan(&columnName)
+
+		if err != nil {
+			rows.Close()
+			return result.Errf[[]model.FieldName]("error scanning postgres rows: %w", err)
+		}
+
+		fieldName := model.FieldName(columnName)
+
+		if fieldDefinition, ok := tableSchema[fieldName]; ok && fieldDefinition.Type == model.FieldTypeTime {
+			fieldNames = append(fieldNames, fieldName)
+		}
+	}
+
+	rows.Close()
+
+	if len(fieldNames) == 0 {
+		return result.Ok(fieldNames)
+	}
+
+	sort.Slice(fieldNames, func(i, j int) bool {
+		return fieldNames[i] < fieldNames[j]
+	})
+
+	migrateQuery := getPostgresTimeFieldMigrateQuery(projectId, tableName, fieldNames)
+
+	_, err = tx.Exec(migrateQuery.String(), migrateQuery.Args()...)
+
+	if err != nil {
+		return result.Errf[[]model.FieldName]("error querying postgres: %w", err)
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return result.Errf[[]model.FieldName]("error commiting postgres transaction: %w", err)
+	}
+
+	return result.Ok(fieldNames)
+}
+
+func getPostgresLegacyTimeColumnsQuery(projectId model.ProjectId, tableName model.TableName) *postgresQuery {
+	return newPostgresQuery().
+		Write("SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ").
+		WriteArg(getPostgresTableName(projectId, tableName)).
+		Write(" AND data_type = 'timestamp without time zone'")
+}
+
+func getPostgresTimeFieldMigrateQuery(
+	projectId model.ProjectId,
+	tableName model.TableName,
+	fieldNames []model.FieldName,
+) *postgresQuery {
+	query := newPostgresQuery().
+		Write("ALTER TABLE ").
+		WriteIdentifier(getPostgresTableName(projectId, tableName))
+
+	for i, fieldName := range fieldNames {
+		if i > 0 {
+			query.Write(",")
+		}
+
+		query.
+			Write(" ALTER COLUMN ").
+			WriteIdentifier(fieldName.String()).
+			Write(" TYPE timestamptz USING ").
+			WriteIdentifier(fieldName.String()).
+			Write(" AT TIME ZONE 'UTC'")
+	}
+
+	return query
+}
@@ -1,5 +1,11 @@
 package util
 
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
 func GetMapKeys[K comparable, V any](m map[K]V) []K {
 	keys := []K{}
 
@@ -10,6 +16,18 @@ func GetMapKeys[K comparable, V any](m map[K]V) []K {
 	return keys
 }
 
+// Map iteration order is random, so wherever the keys end up in generated
+// output they're sorted to keep it stable
+func GetSortedMapKeys[K constraints.Ordered, V any](m map[K]V) []K {
+	keys := GetMapKeys(m)
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
 func CopyMap[K comparable, V any](m map[K]V) map[K]V {
 	c := map[K]V{}
 	for k, v := range m {
@@ -2,20 +2,28 @@ package util
 
 import (
 	"crudly/util/result"
-	"strings"
 	"time"
 )
 
+// Times without an offset are taken to be UTC, as they always were before
+// offsets were accepted
 const IncomingTimeFormat = "2006-01-02T15:04:05"
 
+// Accepts RFC 3339 times with any offset and up to nanosecond fractional
+// seconds (stored to the microsecond), and returns them in UTC
 func ValidateIncomingTime(timeString string) result.R[time.Time] {
-	timeString = strings.TrimSuffix(timeString, "Z")
-
-	parsedTime, err := time.Parse(IncomingTimeFormat, timeString)
+	parsedTime, err := time.Parse(time.RFC3339Nano, timeString)
 
 	if err != nil {
-		return result.Err[time.Time](err)
+		// Fractional seconds are accepted here too, even though the format
+		// doesn't include them
+		var localErr error
+		parsedTime, localErr = time.Parse(IncomingTimeFormat, timeString)
+
+		if localErr != nil {
+			return result.Err[time.Time](err)
+		}
 	}
 
-	return result.Ok(parsedTime)
+	return result.Ok(parsedTime.UTC())
 }